@@ -9,6 +9,9 @@ import (
 	"hangry/seeder"
 	"hangry/usecase"
 	"os"
+	"strconv"
+	// promo schedules load their timezone, the runtime image has no zoneinfo
+	_ "time/tzdata"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -22,6 +25,9 @@ func main() {
 		Dsn: dbDsn,
 	})
 
+	// zero or unset means an order can use any number of promos
+	maxPromosPerOrder, _ := strconv.Atoi(os.Getenv("MAX_PROMOS_PER_ORDER"))
+
 	flag.Parse()
 	args := flag.Args()
 
@@ -40,6 +46,7 @@ func main() {
 	promoRepo := repo.NewPromoRepository(db)
 	orderRepo := repo.NewOrderRepository(db)
 	userRepo := repo.NewUserRepository(db)
+	voucherRepo := repo.NewVoucherRepository(db)
 
 	// this repo is for managing transaction
 	transactionRepo := repo.NewTransactionRepository(db)
@@ -50,13 +57,15 @@ func main() {
 		cartRepo,
 		productRepo,
 	)
-	promoUsecase := usecase.NewPromoUsecase(promoRepo, transactionRepo, cartRepo, productRepo)
-	orderUsecase := usecase.NewOrderUsecase(transactionRepo, orderRepo, userRepo, cartRepo, promoRepo)
+	promoUsecase := usecase.NewPromoUsecase(promoRepo, transactionRepo, cartRepo, productRepo, userRepo, maxPromosPerOrder)
+	orderUsecase := usecase.NewOrderUsecase(transactionRepo, orderRepo, userRepo, cartRepo, promoRepo, voucherRepo, maxPromosPerOrder)
+	voucherUsecase := usecase.NewVoucherUsecase(transactionRepo, promoRepo, voucherRepo)
 
 	var server generated.ServerInterface = handler.NewServer(
 		cartUsecase,
 		promoUsecase,
 		orderUsecase,
+		voucherUsecase,
 	)
 
 	generated.RegisterHandlers(e, server)
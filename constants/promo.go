@@ -1,11 +1,44 @@
 package constants
 
 const (
-	PROMOTYPEBUYXGETY   = "BUY_X_GET_Y_FREE"
-	PROMOTYPEPERCENTAGE = "PERCENTAGE_DISCOUNT"
+	PROMOTYPEBUYXGETY    = "BUY_X_GET_Y_FREE"
+	PROMOTYPEPERCENTAGE  = "PERCENTAGE_DISCOUNT"
+	PROMOTYPEFIXEDAMOUNT = "FIXED_AMOUNT_DISCOUNT"
+	PROMOTYPETIERED      = "TIERED_DISCOUNT"
 
-	PROMOSEGMENTATIONCITY      = "CITY"
-	PROMOSEGMENTATIONLOYALUSER = "LOYAL_USER"
-	PROMOSEGMENTATIONNEWUSER   = "NEW_USER"
-	PROMOSEGMENTATIONALL       = "ALL"
+	PROMOTIERPERCENTAGE = "PERCENTAGE"
+	PROMOTIERFIXED      = "FIXED"
+
+	PROMOSEGMENTATIONCITY       = "CITY"
+	PROMOSEGMENTATIONLOYALUSER  = "LOYAL_USER"
+	PROMOSEGMENTATIONNEWUSER    = "NEW_USER"
+	PROMOSEGMENTATIONALL        = "ALL"
+	PROMOSEGMENTATIONFIRSTORDER = "FIRST_ORDER"
+	PROMOSEGMENTATIONLAPSEDUSER = "LAPSED_USER"
+	PROMOSEGMENTATIONNTHORDER   = "NTH_ORDER"
+	PROMOSEGMENTATIONUSERLIST   = "USER_LIST"
+
+	// a promo starts as a DRAFT and needs to be APPROVED before it can be
+	// redeemed, ENDED and ARCHIVED promos are never redeemed again
+	PROMOSTATUSDRAFT           = "DRAFT"
+	PROMOSTATUSPENDINGAPPROVAL = "PENDING_APPROVAL"
+	PROMOSTATUSAPPROVED        = "APPROVED"
+	PROMOSTATUSREJECTED        = "REJECTED"
+	PROMOSTATUSPAUSED          = "PAUSED"
+	PROMOSTATUSENDED           = "ENDED"
+	PROMOSTATUSARCHIVED        = "ARCHIVED"
+
+	// what a promo audit log entry records
+	PROMOAUDITACTIONCREATE       = "CREATE"
+	PROMOAUDITACTIONEXTEND       = "EXTEND"
+	PROMOAUDITACTIONUPDATE       = "UPDATE"
+	PROMOAUDITACTIONADDCITIES    = "ADD_CITIES"
+	PROMOAUDITACTIONREMOVECITY   = "REMOVE_CITY"
+	PROMOAUDITACTIONADDUSERS     = "ADD_USERS"
+	PROMOAUDITACTIONREMOVEUSERS  = "REMOVE_USERS"
+	PROMOAUDITACTIONCHANGESTATUS = "CHANGE_STATUS"
+
+	// PROMODEFAULTTIMEZONE is where the business runs, promo schedules are
+	// read in it unless the promo sets its own timezone
+	PROMODEFAULTTIMEZONE = "Asia/Jakarta"
 )
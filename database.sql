@@ -23,10 +23,10 @@ CREATE TABLE promos (
     id SERIAL PRIMARY KEY,
     name VARCHAR(255) NOT NULL,
     description TEXT,
-    segmentation VARCHAR(255) NOT NULL CHECK (segmentation IN ('ALL', 'LOYAL_USER', 'NEW_USER', 'CITY')),
-    type VARCHAR(50) NOT NULL CHECK (type IN ('PERCENTAGE_DISCOUNT', 'BUY_X_GET_Y_FREE')),
-    min_order_amount NUMERIC(10, 2), -- Minimum order amount for percentage discounts
-    discount_value NUMERIC(10, 2), -- For percentage discount, this is the percentage value
+    segmentation VARCHAR(255) NOT NULL, -- Validated against the eligibility rules registered in the API
+    type VARCHAR(50) NOT NULL CHECK (type IN ('PERCENTAGE_DISCOUNT', 'FIXED_AMOUNT_DISCOUNT', 'BUY_X_GET_Y_FREE', 'TIERED_DISCOUNT')),
+    min_order_amount NUMERIC(10, 2), -- Minimum order amount for percentage and fixed amount discounts
+    discount_value NUMERIC(10, 2), -- For percentage discount, this is the percentage value, for fixed amount discount the amount taken off
     max_discount_amount NUMERIC(10, 2), -- Maximum discount amount for percentage discounts
     buy_product_id INT, -- For "Buy X, Get Y Free"
     free_product_id INT, -- For "Buy X, Get Y Free"
@@ -36,6 +36,25 @@ CREATE TABLE promos (
     end_date TIMESTAMP WITH TIME ZONE NOT NULL,
     max_usage_limit INT, -- Maximum number of times this promotion can be used
     current_usage_count INT DEFAULT 0, -- Tracks how many times the promotion has been used
+    max_usage_per_user INT, -- Maximum number of times a single user can use this promotion
+    max_budget_amount NUMERIC(12, 2), -- Total amount the promotion may give away in discounts and free items
+    spent_amount NUMERIC(12, 2) DEFAULT 0, -- Tracks how much the promotion has given away
+    code VARCHAR(50) UNIQUE, -- Voucher code customers type to redeem the promotion, stored uppercase
+    is_code_only BOOLEAN DEFAULT FALSE, -- Hidden from the promo list, redeemable by code only
+    is_repeatable BOOLEAN DEFAULT FALSE, -- For "Buy X, Get Y Free", reward every multiple of buy_product_qty
+    max_repeat INT, -- For repeatable "Buy X, Get Y Free", maximum times the reward is granted per order
+    is_exclusive BOOLEAN DEFAULT FALSE, -- Can not be combined with any other promotion
+    stack_group VARCHAR(50), -- Only one promotion per stack group can be applied to an order
+    priority INT DEFAULT 0, -- Promotions with a higher priority are applied first
+    timezone VARCHAR(64) NOT NULL DEFAULT 'Asia/Jakarta', -- IANA timezone the promotion schedules are read in
+    status VARCHAR(20) NOT NULL DEFAULT 'DRAFT' CHECK (status IN ('DRAFT', 'PENDING_APPROVAL', 'APPROVED', 'REJECTED', 'PAUSED', 'ENDED', 'ARCHIVED')), -- Only approved promotions can be redeemed, archived ones are kept for the order history
+    reviewed_by VARCHAR(255), -- Who approved or rejected the promotion last
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    rejection_reason TEXT, -- Why the promotion was rejected, empty once approved
+    new_user_days INT, -- For NEW_USER, days after registering a user counts as new, one month when empty
+    lapsed_days INT, -- For LAPSED_USER, days the user must have gone without ordering
+    order_number INT, -- For NTH_ORDER, which of the user's orders the promotion is for, counting from 1
+    rollout_percentage INT CHECK (rollout_percentage BETWEEN 1 AND 100), -- Share of users the promotion is rolled out to, by a stable hash of user and promotion, everyone when empty
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
@@ -50,6 +69,103 @@ CREATE TABLE promo_cities (
     FOREIGN KEY (promo_id) REFERENCES promos(id)
 );
 
+-- Table: promo_users
+CREATE TABLE promo_users (
+    id SERIAL PRIMARY KEY,
+    promo_id INT NOT NULL,
+    user_id INT NOT NULL, -- A USER_LIST promotion is only available to the listed users
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (promo_id) REFERENCES promos(id),
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    UNIQUE (promo_id, user_id)
+);
+
+-- Table: promo_schedules
+CREATE TABLE promo_schedules (
+    id SERIAL PRIMARY KEY,
+    promo_id INT NOT NULL,
+    day_of_week INT NOT NULL CHECK (day_of_week BETWEEN 0 AND 6), -- 0 is Sunday
+    start_time VARCHAR(5) NOT NULL, -- HH:MM in the promotion timezone
+    end_time VARCHAR(5) NOT NULL, -- HH:MM, a window not ending after it starts runs past midnight
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (promo_id) REFERENCES promos(id)
+);
+
+-- Table: promo_products
+CREATE TABLE promo_products (
+    id SERIAL PRIMARY KEY,
+    promo_id INT NOT NULL,
+    product_id INT NOT NULL, -- A percentage discount with products is taken from these cart lines only
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (promo_id) REFERENCES promos(id),
+    FOREIGN KEY (product_id) REFERENCES products(id)
+);
+
+-- Table: promo_tiers
+CREATE TABLE promo_tiers (
+    id SERIAL PRIMARY KEY,
+    promo_id INT NOT NULL,
+    min_amount NUMERIC(10, 2) NOT NULL, -- Cart subtotal the tier starts at, the highest tier reached is applied
+    discount_type VARCHAR(20) NOT NULL CHECK (discount_type IN ('PERCENTAGE', 'FIXED')),
+    discount_value NUMERIC(10, 2) NOT NULL,
+    max_discount_amount NUMERIC(10, 2), -- Cap of a percentage tier
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (promo_id) REFERENCES promos(id)
+);
+
+-- Table: promo_variants
+CREATE TABLE promo_variants (
+    id SERIAL PRIMARY KEY,
+    promo_id INT NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    weight INT NOT NULL DEFAULT 1, -- Share of the rolled out users assigned to the variant, relative to the other variants
+    discount_value NUMERIC(10, 2) NOT NULL, -- Replaces the discount value of the promotion
+    max_discount_amount NUMERIC(10, 2), -- Replaces the cap of a percentage discount
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (promo_id) REFERENCES promos(id),
+    UNIQUE (promo_id, name)
+);
+
+-- Table: promo_audit_logs
+CREATE TABLE promo_audit_logs (
+    id SERIAL PRIMARY KEY,
+    promo_id INT NOT NULL,
+    action VARCHAR(50) NOT NULL, -- What was done to the promotion, e.g. EXTEND or CHANGE_STATUS
+    actor VARCHAR(255), -- Who made the change, from the X-Actor header
+    changes JSONB NOT NULL, -- Every changed field with its value before and after
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (promo_id) REFERENCES promos(id)
+);
+
+-- promo_audit_logs is append-only, an entry is never changed once written
+CREATE FUNCTION promo_audit_logs_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'promo_audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER promo_audit_logs_append_only
+    BEFORE UPDATE OR DELETE ON promo_audit_logs
+    FOR EACH ROW EXECUTE FUNCTION promo_audit_logs_append_only();
+
+-- Table: promo_vouchers
+CREATE TABLE promo_vouchers (
+    id SERIAL PRIMARY KEY,
+    promo_id INT NOT NULL,
+    code VARCHAR(50) NOT NULL UNIQUE, -- Single-use code, stored uppercase
+    redeemed_by_user_id INT,
+    redeemed_order_id INT,
+    redeemed_at TIMESTAMP WITH TIME ZONE, -- Set once the code is consumed by an order
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (promo_id) REFERENCES promos(id)
+);
+
 -- Table: orders
 CREATE TABLE orders (
     id SERIAL PRIMARY KEY,
@@ -82,10 +198,25 @@ CREATE TABLE order_promos (
     discount_amount NUMERIC(10, 2),
     free_product_id INT,
     free_product_qty INT,
+    promo_tier_id INT, -- Tier a tiered discount was applied with
+    promo_variant_id INT, -- Variant the user was assigned to, for analysing A/B tests
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY (order_id) REFERENCES orders(id),
-    FOREIGN KEY (promo_id) REFERENCES promos(id)    
+    FOREIGN KEY (promo_id) REFERENCES promos(id),
+    FOREIGN KEY (promo_tier_id) REFERENCES promo_tiers(id),
+    FOREIGN KEY (promo_variant_id) REFERENCES promo_variants(id)
+);
+
+-- Table: order_promo_items
+CREATE TABLE order_promo_items (
+    id SERIAL PRIMARY KEY,
+    order_promo_id INT NOT NULL,
+    order_item_id INT NOT NULL, -- Order item a product scoped discount was taken from
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (order_promo_id) REFERENCES order_promos(id),
+    FOREIGN KEY (order_item_id) REFERENCES order_items(id)
 );
 
 -- table: carts
@@ -115,11 +246,19 @@ CREATE INDEX idx_users_city ON users(city);
 CREATE INDEX idx_products_name ON products(name);
 CREATE INDEX idx_promos_dates ON promos(start_date, end_date);
 CREATE INDEX idx_promos_segmentation ON promos(segmentation);
+CREATE INDEX idx_promos_status ON promos(status);
 CREATE INDEX idx_promo_cities_promo_city ON promo_cities(promo_id, city);
+CREATE INDEX idx_promo_schedules_promo_id ON promo_schedules(promo_id);
+CREATE INDEX idx_promo_products_promo_id ON promo_products(promo_id);
+CREATE INDEX idx_promo_tiers_promo_id ON promo_tiers(promo_id);
+CREATE INDEX idx_promo_variants_promo_id ON promo_variants(promo_id);
+CREATE INDEX idx_promo_vouchers_promo_id ON promo_vouchers(promo_id);
+CREATE INDEX idx_promo_audit_logs_promo_id ON promo_audit_logs(promo_id);
 CREATE INDEX idx_orders_user_id ON orders(user_id);
 CREATE INDEX idx_orders_created_at ON orders(created_at);
 CREATE INDEX idx_order_items_order_id ON order_items(order_id);
 CREATE INDEX idx_order_items_product_id ON order_items(product_id);
 CREATE INDEX idx_order_promos_order_promo ON order_promos(order_id, promo_id);
+CREATE INDEX idx_order_promo_items_order_promo_id ON order_promo_items(order_promo_id);
 CREATE INDEX idx_cart_items_cart_id ON cart_items(cart_id);
 CREATE INDEX idx_cart_items_product_id ON cart_items(product_id);
\ No newline at end of file
@@ -40,6 +40,16 @@ func (o *orderRepository) MakeOrder(ctx context.Context, tx *gorm.DB, order *mod
 	return nil
 }
 
+// SaveOrderPromoItems implements repository.OrderRepository.
+func (o *orderRepository) SaveOrderPromoItems(ctx context.Context, tx *gorm.DB, items []models.OrderPromoItem) error {
+	db := tx
+	if db == nil {
+		db = o.db.WithContext(ctx)
+	}
+
+	return db.Create(&items).Error
+}
+
 func NewOrderRepository(db *gorm.DB) repository.OrderRepository {
 	return &orderRepository{db: db}
 }
@@ -204,3 +204,89 @@ func Test_orderRepository_MakeOrder(t *testing.T) {
 		})
 	}
 }
+
+func Test_orderRepository_SaveOrderPromoItems(t *testing.T) {
+	timeNow := time.Now()
+
+	type args struct {
+		ctx   context.Context
+		tx    *gorm.DB
+		items []models.OrderPromoItem
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+		sqlMock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			args: args{
+				ctx: context.Background(),
+				tx:  nil,
+				items: []models.OrderPromoItem{
+					{OrderPromoID: 1, OrderItemID: 2},
+					{OrderPromoID: 1, OrderItemID: 3},
+				},
+			},
+			wantErr: false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "order_promo_items" ("order_promo_id","order_item_id") VALUES ($1,$2),($3,$4) RETURNING "created_at","updated_at","id"`)).
+					WithArgs(1, 2, 1, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at", "id"}).
+						AddRow(timeNow, timeNow, 1).
+						AddRow(timeNow, timeNow, 2))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "failed to insert order promo items",
+			args: args{
+				ctx: context.Background(),
+				tx:  nil,
+				items: []models.OrderPromoItem{
+					{OrderPromoID: 1, OrderItemID: 2},
+				},
+			},
+			wantErr: true,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "order_promo_items" ("order_promo_id","order_item_id") VALUES ($1,$2) RETURNING "created_at","updated_at","id"`)).
+					WithArgs(1, 2).
+					WillReturnError(errors.New("error"))
+				mock.ExpectRollback()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to open sqlmock database: %v", err)
+			}
+			defer sqlDB.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{
+				Conn: sqlDB,
+			}), &gorm.Config{
+				Logger: logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				t.Fatalf("failed to open gorm database: %v", err)
+			}
+
+			tt.sqlMock(mock)
+
+			o := NewOrderRepository(gormDB)
+
+			if err := o.SaveOrderPromoItems(tt.args.ctx, tt.args.tx, tt.args.items); (err != nil) != tt.wantErr {
+				t.Errorf("orderRepository.SaveOrderPromoItems() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -3,11 +3,13 @@ package db
 import (
 	"context"
 	"database/sql"
-	"fmt"
+	"hangry/constants"
 	"hangry/domain/models"
 	"hangry/repository"
+	"strings"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type promoRepostory struct {
@@ -15,118 +17,191 @@ type promoRepostory struct {
 }
 
 // GetPromoByUserCart implements repository.PromoRepository.
-func (r *promoRepostory) GetPromoByUserCart(ctx context.Context, tx *gorm.DB, input repository.GetPromoByUserCartInput) ([]models.Promo, int64, error) {
-
-	baseQuery := `
-		with 
-		user_data as (
-				select 
-						*
-				from users u 
-				where u.id = @userId
-		),
-		items as (
-				select
-						p.price,
-						ci.*
-				from cart_items ci 
-				join carts c on c.id = ci.cart_id
-				join products p on p.id = ci.product_id 
-				where c.user_id  = @userId
-		),
-		summary as (
-				select
-						sum(i.price * i.quantity) total
-				from items i
-		)
-		select
-				%[1]v
-		from promos p
-		where (
-						(
-								p."type" = 'PERCENTAGE_DISCOUNT' 
-								and (select total from summary) >= p.min_order_amount 
-						)
-						or (
-								p."type" = 'BUY_X_GET_Y_FREE' 
-								and (select 
-										coalesce(i.quantity,0)
-										from items i
-										where i.product_id = p.buy_product_id 
-								) >= p.buy_product_qty 
-						)
-				)
-				and (
-						p.segmentation = 'ALL'
-						or (
-								p.segmentation = 'CITY' 
-								and lower((select city from user_data)) in (select lower(city) from promo_cities pc where pc.promo_id = p.id)
-						)
-						or (
-								p.segmentation = 'LOYAL_USER'
-								and (select is_loyal from user_data) = true
-						)
-						or (
-								p.segmentation = 'NEW_USER'
-								and (select created_at from user_data) > (current_timestamp - interval '1 month')
-						)
-				)
-		%[2]v
-		%[3]v
-		%[4]v;
-	`
-
-	selectFields := `
-		p.*
-	`
-
-	additionalCondition := ""
+func (r *promoRepostory) GetPromoByUserCart(ctx context.Context, tx *gorm.DB, input repository.GetPromoByUserCartInput) ([]models.Promo, error) {
+	// a promo with single-use vouchers is only redeemed by consuming one of
+	// them, never by its id, its reusable code or from the list
+	withoutVouchers := "not exists (select 1 from promo_vouchers pv where pv.promo_id = promos.id)"
+
+	// only the named arguments the condition uses are passed, gorm binds
+	// them by position when the condition has none
+	selectors := []string{}
+	args := []interface{}{}
 	if len(input.PromoIds) > 0 {
-		additionalCondition += " and p.id in @promoIds"
+		selectors = append(selectors, "promos.id in @promoIds and promos.is_code_only = false and "+withoutVouchers)
+		args = append(args, sql.Named("promoIds", input.PromoIds))
+	}
+	if len(input.PromoCodes) > 0 {
+		selectors = append(selectors, "promos.code in @promoCodes and "+withoutVouchers)
+		args = append(args, sql.Named("promoCodes", input.PromoCodes))
+	}
+	if len(input.VoucherPromoIds) > 0 {
+		selectors = append(selectors, "promos.id in @voucherPromoIds")
+		args = append(args, sql.Named("voucherPromoIds", input.VoucherPromoIds))
 	}
 
-	if input.IsAvailable != nil && *input.IsAvailable {
-		additionalCondition += " and p.start_date <= current_timestamp and p.end_date >= current_timestamp and p.current_usage_count < p.max_usage_limit"
+	var condition string
+	switch len(selectors) {
+	case 0:
+		// code-only promos are never listed
+		condition = "promos.is_code_only = false and " + withoutVouchers
+	case 1:
+		condition = selectors[0]
+	default:
+		condition = "((" + strings.Join(selectors, ") or (") + "))"
 	}
 
-	limit := ""
-	if input.Page != nil && input.PerPage != nil {
-		offset := (*input.Page - 1) * *input.PerPage
-		limit = fmt.Sprintf(" limit %d offset %d", *input.PerPage, offset)
+	if input.IsAvailable != nil && *input.IsAvailable {
+		condition += " and promos.start_date <= current_timestamp and promos.end_date >= current_timestamp"
+		// orders move the usage and the spent amount. A locking read that
+		// waited for another order re-checks its conditions and would drop a
+		// promo used up meanwhile, so the caller checks them on the locked row.
+		if !input.ForUpdate {
+			condition += " and (promos.max_usage_limit is null or promos.current_usage_count < promos.max_usage_limit)"
+			// a budget that can not cover one more free item is used up as
+			// well, the promo would be redeemed without giving anything
+			condition += " and (promos.max_budget_amount is null or (promos.spent_amount < promos.max_budget_amount and (promos.free_product_id is null or promos.spent_amount + (select products.price from products where products.id = promos.free_product_id) <= promos.max_budget_amount)))"
+		}
+		condition += " and promos.status = '" + constants.PROMOSTATUSAPPROVED + "'"
 	}
 
-	groupBy := " group by p.id"
+	if input.WithinUserLimit != nil && *input.WithinUserLimit {
+		condition += " and (promos.max_usage_per_user is null or (select count(*) from order_promos op join orders o on o.id = op.order_id where op.promo_id = promos.id and o.user_id = @userId) < promos.max_usage_per_user)"
+		args = append(args, sql.Named("userId", input.Cart.UserID))
+	}
 
-	query := fmt.Sprintf(baseQuery, selectFields, additionalCondition, groupBy, limit)
 	db := tx
 	if db == nil {
 		db = r.db.WithContext(ctx)
 	}
 
+	// segmentation and type eligibility is decided by the eligibility rules,
+	// the query only narrows down the candidates
+	if input.ForUpdate {
+		// the promos are locked in id order, so orders never wait on each other
+		// the other way round
+		db = db.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+
 	var promos []models.Promo
+	if err := db.Preload("FreeProduct").Preload("PromoCities").Preload("PromoSchedules").Preload("PromoProducts").Preload("PromoTiers").
+		Preload("PromoUsers", "user_id = ?", input.Cart.UserID).
+		// users are assigned to variants in this order
+		Preload("PromoVariants", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id")
+		}).
+		Where(condition, args...).
+		Order("promos.id").
+		Find(&promos).Error; err != nil {
+		return nil, err
+	}
+
+	return promos, nil
+}
+
+// GetPromoByPromoID implements repository.PromoRepository.
+func (r *promoRepostory) GetPromoByPromoID(ctx context.Context, tx *gorm.DB, promoID uint) (models.Promo, error) {
+	db := tx
+	if db == nil {
+		db = r.db.WithContext(ctx)
+	}
+
+	var promo models.Promo
+	if err := db.Where("id = ?", promoID).First(&promo).Error; err != nil {
+		return promo, err
+	}
+
+	return promo, nil
+}
+
+// GetPromoForUpdate implements repository.PromoRepository.
+func (r *promoRepostory) GetPromoForUpdate(ctx context.Context, tx *gorm.DB, promoID uint) (models.Promo, error) {
+	db := tx
+	if db == nil {
+		db = r.db.WithContext(ctx)
+	}
+
+	var promo models.Promo
+	if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", promoID).First(&promo).Error; err != nil {
+		return promo, err
+	}
+
+	return promo, nil
+}
+
+// GetPromoDetail implements repository.PromoRepository.
+func (r *promoRepostory) GetPromoDetail(ctx context.Context, tx *gorm.DB, promoID uint) (models.Promo, error) {
+	db := tx
+	if db == nil {
+		db = r.db.WithContext(ctx)
+	}
+
+	var promo models.Promo
+	if err := db.Preload("PromoCities", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Preload("PromoProducts", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Preload("PromoSchedules", func(db *gorm.DB) *gorm.DB {
+		return db.Order("day_of_week, start_time")
+	}).Preload("PromoTiers", func(db *gorm.DB) *gorm.DB {
+		return db.Order("min_amount")
+	}).Preload("PromoVariants", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Where("id = ?", promoID).First(&promo).Error; err != nil {
+		return promo, err
+	}
+
+	return promo, nil
+}
+
+// ListPromos implements repository.PromoRepository.
+func (r *promoRepostory) ListPromos(ctx context.Context, tx *gorm.DB, input repository.ListPromosInput) ([]models.Promo, int64, error) {
+	db := tx
+	if db == nil {
+		db = r.db.WithContext(ctx)
+	}
 
-	if err := db.Raw(query, sql.Named("userId", input.Cart.UserID), sql.Named("promoIds", input.PromoIds)).Scan(&promos).Error; err != nil {
+	query := db.Model(&models.Promo{})
+	if input.Status != "" {
+		query = query.Where("status = ?", input.Status)
+	} else {
+		// archived promos are only kept for the order history
+		query = query.Where("status <> ?", constants.PROMOSTATUSARCHIVED)
+	}
+	if input.Type != "" {
+		query = query.Where("type = ?", input.Type)
+	}
+	if input.Segmentation != "" {
+		query = query.Where("segmentation = ?", input.Segmentation)
+	}
+	if !input.StartDate.IsZero() {
+		query = query.Where("end_date >= ?", input.StartDate)
+	}
+	if !input.EndDate.IsZero() {
+		query = query.Where("start_date <= ?", input.EndDate)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	countQuery := fmt.Sprintf(baseQuery, "count(*)", additionalCondition, "", "")
-	var count int64
-	if err := db.Raw(countQuery, sql.Named("userId", input.Cart.UserID), sql.Named("promoIds", input.PromoIds)).Scan(&count).Error; err != nil {
+	var promos []models.Promo
+	if err := query.Order("id").Offset((input.Page - 1) * input.PerPage).Limit(input.PerPage).Find(&promos).Error; err != nil {
 		return nil, 0, err
 	}
 
-	return promos, count, nil
+	return promos, total, nil
 }
 
-// GetPromoByPromoID implements repository.PromoRepository.
-func (r *promoRepostory) GetPromoByPromoID(ctx context.Context, tx *gorm.DB, promoID uint) (models.Promo, error) {
+// GetPromoByCode implements repository.PromoRepository.
+func (r *promoRepostory) GetPromoByCode(ctx context.Context, tx *gorm.DB, code string) (models.Promo, error) {
 	db := tx
 	if db == nil {
 		db = r.db.WithContext(ctx)
 	}
 
 	var promo models.Promo
-	if err := db.Where("id = ?", promoID).First(&promo).Error; err != nil {
+	if err := db.Where("code = ?", code).First(&promo).Error; err != nil {
 		return promo, err
 	}
 
@@ -150,6 +225,269 @@ func (r *promoRepostory) SaveCities(ctx context.Context, tx *gorm.DB, promoID ui
 	return db.Create(&pc).Error
 }
 
+// GetCities implements repository.PromoRepository.
+func (r *promoRepostory) GetCities(ctx context.Context, tx *gorm.DB, promoID uint) ([]models.PromoCity, error) {
+	db := tx
+	if db == nil {
+		db = r.db.WithContext(ctx)
+	}
+
+	var cities []models.PromoCity
+	if err := db.Where("promo_id = ?", promoID).Order("id").Find(&cities).Error; err != nil {
+		return nil, err
+	}
+
+	return cities, nil
+}
+
+// RemoveCity implements repository.PromoRepository.
+func (r *promoRepostory) RemoveCity(ctx context.Context, tx *gorm.DB, promoID uint, city string) (bool, error) {
+	db := tx
+	if db == nil {
+		db = r.db.WithContext(ctx)
+	}
+
+	result := db.Where("promo_id = ? and lower(city) = lower(?)", promoID, city).Delete(&models.PromoCity{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// SaveUsers implements repository.PromoRepository.
+func (r *promoRepostory) SaveUsers(ctx context.Context, tx *gorm.DB, promoID uint, userIDs []uint) (int64, error) {
+	db := tx
+	if db == nil {
+		db = r.db.WithContext(ctx)
+	}
+
+	// unknown users and users already listed are skipped
+	result := db.Exec("INSERT INTO promo_users (promo_id, user_id) SELECT ?, id FROM users WHERE id IN ? ON CONFLICT (promo_id, user_id) DO NOTHING", promoID, userIDs)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+// RemoveUsers implements repository.PromoRepository.
+func (r *promoRepostory) RemoveUsers(ctx context.Context, tx *gorm.DB, promoID uint, userIDs []uint) (int64, error) {
+	db := tx
+	if db == nil {
+		db = r.db.WithContext(ctx)
+	}
+
+	result := db.Where("promo_id = ? and user_id in ?", promoID, userIDs).Delete(&models.PromoUser{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+// GetUsers implements repository.PromoRepository.
+func (r *promoRepostory) GetUsers(ctx context.Context, tx *gorm.DB, promoID uint, page int, perPage int) ([]models.PromoUser, int64, error) {
+	db := tx
+	if db == nil {
+		db = r.db.WithContext(ctx)
+	}
+
+	query := db.Model(&models.PromoUser{}).Where("promo_id = ?", promoID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []models.PromoUser
+	if err := query.Order("user_id").Offset((page - 1) * perPage).Limit(perPage).Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// FindUsers implements repository.PromoRepository.
+func (r *promoRepostory) FindUsers(ctx context.Context, tx *gorm.DB, promoID uint, userIDs []uint) ([]models.PromoUser, error) {
+	db := tx
+	if db == nil {
+		db = r.db.WithContext(ctx)
+	}
+
+	var users []models.PromoUser
+	if err := db.Where("promo_id = ? and user_id in ?", promoID, userIDs).Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// SaveSchedules implements repository.PromoRepository.
+func (r *promoRepostory) SaveSchedules(ctx context.Context, tx *gorm.DB, schedules []models.PromoSchedule) error {
+	db := tx
+	if db == nil {
+		db = r.db.WithContext(ctx)
+	}
+
+	return db.Create(&schedules).Error
+}
+
+// GetSchedules implements repository.PromoRepository.
+func (r *promoRepostory) GetSchedules(ctx context.Context, tx *gorm.DB, promoID uint) ([]models.PromoSchedule, error) {
+	db := tx
+	if db == nil {
+		db = r.db.WithContext(ctx)
+	}
+
+	var schedules []models.PromoSchedule
+	if err := db.Where("promo_id = ?", promoID).Order("day_of_week, start_time").Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+// SaveProducts implements repository.PromoRepository.
+func (r *promoRepostory) SaveProducts(ctx context.Context, tx *gorm.DB, promoID uint, productIDs []uint) error {
+	db := tx
+	if db == nil {
+		db = r.db.WithContext(ctx)
+	}
+
+	pp := make([]models.PromoProduct, len(productIDs))
+	for i, productID := range productIDs {
+		pp[i] = models.PromoProduct{
+			PromoID:   promoID,
+			ProductID: productID,
+		}
+	}
+
+	return db.Create(&pp).Error
+}
+
+// GetProducts implements repository.PromoRepository.
+func (r *promoRepostory) GetProducts(ctx context.Context, tx *gorm.DB, promoID uint) ([]models.PromoProduct, error) {
+	db := tx
+	if db == nil {
+		db = r.db.WithContext(ctx)
+	}
+
+	var products []models.PromoProduct
+	if err := db.Where("promo_id = ?", promoID).Order("id").Find(&products).Error; err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+// SaveTiers implements repository.PromoRepository.
+func (r *promoRepostory) SaveTiers(ctx context.Context, tx *gorm.DB, tiers []models.PromoTier) error {
+	db := tx
+	if db == nil {
+		db = r.db.WithContext(ctx)
+	}
+
+	return db.Create(&tiers).Error
+}
+
+// GetTiers implements repository.PromoRepository.
+func (r *promoRepostory) GetTiers(ctx context.Context, tx *gorm.DB, promoID uint) ([]models.PromoTier, error) {
+	db := tx
+	if db == nil {
+		db = r.db.WithContext(ctx)
+	}
+
+	var tiers []models.PromoTier
+	if err := db.Where("promo_id = ?", promoID).Order("min_amount").Find(&tiers).Error; err != nil {
+		return nil, err
+	}
+
+	return tiers, nil
+}
+
+// SaveVariants implements repository.PromoRepository.
+func (r *promoRepostory) SaveVariants(ctx context.Context, tx *gorm.DB, variants []models.PromoVariant) error {
+	db := tx
+	if db == nil {
+		db = r.db.WithContext(ctx)
+	}
+
+	return db.Create(&variants).Error
+}
+
+// SaveAuditLog implements repository.PromoRepository.
+func (r *promoRepostory) SaveAuditLog(ctx context.Context, tx *gorm.DB, log *models.PromoAuditLog) error {
+	db := tx
+	if db == nil {
+		db = r.db.WithContext(ctx)
+	}
+
+	return db.Create(log).Error
+}
+
+// GetAuditLogs implements repository.PromoRepository.
+func (r *promoRepostory) GetAuditLogs(ctx context.Context, tx *gorm.DB, promoID uint, page int, perPage int) ([]models.PromoAuditLog, int64, error) {
+	db := tx
+	if db == nil {
+		db = r.db.WithContext(ctx)
+	}
+
+	query := db.Model(&models.PromoAuditLog{}).Where("promo_id = ?", promoID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var logs []models.PromoAuditLog
+	if err := query.Order("id desc").Offset((page - 1) * perPage).Limit(perPage).Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return logs, total, nil
+}
+
+// IncrementUsage implements repository.PromoRepository.
+func (r *promoRepostory) IncrementUsage(ctx context.Context, tx *gorm.DB, promoID uint, amount float64) (bool, error) {
+	db := tx
+	if db == nil {
+		db = r.db.WithContext(ctx)
+	}
+
+	// the limit checks and the increments run as one statement, so two orders
+	// can not both take the last redemption or the last of the budget
+	result := db.Model(&models.Promo{}).
+		Where("id = ? and (max_usage_limit is null or current_usage_count < max_usage_limit) and (max_budget_amount is null or spent_amount + ? <= max_budget_amount)", promoID, amount).
+		Updates(map[string]interface{}{
+			"current_usage_count": gorm.Expr("current_usage_count + 1"),
+			"spent_amount":        gorm.Expr("spent_amount + ?", amount),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// GetUserUsageCount implements repository.PromoRepository.
+func (r *promoRepostory) GetUserUsageCount(ctx context.Context, tx *gorm.DB, userID uint, promoID uint) (int, error) {
+	db := tx
+	if db == nil {
+		db = r.db.WithContext(ctx)
+	}
+
+	var count int64
+	if err := db.Model(&models.OrderPromo{}).
+		Joins("join orders on orders.id = order_promos.order_id").
+		Where("order_promos.promo_id = ? and orders.user_id = ?", promoID, userID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
 // CreatePromo implements PromoRepository.
 func (p *promoRepostory) Save(ctx context.Context, tx *gorm.DB, promo *models.Promo) error {
 	db := p.db
@@ -157,7 +495,9 @@ func (p *promoRepostory) Save(ctx context.Context, tx *gorm.DB, promo *models.Pr
 		db = tx
 	}
 
-	return db.Save(promo).Error
+	// a promo read before an order was placed would otherwise write back
+	// the usage counters from before it
+	return db.Omit("current_usage_count", "spent_amount").Save(promo).Error
 }
 
 func NewPromoRepository(db *gorm.DB) repository.PromoRepository {
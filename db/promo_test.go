@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"hangry/constants"
 	"hangry/domain/models"
@@ -19,8 +20,8 @@ import (
 
 func Test_promoRepostory_GetPromoByUserCart(t *testing.T) {
 	isAvailable := true
-	page := 1
-	perPage := 10
+	withinUserLimit := true
+	freeProductID := uint(5)
 
 	type args struct {
 		ctx   context.Context
@@ -31,7 +32,6 @@ func Test_promoRepostory_GetPromoByUserCart(t *testing.T) {
 		name    string
 		args    args
 		want    []models.Promo
-		wantCnt int64
 		wantErr bool
 		sqlMock func(mock sqlmock.Sqlmock)
 	}{
@@ -46,94 +46,105 @@ func Test_promoRepostory_GetPromoByUserCart(t *testing.T) {
 					},
 					PromoIds:    []uint{1, 2},
 					IsAvailable: &isAvailable,
-					Page:        &page,
-					PerPage:     &perPage,
 				},
 			},
 			want: []models.Promo{
 				{
-					ID: 1,
+					ID:          1,
+					PromoCities: []models.PromoCity{},
+					PromoSchedules: []models.PromoSchedule{
+						{
+							ID:        1,
+							PromoID:   1,
+							DayOfWeek: 5,
+							StartTime: "14:00",
+							EndTime:   "17:00",
+						},
+					},
+					PromoProducts: []models.PromoProduct{},
+					PromoTiers:    []models.PromoTier{},
+					PromoUsers:    []models.PromoUser{},
+					PromoVariants: []models.PromoVariant{
+						{ID: 1, PromoID: 1, Name: "A", Weight: 1, DiscountValue: 15},
+						{ID: 2, PromoID: 1, Name: "B", Weight: 1, DiscountValue: 20},
+					},
 				},
 				{
-					ID: 2,
+					ID:            2,
+					FreeProductID: &freeProductID,
+					FreeProduct: &models.Product{
+						ID:    5,
+						Price: 8000,
+					},
+					PromoCities: []models.PromoCity{
+						{
+							ID:      1,
+							PromoID: 2,
+							City:    "Jakarta",
+						},
+					},
+					PromoSchedules: []models.PromoSchedule{},
+					PromoProducts: []models.PromoProduct{
+						{
+							ID:        1,
+							PromoID:   2,
+							ProductID: 3,
+						},
+					},
+					PromoTiers:    []models.PromoTier{},
+					PromoUsers:    []models.PromoUser{},
+					PromoVariants: []models.PromoVariant{},
 				},
 			},
-			wantCnt: 2,
 			wantErr: false,
 			sqlMock: func(mock sqlmock.Sqlmock) {
-				query := regexp.QuoteMeta(`
-					with user_data as ( select * from users u where u.id = $1 ), items as ( select p.price, ci.* from cart_items ci join carts c on c.id = ci.cart_id join products p on p.id = ci.product_id where c.user_id = $2 ), summary as ( select sum(i.price * i.quantity) total from items i ) select p.* from promos p where ( ( p."type" = 'PERCENTAGE_DISCOUNT' and (select total from summary) >= p.min_order_amount ) or ( p."type" = 'BUY_X_GET_Y_FREE' and (select coalesce(i.quantity,0) from items i where i.product_id = p.buy_product_id ) >= p.buy_product_qty ) ) and ( p.segmentation = 'ALL' or ( p.segmentation = 'CITY' and lower((select city from user_data)) in (select lower(city) from promo_cities pc where pc.promo_id = p.id) ) or ( p.segmentation = 'LOYAL_USER' and (select is_loyal from user_data) = true ) or ( p.segmentation = 'NEW_USER' and (select created_at from user_data) > (current_timestamp - interval '1 month') ) ) and p.id in ($3,$4) and p.start_date <= current_timestamp and p.end_date >= current_timestamp and p.current_usage_count < p.max_usage_limit group by p.id limit 10 offset 0;
-				`)
+				query := regexp.QuoteMeta(`SELECT * FROM "promos" WHERE promos.id in ($1,$2) and promos.is_code_only = false and not exists (select 1 from promo_vouchers pv where pv.promo_id = promos.id) and promos.start_date <= current_timestamp and promos.end_date >= current_timestamp and (promos.max_usage_limit is null or promos.current_usage_count < promos.max_usage_limit) and (promos.max_budget_amount is null or (promos.spent_amount < promos.max_budget_amount and (promos.free_product_id is null or promos.spent_amount + (select products.price from products where products.id = promos.free_product_id) <= promos.max_budget_amount))) and promos.status = 'APPROVED' ORDER BY promos.id`)
 				mock.ExpectQuery(query).
-					WithArgs(1, 1, 1, 2).
-					WillReturnRows(sqlmock.NewRows([]string{"id"}).
-						AddRow(1).
-						AddRow(2)).
-					WillReturnError(nil)
-
-				countQuery := regexp.QuoteMeta(`
-				with 
-            		user_data as (
-            				select 
-            						*
-            				from users u 
-            				where u.id = $1
-            		),
-            		items as (
-            				select
-            						p.price,
-            						ci.*
-            				from cart_items ci 
-            				join carts c on c.id = ci.cart_id
-            				join products p on p.id = ci.product_id 
-            				where c.user_id  = $2
-            		),
-            		summary as (
-            				select
-            						sum(i.price * i.quantity) total
-            				from items i
-            		)
-            		select
-            				count(*)
-            		from promos p
-            		where (
-            						(
-            								p."type" = 'PERCENTAGE_DISCOUNT' 
-            								and (select total from summary) >= p.min_order_amount 
-            						)
-            						or (
-            								p."type" = 'BUY_X_GET_Y_FREE' 
-            								and (select 
-            										coalesce(i.quantity,0)
-            										from items i
-            										where i.product_id = p.buy_product_id 
-            								) >= p.buy_product_qty 
-            						)
-            				)
-            				and (
-            						p.segmentation = 'ALL'
-            						or (
-            								p.segmentation = 'CITY' 
-            								and lower((select city from user_data)) in (select lower(city) from promo_cities pc where pc.promo_id = p.id)
-            						)
-            						or (
-            								p.segmentation = 'LOYAL_USER'
-            								and (select is_loyal from user_data) = true
-            						)
-            						or (
-            								p.segmentation = 'NEW_USER'
-            								and (select created_at from user_data) > (current_timestamp - interval '1 month')
-            						)
-            				)
-            		 and p.id in ($3,$4) and p.start_date <= current_timestamp and p.end_date >= current_timestamp and p.current_usage_count < p.max_usage_limit
-            		;
-				`)
-				mock.ExpectQuery(countQuery).
-					WithArgs(1, 1, 1, 2).
-					WillReturnRows(sqlmock.NewRows([]string{"count"}).
-						AddRow(2)).
-					WillReturnError(nil)
+					WithArgs(1, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "free_product_id"}).
+						AddRow(1, nil).
+						AddRow(2, 5))
+
+				freeProductQuery := regexp.QuoteMeta(`SELECT * FROM "products" WHERE "products"."id" = $1`)
+				mock.ExpectQuery(freeProductQuery).
+					WithArgs(5).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "price"}).
+						AddRow(5, 8000))
 
+				citiesQuery := regexp.QuoteMeta(`SELECT * FROM "promo_cities" WHERE "promo_cities"."promo_id" IN ($1,$2)`)
+				mock.ExpectQuery(citiesQuery).
+					WithArgs(1, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "promo_id", "city"}).
+						AddRow(1, 2, "Jakarta"))
+
+				productsQuery := regexp.QuoteMeta(`SELECT * FROM "promo_products" WHERE "promo_products"."promo_id" IN ($1,$2)`)
+				mock.ExpectQuery(productsQuery).
+					WithArgs(1, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "promo_id", "product_id"}).
+						AddRow(1, 2, 3))
+
+				schedulesQuery := regexp.QuoteMeta(`SELECT * FROM "promo_schedules" WHERE "promo_schedules"."promo_id" IN ($1,$2)`)
+				mock.ExpectQuery(schedulesQuery).
+					WithArgs(1, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "promo_id", "day_of_week", "start_time", "end_time"}).
+						AddRow(1, 1, 5, "14:00", "17:00"))
+
+				tiersQuery := regexp.QuoteMeta(`SELECT * FROM "promo_tiers" WHERE "promo_tiers"."promo_id" IN ($1,$2)`)
+				mock.ExpectQuery(tiersQuery).
+					WithArgs(1, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "promo_id", "min_amount", "discount_type", "discount_value"}))
+
+				usersQuery := regexp.QuoteMeta(`SELECT * FROM "promo_users" WHERE "promo_users"."promo_id" IN ($1,$2) AND user_id = $3`)
+				mock.ExpectQuery(usersQuery).
+					WithArgs(1, 2, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "promo_id", "user_id"}))
+
+				variantsQuery := regexp.QuoteMeta(`SELECT * FROM "promo_variants" WHERE "promo_variants"."promo_id" IN ($1,$2) ORDER BY id`)
+				mock.ExpectQuery(variantsQuery).
+					WithArgs(1, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "promo_id", "name", "weight", "discount_value"}).
+						AddRow(1, 1, "A", 1, 15).
+						AddRow(2, 1, "B", 1, 20))
 			},
 		},
 		{
@@ -147,26 +158,47 @@ func Test_promoRepostory_GetPromoByUserCart(t *testing.T) {
 					},
 					PromoIds:    []uint{1, 2},
 					IsAvailable: &isAvailable,
-					Page:        &page,
-					PerPage:     &perPage,
 				},
 			},
 			want:    nil,
-			wantCnt: 0,
 			wantErr: true,
 			sqlMock: func(mock sqlmock.Sqlmock) {
-				query := regexp.QuoteMeta(`
-					with user_data as ( select * from users u where u.id = $1 ), items as ( select p.price, ci.* from cart_items ci join carts c on c.id = ci.cart_id join products p on p.id = ci.product_id where c.user_id = $2 ), summary as ( select sum(i.price * i.quantity) total from items i ) select p.* from promos p where ( ( p."type" = 'PERCENTAGE_DISCOUNT' and (select total from summary) >= p.min_order_amount ) or ( p."type" = 'BUY_X_GET_Y_FREE' and (select coalesce(i.quantity,0) from items i where i.product_id = p.buy_product_id ) >= p.buy_product_qty ) ) and ( p.segmentation = 'ALL' or ( p.segmentation = 'CITY' and lower((select city from user_data)) in (select lower(city) from promo_cities pc where pc.promo_id = p.id) ) or ( p.segmentation = 'LOYAL_USER' and (select is_loyal from user_data) = true ) or ( p.segmentation = 'NEW_USER' and (select created_at from user_data) > (current_timestamp - interval '1 month') ) ) and p.id in ($3,$4) and p.start_date <= current_timestamp and p.end_date >= current_timestamp and p.current_usage_count < p.max_usage_limit group by p.id limit 10 offset 0;
-				`)
+				query := regexp.QuoteMeta(`SELECT * FROM "promos" WHERE promos.id in ($1,$2) and promos.is_code_only = false and not exists (select 1 from promo_vouchers pv where pv.promo_id = promos.id) and promos.start_date <= current_timestamp and promos.end_date >= current_timestamp and (promos.max_usage_limit is null or promos.current_usage_count < promos.max_usage_limit) and (promos.max_budget_amount is null or (promos.spent_amount < promos.max_budget_amount and (promos.free_product_id is null or promos.spent_amount + (select products.price from products where products.id = promos.free_product_id) <= promos.max_budget_amount))) and promos.status = 'APPROVED' ORDER BY promos.id`)
 				mock.ExpectQuery(query).
-					WithArgs(1, 1, 1, 2).
-					WillReturnRows(sqlmock.NewRows([]string{"id"})).
+					WithArgs(1, 2).
 					WillReturnError(errors.New("error"))
+			},
+		},
+		{
+			name: "error get promo cities",
+			args: args{
+				ctx: context.Background(),
+				tx:  nil,
+				input: repository.GetPromoByUserCartInput{
+					Cart: models.Cart{
+						UserID: 1,
+					},
+					PromoIds:    []uint{1, 2},
+					IsAvailable: &isAvailable,
+				},
+			},
+			want:    nil,
+			wantErr: true,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				query := regexp.QuoteMeta(`SELECT * FROM "promos" WHERE promos.id in ($1,$2) and promos.is_code_only = false and not exists (select 1 from promo_vouchers pv where pv.promo_id = promos.id) and promos.start_date <= current_timestamp and promos.end_date >= current_timestamp and (promos.max_usage_limit is null or promos.current_usage_count < promos.max_usage_limit) and (promos.max_budget_amount is null or (promos.spent_amount < promos.max_budget_amount and (promos.free_product_id is null or promos.spent_amount + (select products.price from products where products.id = promos.free_product_id) <= promos.max_budget_amount))) and promos.status = 'APPROVED' ORDER BY promos.id`)
+				mock.ExpectQuery(query).
+					WithArgs(1, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).
+						AddRow(1))
 
+				citiesQuery := regexp.QuoteMeta(`SELECT * FROM "promo_cities" WHERE "promo_cities"."promo_id" = $1`)
+				mock.ExpectQuery(citiesQuery).
+					WithArgs(1).
+					WillReturnError(errors.New("error"))
 			},
 		},
 		{
-			name: "success",
+			name: "error get promo schedules",
 			args: args{
 				ctx: context.Background(),
 				tx:  nil,
@@ -176,86 +208,139 @@ func Test_promoRepostory_GetPromoByUserCart(t *testing.T) {
 					},
 					PromoIds:    []uint{1, 2},
 					IsAvailable: &isAvailable,
-					Page:        &page,
-					PerPage:     &perPage,
 				},
 			},
 			want:    nil,
-			wantCnt: 0,
 			wantErr: true,
 			sqlMock: func(mock sqlmock.Sqlmock) {
-				query := regexp.QuoteMeta(`
-					with user_data as ( select * from users u where u.id = $1 ), items as ( select p.price, ci.* from cart_items ci join carts c on c.id = ci.cart_id join products p on p.id = ci.product_id where c.user_id = $2 ), summary as ( select sum(i.price * i.quantity) total from items i ) select p.* from promos p where ( ( p."type" = 'PERCENTAGE_DISCOUNT' and (select total from summary) >= p.min_order_amount ) or ( p."type" = 'BUY_X_GET_Y_FREE' and (select coalesce(i.quantity,0) from items i where i.product_id = p.buy_product_id ) >= p.buy_product_qty ) ) and ( p.segmentation = 'ALL' or ( p.segmentation = 'CITY' and lower((select city from user_data)) in (select lower(city) from promo_cities pc where pc.promo_id = p.id) ) or ( p.segmentation = 'LOYAL_USER' and (select is_loyal from user_data) = true ) or ( p.segmentation = 'NEW_USER' and (select created_at from user_data) > (current_timestamp - interval '1 month') ) ) and p.id in ($3,$4) and p.start_date <= current_timestamp and p.end_date >= current_timestamp and p.current_usage_count < p.max_usage_limit group by p.id limit 10 offset 0;
-				`)
+				query := regexp.QuoteMeta(`SELECT * FROM "promos" WHERE promos.id in ($1,$2) and promos.is_code_only = false and not exists (select 1 from promo_vouchers pv where pv.promo_id = promos.id) and promos.start_date <= current_timestamp and promos.end_date >= current_timestamp and (promos.max_usage_limit is null or promos.current_usage_count < promos.max_usage_limit) and (promos.max_budget_amount is null or (promos.spent_amount < promos.max_budget_amount and (promos.free_product_id is null or promos.spent_amount + (select products.price from products where products.id = promos.free_product_id) <= promos.max_budget_amount))) and promos.status = 'APPROVED' ORDER BY promos.id`)
 				mock.ExpectQuery(query).
-					WithArgs(1, 1, 1, 2).
+					WithArgs(1, 2).
 					WillReturnRows(sqlmock.NewRows([]string{"id"}).
-						AddRow(1).
-						AddRow(2)).
-					WillReturnError(nil)
-
-				countQuery := regexp.QuoteMeta(`
-				with 
-            		user_data as (
-            				select 
-            						*
-            				from users u 
-            				where u.id = $1
-            		),
-            		items as (
-            				select
-            						p.price,
-            						ci.*
-            				from cart_items ci 
-            				join carts c on c.id = ci.cart_id
-            				join products p on p.id = ci.product_id 
-            				where c.user_id  = $2
-            		),
-            		summary as (
-            				select
-            						sum(i.price * i.quantity) total
-            				from items i
-            		)
-            		select
-            				count(*)
-            		from promos p
-            		where (
-            						(
-            								p."type" = 'PERCENTAGE_DISCOUNT' 
-            								and (select total from summary) >= p.min_order_amount 
-            						)
-            						or (
-            								p."type" = 'BUY_X_GET_Y_FREE' 
-            								and (select 
-            										coalesce(i.quantity,0)
-            										from items i
-            										where i.product_id = p.buy_product_id 
-            								) >= p.buy_product_qty 
-            						)
-            				)
-            				and (
-            						p.segmentation = 'ALL'
-            						or (
-            								p.segmentation = 'CITY' 
-            								and lower((select city from user_data)) in (select lower(city) from promo_cities pc where pc.promo_id = p.id)
-            						)
-            						or (
-            								p.segmentation = 'LOYAL_USER'
-            								and (select is_loyal from user_data) = true
-            						)
-            						or (
-            								p.segmentation = 'NEW_USER'
-            								and (select created_at from user_data) > (current_timestamp - interval '1 month')
-            						)
-            				)
-            		 and p.id in ($3,$4) and p.start_date <= current_timestamp and p.end_date >= current_timestamp and p.current_usage_count < p.max_usage_limit
-            		;
-				`)
-				mock.ExpectQuery(countQuery).
-					WithArgs(1, 1, 1, 2).
-					WillReturnRows(sqlmock.NewRows([]string{"count"})).
+						AddRow(1))
+
+				citiesQuery := regexp.QuoteMeta(`SELECT * FROM "promo_cities" WHERE "promo_cities"."promo_id" = $1`)
+				mock.ExpectQuery(citiesQuery).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "promo_id", "city"}))
+
+				productsQuery := regexp.QuoteMeta(`SELECT * FROM "promo_products" WHERE "promo_products"."promo_id" = $1`)
+				mock.ExpectQuery(productsQuery).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "promo_id", "product_id"}))
+
+				schedulesQuery := regexp.QuoteMeta(`SELECT * FROM "promo_schedules" WHERE "promo_schedules"."promo_id" = $1`)
+				mock.ExpectQuery(schedulesQuery).
+					WithArgs(1).
 					WillReturnError(errors.New("error"))
+			},
+		},
+		{
+			name: "success within user limit",
+			args: args{
+				ctx: context.Background(),
+				tx:  nil,
+				input: repository.GetPromoByUserCartInput{
+					Cart: models.Cart{
+						UserID: 1,
+					},
+					IsAvailable:     &isAvailable,
+					WithinUserLimit: &withinUserLimit,
+				},
+			},
+			want:    []models.Promo{},
+			wantErr: false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				query := regexp.QuoteMeta(`SELECT * FROM "promos" WHERE promos.is_code_only = false and not exists (select 1 from promo_vouchers pv where pv.promo_id = promos.id) and promos.start_date <= current_timestamp and promos.end_date >= current_timestamp and (promos.max_usage_limit is null or promos.current_usage_count < promos.max_usage_limit) and (promos.max_budget_amount is null or (promos.spent_amount < promos.max_budget_amount and (promos.free_product_id is null or promos.spent_amount + (select products.price from products where products.id = promos.free_product_id) <= promos.max_budget_amount))) and promos.status = 'APPROVED' and (promos.max_usage_per_user is null or (select count(*) from order_promos op join orders o on o.id = op.order_id where op.promo_id = promos.id and o.user_id = $1) < promos.max_usage_per_user) ORDER BY promos.id`)
+				mock.ExpectQuery(query).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+		},
+		{
+			name: "success for update",
+			args: args{
+				ctx: context.Background(),
+				tx:  nil,
+				input: repository.GetPromoByUserCartInput{
+					Cart: models.Cart{
+						UserID: 1,
+					},
+					IsAvailable: &isAvailable,
+					ForUpdate:   true,
+				},
+			},
+			want:    []models.Promo{},
+			wantErr: false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				query := regexp.QuoteMeta(`SELECT * FROM "promos" WHERE promos.is_code_only = false and not exists (select 1 from promo_vouchers pv where pv.promo_id = promos.id) and promos.start_date <= current_timestamp and promos.end_date >= current_timestamp and promos.status = 'APPROVED' ORDER BY promos.id FOR UPDATE`)
+				mock.ExpectQuery(query).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+		},
+		{
+			name: "success with promo codes",
+			args: args{
+				ctx: context.Background(),
+				tx:  nil,
+				input: repository.GetPromoByUserCartInput{
+					Cart: models.Cart{
+						UserID: 1,
+					},
+					PromoIds:    []uint{1, 2},
+					PromoCodes:  []string{"HEMAT20"},
+					IsAvailable: &isAvailable,
+				},
+			},
+			want: []models.Promo{
+				{
+					ID:             3,
+					PromoCities:    []models.PromoCity{},
+					PromoSchedules: []models.PromoSchedule{},
+					PromoProducts:  []models.PromoProduct{},
+					PromoTiers:     []models.PromoTier{},
+					PromoUsers:     []models.PromoUser{{ID: 1, PromoID: 3, UserID: 1}},
+					PromoVariants:  []models.PromoVariant{},
+				},
+			},
+			wantErr: false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				query := regexp.QuoteMeta(`SELECT * FROM "promos" WHERE ((promos.id in ($1,$2) and promos.is_code_only = false and not exists (select 1 from promo_vouchers pv where pv.promo_id = promos.id)) or (promos.code in ($3) and not exists (select 1 from promo_vouchers pv where pv.promo_id = promos.id))) and promos.start_date <= current_timestamp and promos.end_date >= current_timestamp and (promos.max_usage_limit is null or promos.current_usage_count < promos.max_usage_limit) and (promos.max_budget_amount is null or (promos.spent_amount < promos.max_budget_amount and (promos.free_product_id is null or promos.spent_amount + (select products.price from products where products.id = promos.free_product_id) <= promos.max_budget_amount))) and promos.status = 'APPROVED' ORDER BY promos.id`)
+				mock.ExpectQuery(query).
+					WithArgs(1, 2, "HEMAT20").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).
+						AddRow(3))
+
+				citiesQuery := regexp.QuoteMeta(`SELECT * FROM "promo_cities" WHERE "promo_cities"."promo_id" = $1`)
+				mock.ExpectQuery(citiesQuery).
+					WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "promo_id", "city"}))
+
+				productsQuery := regexp.QuoteMeta(`SELECT * FROM "promo_products" WHERE "promo_products"."promo_id" = $1`)
+				mock.ExpectQuery(productsQuery).
+					WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "promo_id", "product_id"}))
+
+				schedulesQuery := regexp.QuoteMeta(`SELECT * FROM "promo_schedules" WHERE "promo_schedules"."promo_id" = $1`)
+				mock.ExpectQuery(schedulesQuery).
+					WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "promo_id", "day_of_week", "start_time", "end_time"}))
+
+				tiersQuery := regexp.QuoteMeta(`SELECT * FROM "promo_tiers" WHERE "promo_tiers"."promo_id" = $1`)
+				mock.ExpectQuery(tiersQuery).
+					WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "promo_id", "min_amount", "discount_type", "discount_value"}))
+
+				usersQuery := regexp.QuoteMeta(`SELECT * FROM "promo_users" WHERE "promo_users"."promo_id" = $1 AND user_id = $2`)
+				mock.ExpectQuery(usersQuery).
+					WithArgs(3, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "promo_id", "user_id"}).
+						AddRow(1, 3, 1))
 
+				variantsQuery := regexp.QuoteMeta(`SELECT * FROM "promo_variants" WHERE "promo_variants"."promo_id" = $1 ORDER BY id`)
+				mock.ExpectQuery(variantsQuery).
+					WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "promo_id", "name", "weight", "discount_value"}))
 			},
 		},
 	}
@@ -281,7 +366,7 @@ func Test_promoRepostory_GetPromoByUserCart(t *testing.T) {
 
 			r := NewPromoRepository(gormDB)
 
-			got, gotCnt, err := r.GetPromoByUserCart(tt.args.ctx, tt.args.tx, tt.args.input)
+			got, err := r.GetPromoByUserCart(tt.args.ctx, tt.args.tx, tt.args.input)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("promoRepostory.GetPromoByUserCart() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -289,9 +374,6 @@ func Test_promoRepostory_GetPromoByUserCart(t *testing.T) {
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("promoRepostory.GetPromoByUserCart() = %v, want %v", got, tt.want)
 			}
-			if gotCnt != tt.wantCnt {
-				t.Errorf("promoRepostory.GetPromoByUserCart() count = %v, want %v", gotCnt, tt.wantCnt)
-			}
 
 			if err := mock.ExpectationsWereMet(); err != nil {
 				t.Errorf("there were unfulfilled expectations: %s", err)
@@ -399,9 +481,8 @@ func Test_promoRepostory_GetPromoByPromoID(t *testing.T) {
 	}
 }
 
-func Test_promoRepostory_SaveCities(t *testing.T) {
+func Test_promoRepostory_GetPromoForUpdate(t *testing.T) {
 	timeNow := time.Now()
-
 	type fields struct {
 		db *gorm.DB
 	}
@@ -409,12 +490,12 @@ func Test_promoRepostory_SaveCities(t *testing.T) {
 		ctx     context.Context
 		tx      *gorm.DB
 		promoID uint
-		cities  []string
 	}
 	tests := []struct {
 		name    string
 		fields  fields
 		args    args
+		want    models.Promo
 		wantErr bool
 		sqlMock func(mock sqlmock.Sqlmock)
 	}{
@@ -427,18 +508,38 @@ func Test_promoRepostory_SaveCities(t *testing.T) {
 				ctx:     context.Background(),
 				tx:      nil,
 				promoID: 1,
-				cities:  []string{"Jakarta", "Bandung"},
+			},
+			want: models.Promo{
+				ID:        1,
+				CreatedAt: timeNow,
+				UpdatedAt: timeNow,
 			},
 			wantErr: false,
 			sqlMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectBegin()
-				query := regexp.QuoteMeta(`INSERT INTO "promo_cities" ("promo_id","city") VALUES ($1,$2),($3,$4) RETURNING "created_at","updated_at","id"`)
+				query := regexp.QuoteMeta(`SELECT * FROM "promos" WHERE id = $1 ORDER BY "promos"."id" LIMIT $2 FOR UPDATE`)
 				mock.ExpectQuery(query).
-					WithArgs(1, "Jakarta", 1, "Bandung").
-					WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at", "id"}).
-						AddRow(timeNow, timeNow, 1).
-						AddRow(timeNow, timeNow, 2))
-				mock.ExpectCommit()
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).
+						AddRow(1, timeNow, timeNow))
+			},
+		},
+		{
+			name: "err get promo",
+			fields: fields{
+				db: nil,
+			},
+			args: args{
+				ctx:     context.Background(),
+				tx:      nil,
+				promoID: 1,
+			},
+			want:    models.Promo{},
+			wantErr: true,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				query := regexp.QuoteMeta(`SELECT * FROM "promos" WHERE id = $1 ORDER BY "promos"."id" LIMIT $2 FOR UPDATE`)
+				mock.ExpectQuery(query).
+					WithArgs(1, 1).
+					WillReturnError(errors.New("error"))
 			},
 		},
 	}
@@ -463,8 +564,13 @@ func Test_promoRepostory_SaveCities(t *testing.T) {
 			tt.sqlMock(mock)
 
 			r := NewPromoRepository(gormDB)
-			if err := r.SaveCities(tt.args.ctx, tt.args.tx, tt.args.promoID, tt.args.cities); (err != nil) != tt.wantErr {
-				t.Errorf("promoRepostory.SaveCities() error = %v, wantErr %v", err, tt.wantErr)
+			got, err := r.GetPromoForUpdate(tt.args.ctx, tt.args.tx, tt.args.promoID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("promoRepostory.GetPromoForUpdate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("promoRepostory.GetPromoForUpdate() = %v, want %v", got, tt.want)
 			}
 
 			if err := mock.ExpectationsWereMet(); err != nil {
@@ -474,24 +580,21 @@ func Test_promoRepostory_SaveCities(t *testing.T) {
 	}
 }
 
-func Test_promoRepostory_Save(t *testing.T) {
+func Test_promoRepostory_GetPromoByCode(t *testing.T) {
 	timeNow := time.Now()
-	buyProductID := uint(1)
-	freeProductID := uint(1)
-	maxUsageLimit := 1
-
 	type fields struct {
 		db *gorm.DB
 	}
 	type args struct {
-		ctx   context.Context
-		tx    *gorm.DB
-		promo *models.Promo
+		ctx  context.Context
+		tx   *gorm.DB
+		code string
 	}
 	tests := []struct {
 		name    string
 		fields  fields
 		args    args
+		want    models.Promo
 		wantErr bool
 		sqlMock func(mock sqlmock.Sqlmock)
 	}{
@@ -501,91 +604,41 @@ func Test_promoRepostory_Save(t *testing.T) {
 				db: nil,
 			},
 			args: args{
-				ctx: context.Background(),
-				tx:  nil,
-				promo: &models.Promo{
-					ID:                1,
-					Name:              "name",
-					Description:       "description",
-					Segmentation:      constants.PROMOSEGMENTATIONALL,
-					Type:              constants.PROMOTYPEBUYXGETY,
-					MinOrderAmount:    1,
-					DiscountValue:     1,
-					MaxDiscountAmount: 1,
-					BuyProductID:      &buyProductID,
-					FreeProductID:     &freeProductID,
-					BuyProductQty:     1,
-					FreeProductQty:    1,
-					StartDate:         timeNow,
-					EndDate:           timeNow,
-					MaxUsageLimit:     &maxUsageLimit,
-					CurrentUsageCount: 1,
-					CreatedAt:         timeNow,
-					UpdatedAt:         timeNow,
-				},
+				ctx:  context.Background(),
+				tx:   nil,
+				code: "HEMAT20",
+			},
+			want: models.Promo{
+				ID:        1,
+				CreatedAt: timeNow,
+				UpdatedAt: timeNow,
 			},
 			wantErr: false,
 			sqlMock: func(mock sqlmock.Sqlmock) {
-
-				mock.ExpectBegin()
-				query := regexp.QuoteMeta(`UPDATE "promos" SET "name"=$1,"description"=$2,"segmentation"=$3,"type"=$4,"min_order_amount"=$5,"discount_value"=$6,"max_discount_amount"=$7,"buy_product_id"=$8,"free_product_id"=$9,"buy_product_qty"=$10,"free_product_qty"=$11,"start_date"=$12,"end_date"=$13,"max_usage_limit"=$14,"current_usage_count"=$15,"created_at"=$16,"updated_at"=$17 WHERE "id" = $18`)
-				mock.ExpectExec(query).
-					WithArgs("name", "description", constants.PROMOSEGMENTATIONALL, constants.PROMOTYPEBUYXGETY,
-						float64(1), float64(1), float64(1),
-						buyProductID, freeProductID, 1, 1,
-						sqlmock.AnyArg(), sqlmock.AnyArg(), // start_date, end_date
-						1, 1,
-						sqlmock.AnyArg(), sqlmock.AnyArg(), // created_at, updated_at
-						uint(1)).
-					WillReturnResult(sqlmock.NewResult(1, 1))
-
-				mock.ExpectCommit()
+				query := regexp.QuoteMeta(`SELECT * FROM "promos" WHERE code = $1 ORDER BY "promos"."id" LIMIT $2`)
+				mock.ExpectQuery(query).
+					WithArgs("HEMAT20", 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).
+						AddRow(1, timeNow, timeNow))
 			},
 		},
 		{
-			name: "error",
+			name: "err get promo",
 			fields: fields{
 				db: nil,
 			},
 			args: args{
-				ctx: context.Background(),
-				tx:  nil,
-				promo: &models.Promo{
-					ID:                1,
-					Name:              "name",
-					Description:       "description",
-					Segmentation:      constants.PROMOSEGMENTATIONALL,
-					Type:              constants.PROMOTYPEBUYXGETY,
-					MinOrderAmount:    1,
-					DiscountValue:     1,
-					MaxDiscountAmount: 1,
-					BuyProductID:      &buyProductID,
-					FreeProductID:     &freeProductID,
-					BuyProductQty:     1,
-					FreeProductQty:    1,
-					StartDate:         timeNow,
-					EndDate:           timeNow,
-					MaxUsageLimit:     &maxUsageLimit,
-					CurrentUsageCount: 1,
-					CreatedAt:         timeNow,
-					UpdatedAt:         timeNow,
-				},
+				ctx:  context.Background(),
+				tx:   nil,
+				code: "HEMAT20",
 			},
+			want:    models.Promo{},
 			wantErr: true,
 			sqlMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectBegin()
-				query := regexp.QuoteMeta(`UPDATE "promos" SET "name"=$1,"description"=$2,"segmentation"=$3,"type"=$4,"min_order_amount"=$5,"discount_value"=$6,"max_discount_amount"=$7,"buy_product_id"=$8,"free_product_id"=$9,"buy_product_qty"=$10,"free_product_qty"=$11,"start_date"=$12,"end_date"=$13,"max_usage_limit"=$14,"current_usage_count"=$15,"created_at"=$16,"updated_at"=$17 WHERE "id" = $18`)
-				mock.ExpectExec(query).
-					WithArgs("name", "description", constants.PROMOSEGMENTATIONALL, constants.PROMOTYPEBUYXGETY,
-						float64(1), float64(1), float64(1),
-						buyProductID, freeProductID, 1, 1,
-						sqlmock.AnyArg(), sqlmock.AnyArg(), // start_date, end_date
-						1, 1,
-						sqlmock.AnyArg(), sqlmock.AnyArg(), // created_at, updated_at
-						uint(1)).
+				query := regexp.QuoteMeta(`SELECT * FROM "promos" WHERE code = $1 ORDER BY "promos"."id" LIMIT $2`)
+				mock.ExpectQuery(query).
+					WithArgs("HEMAT20", 1).
 					WillReturnError(errors.New("error"))
-
-				mock.ExpectRollback()
 			},
 		},
 	}
@@ -610,8 +663,2079 @@ func Test_promoRepostory_Save(t *testing.T) {
 			tt.sqlMock(mock)
 
 			r := NewPromoRepository(gormDB)
-			if err := r.Save(tt.args.ctx, gormDB, tt.args.promo); (err != nil) != tt.wantErr {
-				t.Errorf("promoRepostory.Save() error = %v, wantErr %v", err, tt.wantErr)
+			got, err := r.GetPromoByCode(tt.args.ctx, tt.args.tx, tt.args.code)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("promoRepostory.GetPromoByCode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("promoRepostory.GetPromoByCode() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_promoRepostory_SaveCities(t *testing.T) {
+	timeNow := time.Now()
+
+	type fields struct {
+		db *gorm.DB
+	}
+	type args struct {
+		ctx     context.Context
+		tx      *gorm.DB
+		promoID uint
+		cities  []string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		sqlMock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: nil,
+			},
+			args: args{
+				ctx:     context.Background(),
+				tx:      nil,
+				promoID: 1,
+				cities:  []string{"Jakarta", "Bandung"},
+			},
+			wantErr: false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				query := regexp.QuoteMeta(`INSERT INTO "promo_cities" ("promo_id","city") VALUES ($1,$2),($3,$4) RETURNING "created_at","updated_at","id"`)
+				mock.ExpectQuery(query).
+					WithArgs(1, "Jakarta", 1, "Bandung").
+					WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at", "id"}).
+						AddRow(timeNow, timeNow, 1).
+						AddRow(timeNow, timeNow, 2))
+				mock.ExpectCommit()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sqlmock: %v", err)
+			}
+			defer sqlDB.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{
+				Conn: sqlDB,
+			}), &gorm.Config{
+				Logger: logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				t.Fatalf("failed to open gorm connection: %v", err)
+			}
+
+			tt.sqlMock(mock)
+
+			r := NewPromoRepository(gormDB)
+			if err := r.SaveCities(tt.args.ctx, tt.args.tx, tt.args.promoID, tt.args.cities); (err != nil) != tt.wantErr {
+				t.Errorf("promoRepostory.SaveCities() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_promoRepostory_Save(t *testing.T) {
+	timeNow := time.Now()
+	buyProductID := uint(1)
+	freeProductID := uint(1)
+	maxUsageLimit := 1
+	maxUsagePerUser := 1
+	maxBudgetAmount := float64(5000)
+	code := "HEMAT20"
+	maxRepeat := 2
+	stackGroup := "payday"
+
+	type fields struct {
+		db *gorm.DB
+	}
+	type args struct {
+		ctx   context.Context
+		tx    *gorm.DB
+		promo *models.Promo
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+		sqlMock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			fields: fields{
+				db: nil,
+			},
+			args: args{
+				ctx: context.Background(),
+				tx:  nil,
+				promo: &models.Promo{
+					ID:                1,
+					Name:              "name",
+					Description:       "description",
+					Segmentation:      constants.PROMOSEGMENTATIONALL,
+					Type:              constants.PROMOTYPEBUYXGETY,
+					MinOrderAmount:    1,
+					DiscountValue:     1,
+					MaxDiscountAmount: 1,
+					BuyProductID:      &buyProductID,
+					FreeProductID:     &freeProductID,
+					BuyProductQty:     1,
+					FreeProductQty:    1,
+					StartDate:         timeNow,
+					EndDate:           timeNow,
+					MaxUsageLimit:     &maxUsageLimit,
+					CurrentUsageCount: 1,
+					MaxUsagePerUser:   &maxUsagePerUser,
+					MaxBudgetAmount:   &maxBudgetAmount,
+					SpentAmount:       1000,
+					Code:              &code,
+					IsRepeatable:      true,
+					MaxRepeat:         &maxRepeat,
+					StackGroup:        &stackGroup,
+					Priority:          1,
+					Timezone:          constants.PROMODEFAULTTIMEZONE,
+					Status:            constants.PROMOSTATUSAPPROVED,
+					CreatedAt:         timeNow,
+					UpdatedAt:         timeNow,
+				},
+			},
+			wantErr: false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+
+				mock.ExpectBegin()
+				query := regexp.QuoteMeta(`UPDATE "promos" SET "name"=$1,"description"=$2,"segmentation"=$3,"type"=$4,"min_order_amount"=$5,"discount_value"=$6,"max_discount_amount"=$7,"buy_product_id"=$8,"free_product_id"=$9,"buy_product_qty"=$10,"free_product_qty"=$11,"start_date"=$12,"end_date"=$13,"max_usage_limit"=$14,"max_usage_per_user"=$15,"max_budget_amount"=$16,"code"=$17,"is_code_only"=$18,"is_repeatable"=$19,"max_repeat"=$20,"is_exclusive"=$21,"stack_group"=$22,"priority"=$23,"timezone"=$24,"status"=$25,"reviewed_by"=$26,"reviewed_at"=$27,"rejection_reason"=$28,"new_user_days"=$29,"lapsed_days"=$30,"order_number"=$31,"rollout_percentage"=$32,"created_at"=$33,"updated_at"=$34 WHERE "id" = $35`)
+				mock.ExpectExec(query).
+					WithArgs("name", "description", constants.PROMOSEGMENTATIONALL, constants.PROMOTYPEBUYXGETY,
+						float64(1), float64(1), float64(1),
+						buyProductID, freeProductID, 1, 1,
+						sqlmock.AnyArg(), sqlmock.AnyArg(), // start_date, end_date
+						1, 1, // max_usage_limit, max_usage_per_user
+						maxBudgetAmount,
+						code, false,
+						true, 2,
+						false, stackGroup, 1,
+						constants.PROMODEFAULTTIMEZONE, constants.PROMOSTATUSAPPROVED,
+						nil, nil, nil, // reviewed_by, reviewed_at, rejection_reason
+						nil, nil, nil, nil, // new_user_days, lapsed_days, order_number, rollout_percentage
+						sqlmock.AnyArg(), sqlmock.AnyArg(), // created_at, updated_at
+						uint(1)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "error",
+			fields: fields{
+				db: nil,
+			},
+			args: args{
+				ctx: context.Background(),
+				tx:  nil,
+				promo: &models.Promo{
+					ID:                1,
+					Name:              "name",
+					Description:       "description",
+					Segmentation:      constants.PROMOSEGMENTATIONALL,
+					Type:              constants.PROMOTYPEBUYXGETY,
+					MinOrderAmount:    1,
+					DiscountValue:     1,
+					MaxDiscountAmount: 1,
+					BuyProductID:      &buyProductID,
+					FreeProductID:     &freeProductID,
+					BuyProductQty:     1,
+					FreeProductQty:    1,
+					StartDate:         timeNow,
+					EndDate:           timeNow,
+					MaxUsageLimit:     &maxUsageLimit,
+					CurrentUsageCount: 1,
+					MaxUsagePerUser:   &maxUsagePerUser,
+					MaxBudgetAmount:   &maxBudgetAmount,
+					SpentAmount:       1000,
+					Code:              &code,
+					IsRepeatable:      true,
+					MaxRepeat:         &maxRepeat,
+					StackGroup:        &stackGroup,
+					Priority:          1,
+					Timezone:          constants.PROMODEFAULTTIMEZONE,
+					Status:            constants.PROMOSTATUSAPPROVED,
+					CreatedAt:         timeNow,
+					UpdatedAt:         timeNow,
+				},
+			},
+			wantErr: true,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				query := regexp.QuoteMeta(`UPDATE "promos" SET "name"=$1,"description"=$2,"segmentation"=$3,"type"=$4,"min_order_amount"=$5,"discount_value"=$6,"max_discount_amount"=$7,"buy_product_id"=$8,"free_product_id"=$9,"buy_product_qty"=$10,"free_product_qty"=$11,"start_date"=$12,"end_date"=$13,"max_usage_limit"=$14,"max_usage_per_user"=$15,"max_budget_amount"=$16,"code"=$17,"is_code_only"=$18,"is_repeatable"=$19,"max_repeat"=$20,"is_exclusive"=$21,"stack_group"=$22,"priority"=$23,"timezone"=$24,"status"=$25,"reviewed_by"=$26,"reviewed_at"=$27,"rejection_reason"=$28,"new_user_days"=$29,"lapsed_days"=$30,"order_number"=$31,"rollout_percentage"=$32,"created_at"=$33,"updated_at"=$34 WHERE "id" = $35`)
+				mock.ExpectExec(query).
+					WithArgs("name", "description", constants.PROMOSEGMENTATIONALL, constants.PROMOTYPEBUYXGETY,
+						float64(1), float64(1), float64(1),
+						buyProductID, freeProductID, 1, 1,
+						sqlmock.AnyArg(), sqlmock.AnyArg(), // start_date, end_date
+						1, 1, // max_usage_limit, max_usage_per_user
+						maxBudgetAmount,
+						code, false,
+						true, 2,
+						false, stackGroup, 1,
+						constants.PROMODEFAULTTIMEZONE, constants.PROMOSTATUSAPPROVED,
+						nil, nil, nil, // reviewed_by, reviewed_at, rejection_reason
+						nil, nil, nil, nil, // new_user_days, lapsed_days, order_number, rollout_percentage
+						sqlmock.AnyArg(), sqlmock.AnyArg(), // created_at, updated_at
+						uint(1)).
+					WillReturnError(errors.New("error"))
+
+				mock.ExpectRollback()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sqlmock: %v", err)
+			}
+			defer sqlDB.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{
+				Conn: sqlDB,
+			}), &gorm.Config{
+				Logger: logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				t.Fatalf("failed to open gorm connection: %v", err)
+			}
+
+			tt.sqlMock(mock)
+
+			r := NewPromoRepository(gormDB)
+			if err := r.Save(tt.args.ctx, gormDB, tt.args.promo); (err != nil) != tt.wantErr {
+				t.Errorf("promoRepostory.Save() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_promoRepostory_IncrementUsage(t *testing.T) {
+	type args struct {
+		ctx     context.Context
+		tx      *gorm.DB
+		promoID uint
+		amount  float64
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    bool
+		wantErr bool
+		sqlMock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			args: args{
+				ctx:     context.Background(),
+				tx:      nil,
+				promoID: 1,
+				amount:  1000,
+			},
+			want:    true,
+			wantErr: false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				query := regexp.QuoteMeta(`UPDATE "promos" SET "current_usage_count"=current_usage_count + 1,"spent_amount"=spent_amount + $1,"updated_at"=$2 WHERE id = $3 and (max_usage_limit is null or current_usage_count < max_usage_limit) and (max_budget_amount is null or spent_amount + $4 <= max_budget_amount)`)
+				mock.ExpectExec(query).
+					WithArgs(float64(1000), sqlmock.AnyArg(), 1, float64(1000)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "usage limit or budget reached",
+			args: args{
+				ctx:     context.Background(),
+				tx:      nil,
+				promoID: 1,
+				amount:  1000,
+			},
+			want:    false,
+			wantErr: false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				query := regexp.QuoteMeta(`UPDATE "promos" SET "current_usage_count"=current_usage_count + 1,"spent_amount"=spent_amount + $1,"updated_at"=$2 WHERE id = $3 and (max_usage_limit is null or current_usage_count < max_usage_limit) and (max_budget_amount is null or spent_amount + $4 <= max_budget_amount)`)
+				mock.ExpectExec(query).
+					WithArgs(float64(1000), sqlmock.AnyArg(), 1, float64(1000)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "error",
+			args: args{
+				ctx:     context.Background(),
+				tx:      nil,
+				promoID: 1,
+				amount:  1000,
+			},
+			want:    false,
+			wantErr: true,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				query := regexp.QuoteMeta(`UPDATE "promos" SET "current_usage_count"=current_usage_count + 1,"spent_amount"=spent_amount + $1,"updated_at"=$2 WHERE id = $3 and (max_usage_limit is null or current_usage_count < max_usage_limit) and (max_budget_amount is null or spent_amount + $4 <= max_budget_amount)`)
+				mock.ExpectExec(query).
+					WithArgs(float64(1000), sqlmock.AnyArg(), 1, float64(1000)).
+					WillReturnError(errors.New("error"))
+				mock.ExpectRollback()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sqlmock: %v", err)
+			}
+			defer sqlDB.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{
+				Conn: sqlDB,
+			}), &gorm.Config{
+				Logger: logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				t.Fatalf("failed to open gorm connection: %v", err)
+			}
+
+			tt.sqlMock(mock)
+
+			r := NewPromoRepository(gormDB)
+			got, err := r.IncrementUsage(tt.args.ctx, tt.args.tx, tt.args.promoID, tt.args.amount)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("promoRepostory.IncrementUsage() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("promoRepostory.IncrementUsage() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_promoRepostory_GetUserUsageCount(t *testing.T) {
+	type args struct {
+		ctx     context.Context
+		tx      *gorm.DB
+		userID  uint
+		promoID uint
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    int
+		wantErr bool
+		sqlMock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			args: args{
+				ctx:     context.Background(),
+				tx:      nil,
+				userID:  1,
+				promoID: 2,
+			},
+			want:    3,
+			wantErr: false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				query := regexp.QuoteMeta(`SELECT count(*) FROM "order_promos" join orders on orders.id = order_promos.order_id WHERE order_promos.promo_id = $1 and orders.user_id = $2`)
+				mock.ExpectQuery(query).
+					WithArgs(2, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+			},
+		},
+		{
+			name: "error",
+			args: args{
+				ctx:     context.Background(),
+				tx:      nil,
+				userID:  1,
+				promoID: 2,
+			},
+			want:    0,
+			wantErr: true,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				query := regexp.QuoteMeta(`SELECT count(*) FROM "order_promos" join orders on orders.id = order_promos.order_id WHERE order_promos.promo_id = $1 and orders.user_id = $2`)
+				mock.ExpectQuery(query).
+					WithArgs(2, 1).
+					WillReturnError(errors.New("error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sqlmock: %v", err)
+			}
+			defer sqlDB.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{
+				Conn: sqlDB,
+			}), &gorm.Config{
+				Logger: logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				t.Fatalf("failed to open gorm connection: %v", err)
+			}
+
+			tt.sqlMock(mock)
+
+			r := NewPromoRepository(gormDB)
+			got, err := r.GetUserUsageCount(tt.args.ctx, tt.args.tx, tt.args.userID, tt.args.promoID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("promoRepostory.GetUserUsageCount() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("promoRepostory.GetUserUsageCount() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_promoRepostory_GetCities(t *testing.T) {
+	type args struct {
+		ctx     context.Context
+		tx      *gorm.DB
+		promoID uint
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []models.PromoCity
+		wantErr bool
+		sqlMock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			args: args{
+				ctx:     context.Background(),
+				tx:      nil,
+				promoID: 1,
+			},
+			want: []models.PromoCity{
+				{ID: 1, PromoID: 1, City: "Jakarta"},
+				{ID: 2, PromoID: 1, City: "Bandung"},
+			},
+			wantErr: false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				query := regexp.QuoteMeta(`SELECT * FROM "promo_cities" WHERE promo_id = $1 ORDER BY id`)
+				mock.ExpectQuery(query).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "promo_id", "city"}).
+						AddRow(1, 1, "Jakarta").
+						AddRow(2, 1, "Bandung"))
+			},
+		},
+		{
+			name: "err get cities",
+			args: args{
+				ctx:     context.Background(),
+				tx:      nil,
+				promoID: 1,
+			},
+			want:    nil,
+			wantErr: true,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				query := regexp.QuoteMeta(`SELECT * FROM "promo_cities" WHERE promo_id = $1 ORDER BY id`)
+				mock.ExpectQuery(query).
+					WithArgs(1).
+					WillReturnError(errors.New("error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sqlmock: %v", err)
+			}
+			defer sqlDB.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{
+				Conn: sqlDB,
+			}), &gorm.Config{
+				Logger: logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				t.Fatalf("failed to open gorm connection: %v", err)
+			}
+
+			tt.sqlMock(mock)
+
+			r := NewPromoRepository(gormDB)
+			got, err := r.GetCities(tt.args.ctx, tt.args.tx, tt.args.promoID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("promoRepostory.GetCities() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("promoRepostory.GetCities() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_promoRepostory_RemoveCity(t *testing.T) {
+	type args struct {
+		ctx     context.Context
+		tx      *gorm.DB
+		promoID uint
+		city    string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    bool
+		wantErr bool
+		sqlMock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			args: args{
+				ctx:     context.Background(),
+				tx:      nil,
+				promoID: 1,
+				city:    "jakarta",
+			},
+			want:    true,
+			wantErr: false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				query := regexp.QuoteMeta(`DELETE FROM "promo_cities" WHERE promo_id = $1 and lower(city) = lower($2)`)
+				mock.ExpectExec(query).
+					WithArgs(1, "jakarta").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "city not found",
+			args: args{
+				ctx:     context.Background(),
+				tx:      nil,
+				promoID: 1,
+				city:    "Bandung",
+			},
+			want:    false,
+			wantErr: false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				query := regexp.QuoteMeta(`DELETE FROM "promo_cities" WHERE promo_id = $1 and lower(city) = lower($2)`)
+				mock.ExpectExec(query).
+					WithArgs(1, "Bandung").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "error",
+			args: args{
+				ctx:     context.Background(),
+				tx:      nil,
+				promoID: 1,
+				city:    "Jakarta",
+			},
+			want:    false,
+			wantErr: true,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				query := regexp.QuoteMeta(`DELETE FROM "promo_cities" WHERE promo_id = $1 and lower(city) = lower($2)`)
+				mock.ExpectExec(query).
+					WithArgs(1, "Jakarta").
+					WillReturnError(errors.New("error"))
+				mock.ExpectRollback()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sqlmock: %v", err)
+			}
+			defer sqlDB.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{
+				Conn: sqlDB,
+			}), &gorm.Config{
+				Logger: logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				t.Fatalf("failed to open gorm connection: %v", err)
+			}
+
+			tt.sqlMock(mock)
+
+			r := NewPromoRepository(gormDB)
+			got, err := r.RemoveCity(tt.args.ctx, tt.args.tx, tt.args.promoID, tt.args.city)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("promoRepostory.RemoveCity() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("promoRepostory.RemoveCity() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_promoRepostory_SaveUsers(t *testing.T) {
+	type args struct {
+		ctx     context.Context
+		tx      *gorm.DB
+		promoID uint
+		userIDs []uint
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    int64
+		wantErr bool
+		sqlMock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			args: args{
+				ctx:     context.Background(),
+				tx:      nil,
+				promoID: 1,
+				userIDs: []uint{2, 3},
+			},
+			want:    2,
+			wantErr: false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				query := regexp.QuoteMeta(`INSERT INTO promo_users (promo_id, user_id) SELECT $1, id FROM users WHERE id IN ($2,$3) ON CONFLICT (promo_id, user_id) DO NOTHING`)
+				mock.ExpectExec(query).
+					WithArgs(1, 2, 3).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+		},
+		{
+			name: "error",
+			args: args{
+				ctx:     context.Background(),
+				tx:      nil,
+				promoID: 1,
+				userIDs: []uint{2, 3},
+			},
+			want:    0,
+			wantErr: true,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				query := regexp.QuoteMeta(`INSERT INTO promo_users (promo_id, user_id) SELECT $1, id FROM users WHERE id IN ($2,$3) ON CONFLICT (promo_id, user_id) DO NOTHING`)
+				mock.ExpectExec(query).
+					WithArgs(1, 2, 3).
+					WillReturnError(errors.New("error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sqlmock: %v", err)
+			}
+			defer sqlDB.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{
+				Conn: sqlDB,
+			}), &gorm.Config{
+				Logger: logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				t.Fatalf("failed to open gorm connection: %v", err)
+			}
+
+			tt.sqlMock(mock)
+
+			r := NewPromoRepository(gormDB)
+			got, err := r.SaveUsers(tt.args.ctx, tt.args.tx, tt.args.promoID, tt.args.userIDs)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("promoRepostory.SaveUsers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("promoRepostory.SaveUsers() = %v, want %v", got, tt.want)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_promoRepostory_RemoveUsers(t *testing.T) {
+	type args struct {
+		ctx     context.Context
+		tx      *gorm.DB
+		promoID uint
+		userIDs []uint
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    int64
+		wantErr bool
+		sqlMock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			args: args{
+				ctx:     context.Background(),
+				tx:      nil,
+				promoID: 1,
+				userIDs: []uint{2, 3},
+			},
+			want:    2,
+			wantErr: false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				query := regexp.QuoteMeta(`DELETE FROM "promo_users" WHERE promo_id = $1 and user_id in ($2,$3)`)
+				mock.ExpectExec(query).
+					WithArgs(1, 2, 3).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "error",
+			args: args{
+				ctx:     context.Background(),
+				tx:      nil,
+				promoID: 1,
+				userIDs: []uint{2, 3},
+			},
+			want:    0,
+			wantErr: true,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				query := regexp.QuoteMeta(`DELETE FROM "promo_users" WHERE promo_id = $1 and user_id in ($2,$3)`)
+				mock.ExpectExec(query).
+					WithArgs(1, 2, 3).
+					WillReturnError(errors.New("error"))
+				mock.ExpectRollback()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sqlmock: %v", err)
+			}
+			defer sqlDB.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{
+				Conn: sqlDB,
+			}), &gorm.Config{
+				Logger: logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				t.Fatalf("failed to open gorm connection: %v", err)
+			}
+
+			tt.sqlMock(mock)
+
+			r := NewPromoRepository(gormDB)
+			got, err := r.RemoveUsers(tt.args.ctx, tt.args.tx, tt.args.promoID, tt.args.userIDs)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("promoRepostory.RemoveUsers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("promoRepostory.RemoveUsers() = %v, want %v", got, tt.want)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_promoRepostory_GetUsers(t *testing.T) {
+	type args struct {
+		ctx     context.Context
+		tx      *gorm.DB
+		promoID uint
+		page    int
+		perPage int
+	}
+	tests := []struct {
+		name      string
+		args      args
+		want      []models.PromoUser
+		wantTotal int64
+		wantErr   bool
+		sqlMock   func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			args: args{
+				ctx:     context.Background(),
+				tx:      nil,
+				promoID: 1,
+				page:    2,
+				perPage: 2,
+			},
+			want:      []models.PromoUser{{ID: 3, PromoID: 1, UserID: 7}},
+			wantTotal: 3,
+			wantErr:   false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				countQuery := regexp.QuoteMeta(`SELECT count(*) FROM "promo_users" WHERE promo_id = $1`)
+				mock.ExpectQuery(countQuery).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+				query := regexp.QuoteMeta(`SELECT * FROM "promo_users" WHERE promo_id = $1 ORDER BY user_id LIMIT $2 OFFSET $3`)
+				mock.ExpectQuery(query).
+					WithArgs(1, 2, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "promo_id", "user_id"}).AddRow(3, 1, 7))
+			},
+		},
+		{
+			name: "error count",
+			args: args{
+				ctx:     context.Background(),
+				tx:      nil,
+				promoID: 1,
+				page:    1,
+				perPage: 10,
+			},
+			want:      nil,
+			wantTotal: 0,
+			wantErr:   true,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				countQuery := regexp.QuoteMeta(`SELECT count(*) FROM "promo_users" WHERE promo_id = $1`)
+				mock.ExpectQuery(countQuery).
+					WithArgs(1).
+					WillReturnError(errors.New("error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sqlmock: %v", err)
+			}
+			defer sqlDB.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{
+				Conn: sqlDB,
+			}), &gorm.Config{
+				Logger: logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				t.Fatalf("failed to open gorm connection: %v", err)
+			}
+
+			tt.sqlMock(mock)
+
+			r := NewPromoRepository(gormDB)
+			got, total, err := r.GetUsers(tt.args.ctx, tt.args.tx, tt.args.promoID, tt.args.page, tt.args.perPage)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("promoRepostory.GetUsers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("promoRepostory.GetUsers() = %v, want %v", got, tt.want)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("promoRepostory.GetUsers() total = %v, want %v", total, tt.wantTotal)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_promoRepostory_FindUsers(t *testing.T) {
+	type args struct {
+		ctx     context.Context
+		tx      *gorm.DB
+		promoID uint
+		userIDs []uint
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []models.PromoUser
+		wantErr bool
+		sqlMock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			args: args{
+				ctx:     context.Background(),
+				tx:      nil,
+				promoID: 1,
+				userIDs: []uint{2},
+			},
+			want:    []models.PromoUser{{ID: 1, PromoID: 1, UserID: 2}},
+			wantErr: false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				query := regexp.QuoteMeta(`SELECT * FROM "promo_users" WHERE promo_id = $1 and user_id in ($2)`)
+				mock.ExpectQuery(query).
+					WithArgs(1, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "promo_id", "user_id"}).AddRow(1, 1, 2))
+			},
+		},
+		{
+			name: "error",
+			args: args{
+				ctx:     context.Background(),
+				tx:      nil,
+				promoID: 1,
+				userIDs: []uint{2},
+			},
+			want:    nil,
+			wantErr: true,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				query := regexp.QuoteMeta(`SELECT * FROM "promo_users" WHERE promo_id = $1 and user_id in ($2)`)
+				mock.ExpectQuery(query).
+					WithArgs(1, 2).
+					WillReturnError(errors.New("error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sqlmock: %v", err)
+			}
+			defer sqlDB.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{
+				Conn: sqlDB,
+			}), &gorm.Config{
+				Logger: logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				t.Fatalf("failed to open gorm connection: %v", err)
+			}
+
+			tt.sqlMock(mock)
+
+			r := NewPromoRepository(gormDB)
+			got, err := r.FindUsers(tt.args.ctx, tt.args.tx, tt.args.promoID, tt.args.userIDs)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("promoRepostory.FindUsers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("promoRepostory.FindUsers() = %v, want %v", got, tt.want)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_promoRepostory_SaveSchedules(t *testing.T) {
+	timeNow := time.Now()
+
+	type args struct {
+		ctx       context.Context
+		tx        *gorm.DB
+		schedules []models.PromoSchedule
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+		sqlMock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			args: args{
+				ctx: context.Background(),
+				tx:  nil,
+				schedules: []models.PromoSchedule{
+					{PromoID: 1, DayOfWeek: 1, StartTime: "14:00", EndTime: "17:00"},
+					{PromoID: 1, DayOfWeek: 2, StartTime: "14:00", EndTime: "17:00"},
+				},
+			},
+			wantErr: false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				query := regexp.QuoteMeta(`INSERT INTO "promo_schedules" ("promo_id","day_of_week","start_time","end_time") VALUES ($1,$2,$3,$4),($5,$6,$7,$8) RETURNING "created_at","updated_at","id"`)
+				mock.ExpectQuery(query).
+					WithArgs(1, 1, "14:00", "17:00", 1, 2, "14:00", "17:00").
+					WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at", "id"}).
+						AddRow(timeNow, timeNow, 1).
+						AddRow(timeNow, timeNow, 2))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "err save schedules",
+			args: args{
+				ctx: context.Background(),
+				tx:  nil,
+				schedules: []models.PromoSchedule{
+					{PromoID: 1, DayOfWeek: 1, StartTime: "14:00", EndTime: "17:00"},
+				},
+			},
+			wantErr: true,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				query := regexp.QuoteMeta(`INSERT INTO "promo_schedules" ("promo_id","day_of_week","start_time","end_time") VALUES ($1,$2,$3,$4) RETURNING "created_at","updated_at","id"`)
+				mock.ExpectQuery(query).
+					WithArgs(1, 1, "14:00", "17:00").
+					WillReturnError(errors.New("error"))
+				mock.ExpectRollback()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sqlmock: %v", err)
+			}
+			defer sqlDB.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{
+				Conn: sqlDB,
+			}), &gorm.Config{
+				Logger: logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				t.Fatalf("failed to open gorm connection: %v", err)
+			}
+
+			tt.sqlMock(mock)
+
+			r := NewPromoRepository(gormDB)
+			if err := r.SaveSchedules(tt.args.ctx, tt.args.tx, tt.args.schedules); (err != nil) != tt.wantErr {
+				t.Errorf("promoRepostory.SaveSchedules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_promoRepostory_GetSchedules(t *testing.T) {
+	type args struct {
+		ctx     context.Context
+		tx      *gorm.DB
+		promoID uint
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []models.PromoSchedule
+		wantErr bool
+		sqlMock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			args: args{
+				ctx:     context.Background(),
+				tx:      nil,
+				promoID: 1,
+			},
+			want: []models.PromoSchedule{
+				{ID: 1, PromoID: 1, DayOfWeek: 1, StartTime: "14:00", EndTime: "17:00"},
+				{ID: 2, PromoID: 1, DayOfWeek: 5, StartTime: "22:00", EndTime: "02:00"},
+			},
+			wantErr: false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				query := regexp.QuoteMeta(`SELECT * FROM "promo_schedules" WHERE promo_id = $1 ORDER BY day_of_week, start_time`)
+				mock.ExpectQuery(query).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "promo_id", "day_of_week", "start_time", "end_time"}).
+						AddRow(1, 1, 1, "14:00", "17:00").
+						AddRow(2, 1, 5, "22:00", "02:00"))
+			},
+		},
+		{
+			name: "err get schedules",
+			args: args{
+				ctx:     context.Background(),
+				tx:      nil,
+				promoID: 1,
+			},
+			want:    nil,
+			wantErr: true,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				query := regexp.QuoteMeta(`SELECT * FROM "promo_schedules" WHERE promo_id = $1 ORDER BY day_of_week, start_time`)
+				mock.ExpectQuery(query).
+					WithArgs(1).
+					WillReturnError(errors.New("error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sqlmock: %v", err)
+			}
+			defer sqlDB.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{
+				Conn: sqlDB,
+			}), &gorm.Config{
+				Logger: logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				t.Fatalf("failed to open gorm connection: %v", err)
+			}
+
+			tt.sqlMock(mock)
+
+			r := NewPromoRepository(gormDB)
+			got, err := r.GetSchedules(tt.args.ctx, tt.args.tx, tt.args.promoID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("promoRepostory.GetSchedules() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("promoRepostory.GetSchedules() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_promoRepostory_SaveProducts(t *testing.T) {
+	timeNow := time.Now()
+
+	type args struct {
+		ctx        context.Context
+		tx         *gorm.DB
+		promoID    uint
+		productIDs []uint
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+		sqlMock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			args: args{
+				ctx:        context.Background(),
+				tx:         nil,
+				promoID:    1,
+				productIDs: []uint{2, 3},
+			},
+			wantErr: false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				query := regexp.QuoteMeta(`INSERT INTO "promo_products" ("promo_id","product_id") VALUES ($1,$2),($3,$4) RETURNING "created_at","updated_at","id"`)
+				mock.ExpectQuery(query).
+					WithArgs(1, 2, 1, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at", "id"}).
+						AddRow(timeNow, timeNow, 1).
+						AddRow(timeNow, timeNow, 2))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "err save products",
+			args: args{
+				ctx:        context.Background(),
+				tx:         nil,
+				promoID:    1,
+				productIDs: []uint{2},
+			},
+			wantErr: true,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				query := regexp.QuoteMeta(`INSERT INTO "promo_products" ("promo_id","product_id") VALUES ($1,$2) RETURNING "created_at","updated_at","id"`)
+				mock.ExpectQuery(query).
+					WithArgs(1, 2).
+					WillReturnError(errors.New("error"))
+				mock.ExpectRollback()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sqlmock: %v", err)
+			}
+			defer sqlDB.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{
+				Conn: sqlDB,
+			}), &gorm.Config{
+				Logger: logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				t.Fatalf("failed to open gorm connection: %v", err)
+			}
+
+			tt.sqlMock(mock)
+
+			r := NewPromoRepository(gormDB)
+			if err := r.SaveProducts(tt.args.ctx, tt.args.tx, tt.args.promoID, tt.args.productIDs); (err != nil) != tt.wantErr {
+				t.Errorf("promoRepostory.SaveProducts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_promoRepostory_GetProducts(t *testing.T) {
+	type args struct {
+		ctx     context.Context
+		tx      *gorm.DB
+		promoID uint
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []models.PromoProduct
+		wantErr bool
+		sqlMock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			args: args{
+				ctx:     context.Background(),
+				tx:      nil,
+				promoID: 1,
+			},
+			want: []models.PromoProduct{
+				{ID: 1, PromoID: 1, ProductID: 2},
+				{ID: 2, PromoID: 1, ProductID: 3},
+			},
+			wantErr: false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				query := regexp.QuoteMeta(`SELECT * FROM "promo_products" WHERE promo_id = $1 ORDER BY id`)
+				mock.ExpectQuery(query).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "promo_id", "product_id"}).
+						AddRow(1, 1, 2).
+						AddRow(2, 1, 3))
+			},
+		},
+		{
+			name: "err get products",
+			args: args{
+				ctx:     context.Background(),
+				tx:      nil,
+				promoID: 1,
+			},
+			want:    nil,
+			wantErr: true,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				query := regexp.QuoteMeta(`SELECT * FROM "promo_products" WHERE promo_id = $1 ORDER BY id`)
+				mock.ExpectQuery(query).
+					WithArgs(1).
+					WillReturnError(errors.New("error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sqlmock: %v", err)
+			}
+			defer sqlDB.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{
+				Conn: sqlDB,
+			}), &gorm.Config{
+				Logger: logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				t.Fatalf("failed to open gorm connection: %v", err)
+			}
+
+			tt.sqlMock(mock)
+
+			r := NewPromoRepository(gormDB)
+			got, err := r.GetProducts(tt.args.ctx, tt.args.tx, tt.args.promoID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("promoRepostory.GetProducts() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("promoRepostory.GetProducts() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_promoRepostory_SaveTiers(t *testing.T) {
+	timeNow := time.Now()
+
+	type args struct {
+		ctx   context.Context
+		tx    *gorm.DB
+		tiers []models.PromoTier
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+		sqlMock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			args: args{
+				ctx: context.Background(),
+				tx:  nil,
+				tiers: []models.PromoTier{
+					{PromoID: 1, MinAmount: 50000, DiscountType: constants.PROMOTIERPERCENTAGE, DiscountValue: 5},
+					{PromoID: 1, MinAmount: 100000, DiscountType: constants.PROMOTIERFIXED, DiscountValue: 10000},
+				},
+			},
+			wantErr: false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				query := regexp.QuoteMeta(`INSERT INTO "promo_tiers" ("promo_id","min_amount","discount_type","discount_value","max_discount_amount") VALUES ($1,$2,$3,$4,$5),($6,$7,$8,$9,$10) RETURNING "created_at","updated_at","id"`)
+				mock.ExpectQuery(query).
+					WithArgs(1, float64(50000), constants.PROMOTIERPERCENTAGE, float64(5), nil, 1, float64(100000), constants.PROMOTIERFIXED, float64(10000), nil).
+					WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at", "id"}).
+						AddRow(timeNow, timeNow, 1).
+						AddRow(timeNow, timeNow, 2))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "err save tiers",
+			args: args{
+				ctx: context.Background(),
+				tx:  nil,
+				tiers: []models.PromoTier{
+					{PromoID: 1, MinAmount: 50000, DiscountType: constants.PROMOTIERPERCENTAGE, DiscountValue: 5},
+				},
+			},
+			wantErr: true,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				query := regexp.QuoteMeta(`INSERT INTO "promo_tiers" ("promo_id","min_amount","discount_type","discount_value","max_discount_amount") VALUES ($1,$2,$3,$4,$5) RETURNING "created_at","updated_at","id"`)
+				mock.ExpectQuery(query).
+					WithArgs(1, float64(50000), constants.PROMOTIERPERCENTAGE, float64(5), nil).
+					WillReturnError(errors.New("error"))
+				mock.ExpectRollback()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sqlmock: %v", err)
+			}
+			defer sqlDB.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{
+				Conn: sqlDB,
+			}), &gorm.Config{
+				Logger: logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				t.Fatalf("failed to open gorm connection: %v", err)
+			}
+
+			tt.sqlMock(mock)
+
+			r := NewPromoRepository(gormDB)
+			if err := r.SaveTiers(tt.args.ctx, tt.args.tx, tt.args.tiers); (err != nil) != tt.wantErr {
+				t.Errorf("promoRepostory.SaveTiers() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_promoRepostory_GetTiers(t *testing.T) {
+	type args struct {
+		ctx     context.Context
+		tx      *gorm.DB
+		promoID uint
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []models.PromoTier
+		wantErr bool
+		sqlMock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			args: args{
+				ctx:     context.Background(),
+				tx:      nil,
+				promoID: 1,
+			},
+			want: []models.PromoTier{
+				{ID: 1, PromoID: 1, MinAmount: 50000, DiscountType: constants.PROMOTIERPERCENTAGE, DiscountValue: 5},
+				{ID: 2, PromoID: 1, MinAmount: 100000, DiscountType: constants.PROMOTIERFIXED, DiscountValue: 10000},
+			},
+			wantErr: false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				query := regexp.QuoteMeta(`SELECT * FROM "promo_tiers" WHERE promo_id = $1 ORDER BY min_amount`)
+				mock.ExpectQuery(query).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "promo_id", "min_amount", "discount_type", "discount_value"}).
+						AddRow(1, 1, 50000, constants.PROMOTIERPERCENTAGE, 5).
+						AddRow(2, 1, 100000, constants.PROMOTIERFIXED, 10000))
+			},
+		},
+		{
+			name: "err get tiers",
+			args: args{
+				ctx:     context.Background(),
+				tx:      nil,
+				promoID: 1,
+			},
+			want:    nil,
+			wantErr: true,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				query := regexp.QuoteMeta(`SELECT * FROM "promo_tiers" WHERE promo_id = $1 ORDER BY min_amount`)
+				mock.ExpectQuery(query).
+					WithArgs(1).
+					WillReturnError(errors.New("error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sqlmock: %v", err)
+			}
+			defer sqlDB.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{
+				Conn: sqlDB,
+			}), &gorm.Config{
+				Logger: logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				t.Fatalf("failed to open gorm connection: %v", err)
+			}
+
+			tt.sqlMock(mock)
+
+			r := NewPromoRepository(gormDB)
+			got, err := r.GetTiers(tt.args.ctx, tt.args.tx, tt.args.promoID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("promoRepostory.GetTiers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("promoRepostory.GetTiers() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_promoRepostory_SaveVariants(t *testing.T) {
+	timeNow := time.Now()
+
+	type args struct {
+		ctx      context.Context
+		tx       *gorm.DB
+		variants []models.PromoVariant
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+		sqlMock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			args: args{
+				ctx: context.Background(),
+				tx:  nil,
+				variants: []models.PromoVariant{
+					{PromoID: 1, Name: "A", Weight: 1, DiscountValue: 15},
+					{PromoID: 1, Name: "B", Weight: 1, DiscountValue: 20},
+				},
+			},
+			wantErr: false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				query := regexp.QuoteMeta(`INSERT INTO "promo_variants" ("promo_id","name","weight","discount_value","max_discount_amount") VALUES ($1,$2,$3,$4,$5),($6,$7,$8,$9,$10) RETURNING "created_at","updated_at","id"`)
+				mock.ExpectQuery(query).
+					WithArgs(1, "A", 1, float64(15), nil, 1, "B", 1, float64(20), nil).
+					WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at", "id"}).
+						AddRow(timeNow, timeNow, 1).
+						AddRow(timeNow, timeNow, 2))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "err save variants",
+			args: args{
+				ctx: context.Background(),
+				tx:  nil,
+				variants: []models.PromoVariant{
+					{PromoID: 1, Name: "A", Weight: 1, DiscountValue: 15},
+				},
+			},
+			wantErr: true,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				query := regexp.QuoteMeta(`INSERT INTO "promo_variants" ("promo_id","name","weight","discount_value","max_discount_amount") VALUES ($1,$2,$3,$4,$5) RETURNING "created_at","updated_at","id"`)
+				mock.ExpectQuery(query).
+					WithArgs(1, "A", 1, float64(15), nil).
+					WillReturnError(errors.New("error"))
+				mock.ExpectRollback()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sqlmock: %v", err)
+			}
+			defer sqlDB.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{
+				Conn: sqlDB,
+			}), &gorm.Config{
+				Logger: logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				t.Fatalf("failed to open gorm connection: %v", err)
+			}
+
+			tt.sqlMock(mock)
+
+			r := NewPromoRepository(gormDB)
+			if err := r.SaveVariants(tt.args.ctx, tt.args.tx, tt.args.variants); (err != nil) != tt.wantErr {
+				t.Errorf("promoRepostory.SaveVariants() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_promoRepostory_SaveAuditLog(t *testing.T) {
+	timeNow := time.Now()
+	actor := "jane@hangry.id"
+	changes := json.RawMessage(`{"end_date":{"before":"2024-06-30T23:59:59Z","after":"2024-07-31T23:59:59Z"}}`)
+
+	type args struct {
+		ctx context.Context
+		tx  *gorm.DB
+		log *models.PromoAuditLog
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+		sqlMock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			args: args{
+				ctx: context.Background(),
+				tx:  nil,
+				log: &models.PromoAuditLog{PromoID: 1, Action: constants.PROMOAUDITACTIONEXTEND, Actor: &actor, Changes: changes},
+			},
+			wantErr: false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				query := regexp.QuoteMeta(`INSERT INTO "promo_audit_logs" ("promo_id","action","actor","changes") VALUES ($1,$2,$3,$4) RETURNING "created_at","id"`)
+				mock.ExpectQuery(query).
+					WithArgs(1, constants.PROMOAUDITACTIONEXTEND, actor, changes).
+					WillReturnRows(sqlmock.NewRows([]string{"created_at", "id"}).AddRow(timeNow, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "err save audit log",
+			args: args{
+				ctx: context.Background(),
+				tx:  nil,
+				log: &models.PromoAuditLog{PromoID: 1, Action: constants.PROMOAUDITACTIONEXTEND, Changes: changes},
+			},
+			wantErr: true,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				query := regexp.QuoteMeta(`INSERT INTO "promo_audit_logs" ("promo_id","action","actor","changes") VALUES ($1,$2,$3,$4) RETURNING "created_at","id"`)
+				mock.ExpectQuery(query).
+					WithArgs(1, constants.PROMOAUDITACTIONEXTEND, nil, changes).
+					WillReturnError(errors.New("error"))
+				mock.ExpectRollback()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sqlmock: %v", err)
+			}
+			defer sqlDB.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{
+				Conn: sqlDB,
+			}), &gorm.Config{
+				Logger: logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				t.Fatalf("failed to open gorm connection: %v", err)
+			}
+
+			tt.sqlMock(mock)
+
+			r := NewPromoRepository(gormDB)
+			if err := r.SaveAuditLog(tt.args.ctx, tt.args.tx, tt.args.log); (err != nil) != tt.wantErr {
+				t.Errorf("promoRepostory.SaveAuditLog() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_promoRepostory_GetAuditLogs(t *testing.T) {
+	type args struct {
+		ctx     context.Context
+		tx      *gorm.DB
+		promoID uint
+		page    int
+		perPage int
+	}
+	tests := []struct {
+		name      string
+		args      args
+		want      []models.PromoAuditLog
+		wantTotal int64
+		wantErr   bool
+		sqlMock   func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			args: args{
+				ctx:     context.Background(),
+				tx:      nil,
+				promoID: 1,
+				page:    2,
+				perPage: 2,
+			},
+			want:      []models.PromoAuditLog{{ID: 1, PromoID: 1, Action: constants.PROMOAUDITACTIONCREATE, Changes: json.RawMessage(`{}`)}},
+			wantTotal: 3,
+			wantErr:   false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				countQuery := regexp.QuoteMeta(`SELECT count(*) FROM "promo_audit_logs" WHERE promo_id = $1`)
+				mock.ExpectQuery(countQuery).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+				query := regexp.QuoteMeta(`SELECT * FROM "promo_audit_logs" WHERE promo_id = $1 ORDER BY id desc LIMIT $2 OFFSET $3`)
+				mock.ExpectQuery(query).
+					WithArgs(1, 2, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "promo_id", "action", "changes"}).AddRow(1, 1, constants.PROMOAUDITACTIONCREATE, []byte(`{}`)))
+			},
+		},
+		{
+			name: "error count",
+			args: args{
+				ctx:     context.Background(),
+				tx:      nil,
+				promoID: 1,
+				page:    1,
+				perPage: 10,
+			},
+			want:      nil,
+			wantTotal: 0,
+			wantErr:   true,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				countQuery := regexp.QuoteMeta(`SELECT count(*) FROM "promo_audit_logs" WHERE promo_id = $1`)
+				mock.ExpectQuery(countQuery).
+					WithArgs(1).
+					WillReturnError(errors.New("error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sqlmock: %v", err)
+			}
+			defer sqlDB.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{
+				Conn: sqlDB,
+			}), &gorm.Config{
+				Logger: logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				t.Fatalf("failed to open gorm connection: %v", err)
+			}
+
+			tt.sqlMock(mock)
+
+			r := NewPromoRepository(gormDB)
+			got, total, err := r.GetAuditLogs(tt.args.ctx, tt.args.tx, tt.args.promoID, tt.args.page, tt.args.perPage)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("promoRepostory.GetAuditLogs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("promoRepostory.GetAuditLogs() = %v, want %v", got, tt.want)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("promoRepostory.GetAuditLogs() total = %v, want %v", total, tt.wantTotal)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_promoRepostory_GetPromoDetail(t *testing.T) {
+	type args struct {
+		ctx     context.Context
+		tx      *gorm.DB
+		promoID uint
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    models.Promo
+		wantErr bool
+		sqlMock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			args: args{
+				ctx:     context.Background(),
+				tx:      nil,
+				promoID: 1,
+			},
+			want: models.Promo{
+				ID:     1,
+				Status: constants.PROMOSTATUSAPPROVED,
+				PromoCities: []models.PromoCity{
+					{ID: 1, PromoID: 1, City: "Jakarta"},
+				},
+				PromoProducts:  []models.PromoProduct{},
+				PromoSchedules: []models.PromoSchedule{},
+				PromoTiers:     []models.PromoTier{},
+				PromoVariants:  []models.PromoVariant{},
+			},
+			wantErr: false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				query := regexp.QuoteMeta(`SELECT * FROM "promos" WHERE id = $1 ORDER BY "promos"."id" LIMIT $2`)
+				mock.ExpectQuery(query).
+					WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).
+						AddRow(1, constants.PROMOSTATUSAPPROVED))
+
+				citiesQuery := regexp.QuoteMeta(`SELECT * FROM "promo_cities" WHERE "promo_cities"."promo_id" = $1 ORDER BY id`)
+				mock.ExpectQuery(citiesQuery).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "promo_id", "city"}).
+						AddRow(1, 1, "Jakarta"))
+
+				productsQuery := regexp.QuoteMeta(`SELECT * FROM "promo_products" WHERE "promo_products"."promo_id" = $1 ORDER BY id`)
+				mock.ExpectQuery(productsQuery).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "promo_id", "product_id"}))
+
+				schedulesQuery := regexp.QuoteMeta(`SELECT * FROM "promo_schedules" WHERE "promo_schedules"."promo_id" = $1 ORDER BY day_of_week, start_time`)
+				mock.ExpectQuery(schedulesQuery).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "promo_id", "day_of_week", "start_time", "end_time"}))
+
+				tiersQuery := regexp.QuoteMeta(`SELECT * FROM "promo_tiers" WHERE "promo_tiers"."promo_id" = $1 ORDER BY min_amount`)
+				mock.ExpectQuery(tiersQuery).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "promo_id", "min_amount", "discount_type", "discount_value"}))
+
+				variantsQuery := regexp.QuoteMeta(`SELECT * FROM "promo_variants" WHERE "promo_variants"."promo_id" = $1 ORDER BY id`)
+				mock.ExpectQuery(variantsQuery).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "promo_id", "name", "weight", "discount_value"}))
+			},
+		},
+		{
+			name: "not found",
+			args: args{
+				ctx:     context.Background(),
+				tx:      nil,
+				promoID: 1,
+			},
+			want:    models.Promo{},
+			wantErr: true,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				query := regexp.QuoteMeta(`SELECT * FROM "promos" WHERE id = $1 ORDER BY "promos"."id" LIMIT $2`)
+				mock.ExpectQuery(query).
+					WithArgs(1, 1).
+					WillReturnError(gorm.ErrRecordNotFound)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sqlmock: %v", err)
+			}
+			defer sqlDB.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{
+				Conn: sqlDB,
+			}), &gorm.Config{
+				Logger: logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				t.Fatalf("failed to open gorm connection: %v", err)
+			}
+
+			tt.sqlMock(mock)
+
+			r := NewPromoRepository(gormDB)
+			got, err := r.GetPromoDetail(tt.args.ctx, tt.args.tx, tt.args.promoID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("promoRepostory.GetPromoDetail() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("promoRepostory.GetPromoDetail() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_promoRepostory_ListPromos(t *testing.T) {
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+
+	type args struct {
+		ctx   context.Context
+		tx    *gorm.DB
+		input repository.ListPromosInput
+	}
+	tests := []struct {
+		name      string
+		args      args
+		want      []models.Promo
+		wantTotal int64
+		wantErr   bool
+		sqlMock   func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "success without filters leaves out archived promos",
+			args: args{
+				ctx: context.Background(),
+				tx:  nil,
+				input: repository.ListPromosInput{
+					Page:    2,
+					PerPage: 10,
+				},
+			},
+			want: []models.Promo{
+				{ID: 11, Status: constants.PROMOSTATUSAPPROVED},
+			},
+			wantTotal: 11,
+			wantErr:   false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				countQuery := regexp.QuoteMeta(`SELECT count(*) FROM "promos" WHERE status <> $1`)
+				mock.ExpectQuery(countQuery).
+					WithArgs(constants.PROMOSTATUSARCHIVED).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(11))
+
+				query := regexp.QuoteMeta(`SELECT * FROM "promos" WHERE status <> $1 ORDER BY id LIMIT $2 OFFSET $3`)
+				mock.ExpectQuery(query).
+					WithArgs(constants.PROMOSTATUSARCHIVED, 10, 10).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).
+						AddRow(11, constants.PROMOSTATUSAPPROVED))
+			},
+		},
+		{
+			name: "success with filters",
+			args: args{
+				ctx: context.Background(),
+				tx:  nil,
+				input: repository.ListPromosInput{
+					Status:       constants.PROMOSTATUSPAUSED,
+					Type:         constants.PROMOTYPEPERCENTAGE,
+					Segmentation: constants.PROMOSEGMENTATIONCITY,
+					StartDate:    from,
+					EndDate:      to,
+					Page:         1,
+					PerPage:      10,
+				},
+			},
+			want: []models.Promo{
+				{ID: 1, Status: constants.PROMOSTATUSPAUSED},
+			},
+			wantTotal: 1,
+			wantErr:   false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				countQuery := regexp.QuoteMeta(`SELECT count(*) FROM "promos" WHERE status = $1 AND type = $2 AND segmentation = $3 AND end_date >= $4 AND start_date <= $5`)
+				mock.ExpectQuery(countQuery).
+					WithArgs(constants.PROMOSTATUSPAUSED, constants.PROMOTYPEPERCENTAGE, constants.PROMOSEGMENTATIONCITY, from, to).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+				query := regexp.QuoteMeta(`SELECT * FROM "promos" WHERE status = $1 AND type = $2 AND segmentation = $3 AND end_date >= $4 AND start_date <= $5 ORDER BY id LIMIT $6`)
+				mock.ExpectQuery(query).
+					WithArgs(constants.PROMOSTATUSPAUSED, constants.PROMOTYPEPERCENTAGE, constants.PROMOSEGMENTATIONCITY, from, to, 10).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).
+						AddRow(1, constants.PROMOSTATUSPAUSED))
+			},
+		},
+		{
+			name: "err count promos",
+			args: args{
+				ctx: context.Background(),
+				tx:  nil,
+				input: repository.ListPromosInput{
+					Page:    1,
+					PerPage: 10,
+				},
+			},
+			want:      nil,
+			wantTotal: 0,
+			wantErr:   true,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				countQuery := regexp.QuoteMeta(`SELECT count(*) FROM "promos" WHERE status <> $1`)
+				mock.ExpectQuery(countQuery).
+					WithArgs(constants.PROMOSTATUSARCHIVED).
+					WillReturnError(errors.New("error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sqlmock: %v", err)
+			}
+			defer sqlDB.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{
+				Conn: sqlDB,
+			}), &gorm.Config{
+				Logger: logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				t.Fatalf("failed to open gorm connection: %v", err)
+			}
+
+			tt.sqlMock(mock)
+
+			r := NewPromoRepository(gormDB)
+			got, total, err := r.ListPromos(tt.args.ctx, tt.args.tx, tt.args.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("promoRepostory.ListPromos() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("promoRepostory.ListPromos() = %v, want %v", got, tt.want)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("promoRepostory.ListPromos() total = %v, want %v", total, tt.wantTotal)
 			}
 
 			if err := mock.ExpectationsWereMet(); err != nil {
@@ -26,6 +26,23 @@ func (u *userRepository) Get(ctx context.Context, tx *gorm.DB, userID uint) (*mo
 	return &user, nil
 }
 
+// GetOrderHistory implements repository.UserRepository.
+func (u *userRepository) GetOrderHistory(ctx context.Context, tx *gorm.DB, userID uint) (repository.UserOrderHistory, error) {
+	db := tx
+	if db == nil {
+		db = u.db.WithContext(ctx)
+	}
+
+	var history repository.UserOrderHistory
+	if err := db.Model(&models.Order{}).
+		Select("count(*) as order_count, max(created_at) as last_order_at").
+		Where("user_id = ?", userID).
+		Scan(&history).Error; err != nil {
+		return repository.UserOrderHistory{}, err
+	}
+	return history, nil
+}
+
 // Save implements repository.UserRepository.
 func (u *userRepository) Save(ctx context.Context, tx *gorm.DB, user *models.User) error {
 	db := tx
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"hangry/domain/models"
+	"hangry/repository"
 	"reflect"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"gorm.io/driver/postgres"
@@ -110,6 +112,107 @@ func Test_userRepository_Get(t *testing.T) {
 	}
 }
 
+func Test_userRepository_GetOrderHistory(t *testing.T) {
+	lastOrderAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	type args struct {
+		ctx    context.Context
+		tx     *gorm.DB
+		userID uint
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    repository.UserOrderHistory
+		wantErr bool
+		sqlMock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			args: args{
+				ctx:    context.Background(),
+				tx:     nil,
+				userID: 1,
+			},
+			want:    repository.UserOrderHistory{OrderCount: 2, LastOrderAt: &lastOrderAt},
+			wantErr: false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				query := regexp.QuoteMeta(`SELECT count(*) as order_count, max(created_at) as last_order_at FROM "orders" WHERE user_id = $1`)
+				mock.ExpectQuery(query).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"order_count", "last_order_at"}).AddRow(2, lastOrderAt))
+			},
+		},
+		{
+			name: "no order",
+			args: args{
+				ctx:    context.Background(),
+				tx:     nil,
+				userID: 1,
+			},
+			want:    repository.UserOrderHistory{},
+			wantErr: false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				query := regexp.QuoteMeta(`SELECT count(*) as order_count, max(created_at) as last_order_at FROM "orders" WHERE user_id = $1`)
+				mock.ExpectQuery(query).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"order_count", "last_order_at"}).AddRow(0, nil))
+			},
+		},
+		{
+			name: "error",
+			args: args{
+				ctx:    context.Background(),
+				tx:     nil,
+				userID: 1,
+			},
+			want:    repository.UserOrderHistory{},
+			wantErr: true,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				query := regexp.QuoteMeta(`SELECT count(*) as order_count, max(created_at) as last_order_at FROM "orders" WHERE user_id = $1`)
+				mock.ExpectQuery(query).
+					WithArgs(1).
+					WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to open sqlmock database: %v", err)
+			}
+			defer sqlDB.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{
+				Conn: sqlDB,
+			}), &gorm.Config{
+				Logger: logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				t.Fatalf("failed to open gorm database: %v", err)
+			}
+
+			tt.sqlMock(mock)
+
+			u := NewUserRepository(gormDB)
+
+			got, err := u.GetOrderHistory(tt.args.ctx, tt.args.tx, tt.args.userID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("userRepository.GetOrderHistory() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("userRepository.GetOrderHistory() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
 func Test_userRepository_Save(t *testing.T) {
 	type fields struct {
 		db *gorm.DB
@@ -0,0 +1,91 @@
+package db
+
+import (
+	"context"
+	"hangry/domain/models"
+	"hangry/repository"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type voucherRepository struct {
+	db *gorm.DB
+}
+
+// CreateVouchers implements repository.VoucherRepository.
+func (r *voucherRepository) CreateVouchers(ctx context.Context, tx *gorm.DB, vouchers []models.PromoVoucher) (int64, error) {
+	db := tx
+	if db == nil {
+		db = r.db.WithContext(ctx)
+	}
+
+	// a generated code can collide with an existing one, those rows are skipped
+	// and the caller generates replacements
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&vouchers)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+// GetVouchersByPromoID implements repository.VoucherRepository.
+func (r *voucherRepository) GetVouchersByPromoID(ctx context.Context, tx *gorm.DB, promoID uint) ([]models.PromoVoucher, error) {
+	db := tx
+	if db == nil {
+		db = r.db.WithContext(ctx)
+	}
+
+	var vouchers []models.PromoVoucher
+	if err := db.Where("promo_id = ?", promoID).Order("id").Find(&vouchers).Error; err != nil {
+		return nil, err
+	}
+
+	return vouchers, nil
+}
+
+// GetVoucherByCode implements repository.VoucherRepository.
+func (r *voucherRepository) GetVoucherByCode(ctx context.Context, tx *gorm.DB, code string) (models.PromoVoucher, error) {
+	db := tx
+	if db == nil {
+		db = r.db.WithContext(ctx)
+	}
+
+	var voucher models.PromoVoucher
+	if err := db.Where("code = ?", code).First(&voucher).Error; err != nil {
+		return voucher, err
+	}
+
+	return voucher, nil
+}
+
+// Redeem implements repository.VoucherRepository.
+func (r *voucherRepository) Redeem(ctx context.Context, tx *gorm.DB, voucherID uint, userID uint, orderID uint) (bool, error) {
+	db := tx
+	if db == nil {
+		db = r.db.WithContext(ctx)
+	}
+
+	// only an unredeemed voucher is updated, so a code can not be consumed by
+	// two orders
+	result := db.Model(&models.PromoVoucher{}).
+		Where("id = ? and redeemed_at is null", voucherID).
+		Updates(map[string]interface{}{
+			"redeemed_by_user_id": userID,
+			"redeemed_order_id":   orderID,
+			"redeemed_at":         time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func NewVoucherRepository(db *gorm.DB) repository.VoucherRepository {
+	return &voucherRepository{
+		db: db,
+	}
+}
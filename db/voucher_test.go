@@ -0,0 +1,309 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"hangry/domain/models"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func Test_voucherRepository_CreateVouchers(t *testing.T) {
+	timeNow := time.Now()
+	query := regexp.QuoteMeta(`INSERT INTO "promo_vouchers" ("promo_id","code","redeemed_by_user_id","redeemed_order_id","redeemed_at") VALUES ($1,$2,$3,$4,$5),($6,$7,$8,$9,$10) ON CONFLICT DO NOTHING RETURNING "created_at","updated_at","id"`)
+
+	tests := []struct {
+		name    string
+		want    int64
+		wantErr bool
+		sqlMock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name:    "success skips existing codes",
+			want:    1,
+			wantErr: false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(query).
+					WithArgs(1, "P1", nil, nil, nil, 1, "P2", nil, nil, nil).
+					WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at", "id"}).AddRow(timeNow, timeNow, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:    "error",
+			want:    0,
+			wantErr: true,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(query).
+					WithArgs(1, "P1", nil, nil, nil, 1, "P2", nil, nil, nil).
+					WillReturnError(errors.New("error"))
+				mock.ExpectRollback()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sqlmock: %v", err)
+			}
+			defer sqlDB.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{
+				Conn: sqlDB,
+			}), &gorm.Config{
+				Logger: logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				t.Fatalf("failed to open gorm connection: %v", err)
+			}
+
+			tt.sqlMock(mock)
+
+			r := NewVoucherRepository(gormDB)
+			got, err := r.CreateVouchers(context.Background(), nil, []models.PromoVoucher{
+				{PromoID: 1, Code: "P1"},
+				{PromoID: 1, Code: "P2"},
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("voucherRepository.CreateVouchers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("voucherRepository.CreateVouchers() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_voucherRepository_GetVouchersByPromoID(t *testing.T) {
+	query := regexp.QuoteMeta(`SELECT * FROM "promo_vouchers" WHERE promo_id = $1 ORDER BY id`)
+
+	tests := []struct {
+		name    string
+		want    []models.PromoVoucher
+		wantErr bool
+		sqlMock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "success",
+			want: []models.PromoVoucher{
+				{ID: 1, PromoID: 1, Code: "P1"},
+				{ID: 2, PromoID: 1, Code: "P2"},
+			},
+			wantErr: false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "promo_id", "code"}).
+						AddRow(1, 1, "P1").
+						AddRow(2, 1, "P2"))
+			},
+		},
+		{
+			name:    "error",
+			want:    nil,
+			wantErr: true,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(1).
+					WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sqlmock: %v", err)
+			}
+			defer sqlDB.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{
+				Conn: sqlDB,
+			}), &gorm.Config{
+				Logger: logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				t.Fatalf("failed to open gorm connection: %v", err)
+			}
+
+			tt.sqlMock(mock)
+
+			r := NewVoucherRepository(gormDB)
+			got, err := r.GetVouchersByPromoID(context.Background(), nil, 1)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("voucherRepository.GetVouchersByPromoID() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("voucherRepository.GetVouchersByPromoID() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_voucherRepository_GetVoucherByCode(t *testing.T) {
+	query := regexp.QuoteMeta(`SELECT * FROM "promo_vouchers" WHERE code = $1 ORDER BY "promo_vouchers"."id" LIMIT $2`)
+
+	tests := []struct {
+		name    string
+		want    models.PromoVoucher
+		wantErr bool
+		sqlMock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name:    "success",
+			want:    models.PromoVoucher{ID: 1, PromoID: 1, Code: "P1"},
+			wantErr: false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("P1", 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "promo_id", "code"}).AddRow(1, 1, "P1"))
+			},
+		},
+		{
+			name:    "error",
+			want:    models.PromoVoucher{},
+			wantErr: true,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("P1", 1).
+					WillReturnError(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sqlmock: %v", err)
+			}
+			defer sqlDB.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{
+				Conn: sqlDB,
+			}), &gorm.Config{
+				Logger: logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				t.Fatalf("failed to open gorm connection: %v", err)
+			}
+
+			tt.sqlMock(mock)
+
+			r := NewVoucherRepository(gormDB)
+			got, err := r.GetVoucherByCode(context.Background(), nil, "P1")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("voucherRepository.GetVoucherByCode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("voucherRepository.GetVoucherByCode() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_voucherRepository_Redeem(t *testing.T) {
+	query := regexp.QuoteMeta(`UPDATE "promo_vouchers" SET "redeemed_at"=$1,"redeemed_by_user_id"=$2,"redeemed_order_id"=$3,"updated_at"=$4 WHERE id = $5 and redeemed_at is null`)
+
+	tests := []struct {
+		name    string
+		want    bool
+		wantErr bool
+		sqlMock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name:    "success",
+			want:    true,
+			wantErr: false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(query).
+					WithArgs(sqlmock.AnyArg(), 2, 3, sqlmock.AnyArg(), 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:    "already redeemed",
+			want:    false,
+			wantErr: false,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(query).
+					WithArgs(sqlmock.AnyArg(), 2, 3, sqlmock.AnyArg(), 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:    "error",
+			want:    false,
+			wantErr: true,
+			sqlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(query).
+					WithArgs(sqlmock.AnyArg(), 2, 3, sqlmock.AnyArg(), 1).
+					WillReturnError(errors.New("error"))
+				mock.ExpectRollback()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sqlmock: %v", err)
+			}
+			defer sqlDB.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{
+				Conn: sqlDB,
+			}), &gorm.Config{
+				Logger: logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				t.Fatalf("failed to open gorm connection: %v", err)
+			}
+
+			tt.sqlMock(mock)
+
+			r := NewVoucherRepository(gormDB)
+			got, err := r.Redeem(context.Background(), nil, 1, 2, 3)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("voucherRepository.Redeem() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("voucherRepository.Redeem() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
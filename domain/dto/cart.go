@@ -10,3 +10,28 @@ type RemoveFromCartInput struct {
 	ProductId uint `json:"product_id"`
 	UserId    uint `json:"user_id"`
 }
+
+type UpdateCartItemInput struct {
+	ProductId uint `json:"product_id"`
+	UserId    uint `json:"user_id"`
+	Quantity  int  `json:"quantity"`
+}
+
+type GetCartInput struct {
+	UserId uint `json:"user_id"`
+}
+
+type CartItemDetail struct {
+	ProductId   uint    `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	Quantity    int     `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+	LineTotal   float64 `json:"line_total"`
+}
+
+type CartDetail struct {
+	CartId   uint             `json:"cart_id"`
+	UserId   uint             `json:"user_id"`
+	Items    []CartItemDetail `json:"items"`
+	Subtotal float64          `json:"subtotal"`
+}
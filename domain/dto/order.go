@@ -1,6 +1,32 @@
 package dto
 
 type OrderInput struct {
-	UserId   uint   `json:"user_id"`
-	PromoIds []uint `json:"promo_id"`
+	UserId     uint     `json:"user_id"`
+	PromoIds   []uint   `json:"promo_id"`
+	PromoCodes []string `json:"promo_codes"`
+}
+
+type OrderQuotePromo struct {
+	PromoId        uint    `json:"promo_id"`
+	Name           string  `json:"name"`
+	Type           string  `json:"type"`
+	DiscountAmount float64 `json:"discount_amount"`
+	FreeProductId  *uint   `json:"free_product_id"`
+	FreeProductQty int     `json:"free_product_qty"`
+	TierId         *uint   `json:"tier_id"`
+	VariantId      *uint   `json:"variant_id"`
+}
+
+type OrderQuoteFreeItem struct {
+	ProductId uint `json:"product_id"`
+	Quantity  int  `json:"quantity"`
+	PromoId   uint `json:"promo_id"`
+}
+
+type OrderQuote struct {
+	Subtotal      float64              `json:"subtotal"`
+	Promos        []OrderQuotePromo    `json:"promos"`
+	FreeItems     []OrderQuoteFreeItem `json:"free_items"`
+	DiscountTotal float64              `json:"discount_total"`
+	Total         float64              `json:"total"`
 }
@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"hangry/constants"
 	"hangry/domain/models"
 	"time"
 )
@@ -9,6 +10,9 @@ type CreatePromoInput struct {
 	Name              string    `json:"name"`
 	Description       *string   `json:"description,omitempty"`
 	Segmentation      string    `json:"segmentation"`
+	NewUserDays       *int      `json:"newUserDays,omitempty"`
+	LapsedDays        *int      `json:"lapsedDays,omitempty"`
+	OrderNumber       *int      `json:"orderNumber,omitempty"`
 	Type              string    `json:"type"`
 	StartDate         time.Time `json:"startDate"`
 	EndDate           time.Time `json:"endDate"`
@@ -20,7 +24,48 @@ type CreatePromoInput struct {
 	FreeProductId     *int      `json:"freeProductId,omitempty"`
 	FreeItemCount     *int      `json:"freeItemCount,omitempty"`
 	MaxUsageLimit     *int      `json:"maxUsageLimit,omitempty"`
+	MaxUsagePerUser   *int      `json:"maxUsagePerUser,omitempty"`
+	MaxBudgetAmount   *float64  `json:"maxBudgetAmount,omitempty"`
+	Code              *string   `json:"code,omitempty"`
+	CodeOnly          bool      `json:"codeOnly,omitempty"`
+	Repeatable        bool      `json:"repeatable,omitempty"`
+	MaxRepeat         *int      `json:"maxRepeat,omitempty"`
+	Exclusive         bool      `json:"exclusive,omitempty"`
+	StackGroup        *string   `json:"stackGroup,omitempty"`
+	Priority          int       `json:"priority,omitempty"`
+	RolloutPercentage *int      `json:"rolloutPercentage,omitempty"`
 	Cities            []string  `json:"cities,omitempty"`
+	// UserIds lists the users of a USER_LIST promo.
+	UserIds []uint `json:"userIds,omitempty"`
+	// ProductIds scopes a percentage discount to these products.
+	ProductIds []uint           `json:"productIds,omitempty"`
+	Tiers      []PromoTierInput `json:"tiers,omitempty"`
+	// Variants replace the discount of a percentage or fixed amount discount
+	// for the users assigned to them.
+	Variants []PromoVariantInput `json:"variants,omitempty"`
+	// Timezone is empty for the default timezone.
+	Timezone  string               `json:"timezone,omitempty"`
+	Schedules []PromoScheduleInput `json:"schedules,omitempty"`
+}
+
+type PromoTierInput struct {
+	MinAmount         float64  `json:"minAmount"`
+	DiscountType      string   `json:"discountType"`
+	DiscountValue     float64  `json:"discountValue"`
+	MaxDiscountAmount *float64 `json:"maxDiscountAmount,omitempty"`
+}
+
+type PromoVariantInput struct {
+	Name              string   `json:"name"`
+	Weight            int      `json:"weight"`
+	DiscountValue     float64  `json:"discountValue"`
+	MaxDiscountAmount *float64 `json:"maxDiscountAmount,omitempty"`
+}
+
+type PromoScheduleInput struct {
+	Days      []int  `json:"days"`
+	StartTime string `json:"startTime"`
+	EndTime   string `json:"endTime"`
 }
 
 func (c *CreatePromoInput) CreatePromoModel() models.Promo {
@@ -37,6 +82,10 @@ func (c *CreatePromoInput) CreatePromoModel() models.Promo {
 		promo.Description = *c.Description
 	}
 
+	promo.NewUserDays = c.NewUserDays
+	promo.LapsedDays = c.LapsedDays
+	promo.OrderNumber = c.OrderNumber
+
 	if c.MinOrderAmount != nil {
 		promo.MinOrderAmount = *c.MinOrderAmount
 	}
@@ -71,15 +120,237 @@ func (c *CreatePromoInput) CreatePromoModel() models.Promo {
 		promo.MaxUsageLimit = c.MaxUsageLimit
 	}
 
+	if c.MaxUsagePerUser != nil {
+		promo.MaxUsagePerUser = c.MaxUsagePerUser
+	}
+
+	if c.MaxBudgetAmount != nil {
+		promo.MaxBudgetAmount = c.MaxBudgetAmount
+	}
+
+	if c.Code != nil {
+		promo.Code = c.Code
+	}
+
+	promo.IsCodeOnly = c.CodeOnly
+	promo.IsRepeatable = c.Repeatable
+
+	if c.MaxRepeat != nil {
+		promo.MaxRepeat = c.MaxRepeat
+	}
+
+	promo.IsExclusive = c.Exclusive
+	promo.StackGroup = c.StackGroup
+	promo.Priority = c.Priority
+	promo.RolloutPercentage = c.RolloutPercentage
+
+	promo.Status = constants.PROMOSTATUSDRAFT
+	promo.Timezone = constants.PROMODEFAULTTIMEZONE
+	if c.Timezone != "" {
+		promo.Timezone = c.Timezone
+	}
+
 	return promo
 }
 
+// CreatePromoTierModels builds the tiers of a tiered discount.
+func (c *CreatePromoInput) CreatePromoTierModels(promoID uint) []models.PromoTier {
+	tiers := make([]models.PromoTier, 0, len(c.Tiers))
+	for _, tier := range c.Tiers {
+		tiers = append(tiers, models.PromoTier{
+			PromoID:           promoID,
+			MinAmount:         tier.MinAmount,
+			DiscountType:      tier.DiscountType,
+			DiscountValue:     tier.DiscountValue,
+			MaxDiscountAmount: tier.MaxDiscountAmount,
+		})
+	}
+
+	return tiers
+}
+
+// CreatePromoVariantModels builds the A/B variants of a discount.
+func (c *CreatePromoInput) CreatePromoVariantModels(promoID uint) []models.PromoVariant {
+	variants := make([]models.PromoVariant, 0, len(c.Variants))
+	for _, variant := range c.Variants {
+		variants = append(variants, models.PromoVariant{
+			PromoID:           promoID,
+			Name:              variant.Name,
+			Weight:            variant.Weight,
+			DiscountValue:     variant.DiscountValue,
+			MaxDiscountAmount: variant.MaxDiscountAmount,
+		})
+	}
+
+	return variants
+}
+
+// CreatePromoScheduleModels expands every schedule into one row per day.
+func (c *CreatePromoInput) CreatePromoScheduleModels(promoID uint) []models.PromoSchedule {
+	schedules := []models.PromoSchedule{}
+	for _, schedule := range c.Schedules {
+		for _, day := range schedule.Days {
+			schedules = append(schedules, models.PromoSchedule{
+				PromoID:   promoID,
+				DayOfWeek: day,
+				StartTime: schedule.StartTime,
+				EndTime:   schedule.EndTime,
+			})
+		}
+	}
+
+	return schedules
+}
+
 type ExtendPromoInput struct {
 	ID        uint      `json:"id"`
 	StartDate time.Time `json:"startDate"`
 	EndDate   time.Time `json:"endDate"`
 }
 
+// ReviewPromoInput is the reviewer's decision on a promo waiting for approval.
+type ReviewPromoInput struct {
+	PromoID uint
+	// Actor is who reviewed the promo.
+	Actor string
+	// Reason tells why the promo was rejected.
+	Reason *string
+}
+
+type UpdatePromoInput struct {
+	ID                uint
+	Name              *string
+	Description       *string
+	MinOrderAmount    *float64
+	DiscountValue     *float64
+	MaxDiscountAmount *float64
+	MaxUsageLimit     *int
+	MaxUsagePerUser   *int
+	MaxBudgetAmount   *float64
+	Exclusive         *bool
+	StackGroup        *string
+	Priority          *int
+	RolloutPercentage *int
+}
+
+// Apply copies the given fields onto the promo.
+func (u *UpdatePromoInput) Apply(promo *models.Promo) {
+	if u.Name != nil {
+		promo.Name = *u.Name
+	}
+
+	if u.Description != nil {
+		promo.Description = *u.Description
+	}
+
+	if u.MinOrderAmount != nil {
+		promo.MinOrderAmount = *u.MinOrderAmount
+	}
+
+	if u.DiscountValue != nil {
+		promo.DiscountValue = *u.DiscountValue
+	}
+
+	if u.MaxDiscountAmount != nil {
+		promo.MaxDiscountAmount = *u.MaxDiscountAmount
+	}
+
+	if u.MaxUsageLimit != nil {
+		promo.MaxUsageLimit = u.MaxUsageLimit
+	}
+
+	if u.MaxUsagePerUser != nil {
+		promo.MaxUsagePerUser = u.MaxUsagePerUser
+	}
+
+	if u.MaxBudgetAmount != nil {
+		promo.MaxBudgetAmount = u.MaxBudgetAmount
+	}
+
+	if u.Exclusive != nil {
+		promo.IsExclusive = *u.Exclusive
+	}
+
+	if u.StackGroup != nil {
+		promo.StackGroup = u.StackGroup
+	}
+
+	if u.Priority != nil {
+		promo.Priority = *u.Priority
+	}
+
+	if u.RolloutPercentage != nil {
+		promo.RolloutPercentage = u.RolloutPercentage
+	}
+}
+
+type AddPromoCitiesInput struct {
+	PromoID uint
+	Cities  []string
+}
+
+type RemovePromoCityInput struct {
+	PromoID uint
+	City    string
+}
+
+type PromoUsersInput struct {
+	PromoID uint
+	UserIds []uint
+}
+
+type GetPromoUsersInput struct {
+	PromoID uint
+	Page    int
+	PerPage int
+}
+
+type GetPromoHistoryInput struct {
+	PromoID uint
+	Page    int
+	PerPage int
+}
+
+type ListPromosInput struct {
+	Status       string
+	Type         string
+	Segmentation string
+	StartDate    time.Time
+	EndDate      time.Time
+	Page         int
+	PerPage      int
+}
+
+type RecommendPromoInput struct {
+	UserId uint
+}
+
+type PromoRecommendation struct {
+	PromoIds       []uint     `json:"promo_ids"`
+	Quote          OrderQuote `json:"quote"`
+	FreeItemsValue float64    `json:"free_items_value"`
+	Saving         float64    `json:"saving"`
+}
+
+type PromoEligibilityInput struct {
+	PromoId uint
+	UserId  uint
+}
+
+type EligibilityCheck struct {
+	Name     string `json:"name"`
+	Passed   bool   `json:"passed"`
+	Actual   string `json:"actual"`
+	Required string `json:"required"`
+	Message  string `json:"message,omitempty"`
+}
+
+type PromoEligibility struct {
+	PromoId  uint               `json:"promo_id"`
+	Eligible bool               `json:"eligible"`
+	Checks   []EligibilityCheck `json:"checks"`
+}
+
 type GetPromoInput struct {
 	UserId  uint
 	Page    int
@@ -0,0 +1,7 @@
+package dto
+
+type GenerateVoucherInput struct {
+	PromoId uint   `json:"promoId"`
+	Count   int    `json:"count"`
+	Prefix  string `json:"prefix"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// OrderPromoItem represents the order_promo_items table, the order items a
+// product scoped discount was taken from.
+type OrderPromoItem struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	OrderPromoID uint      `gorm:"not null;index" json:"order_promo_id"`
+	OrderItemID  uint      `gorm:"not null" json:"order_item_id"`
+	CreatedAt    time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+
+	// Relationships
+	OrderPromo *OrderPromo `gorm:"foreignKey:OrderPromoID" json:"-"`
+	OrderItem  *OrderItem  `gorm:"foreignKey:OrderItemID" json:"-"`
+}
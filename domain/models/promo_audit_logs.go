@@ -0,0 +1,22 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// PromoAuditLog represents the promo_audit_logs table. An entry is added for
+// every change of a promo and is never changed afterwards.
+type PromoAuditLog struct {
+	ID      uint   `gorm:"primaryKey" json:"id"`
+	PromoID uint   `gorm:"not null;index" json:"promo_id"`
+	Action  string `gorm:"not null;size:50" json:"action"`
+	// Actor is who made the change, nil when the request did not say.
+	Actor *string `gorm:"size:255" json:"actor"`
+	// Changes maps every changed field to its value before and after.
+	Changes   json.RawMessage `gorm:"type:jsonb;not null" json:"changes"`
+	CreatedAt time.Time       `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+
+	// Relationships
+	Promo *Promo `gorm:"foreignKey:PromoID" json:"-"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// PromoProduct represents the promo_products table. A percentage discount
+// with products is taken from the matching cart lines only.
+type PromoProduct struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	PromoID   uint      `gorm:"not null;index" json:"promo_id"`
+	ProductID uint      `gorm:"not null" json:"product_id"`
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+
+	// Relationships
+	Promo   *Promo   `gorm:"foreignKey:PromoID" json:"-"`
+	Product *Product `gorm:"foreignKey:ProductID" json:"-"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// PromoSchedule represents the promo_schedules table. A promo with schedules
+// can only be redeemed inside one of them, in the promo's timezone.
+type PromoSchedule struct {
+	ID      uint `gorm:"primaryKey" json:"id"`
+	PromoID uint `gorm:"not null;index" json:"promo_id"`
+	// DayOfWeek follows time.Weekday, 0 is Sunday.
+	DayOfWeek int `gorm:"not null;check:day_of_week BETWEEN 0 AND 6" json:"day_of_week"`
+	// StartTime and EndTime are HH:MM. A window whose end is not after its
+	// start runs past midnight into the next day, so 00:00 to 00:00 is the
+	// whole day.
+	StartTime string    `gorm:"not null;size:5" json:"start_time"`
+	EndTime   string    `gorm:"not null;size:5" json:"end_time"`
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+
+	// Relationships
+	Promo *Promo `gorm:"foreignKey:PromoID" json:"-"`
+}
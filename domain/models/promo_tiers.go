@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// PromoTier represents the promo_tiers table, one spend level of a tiered
+// discount. The highest tier the cart reaches is applied.
+type PromoTier struct {
+	ID        uint    `gorm:"primaryKey" json:"id"`
+	PromoID   uint    `gorm:"not null;index" json:"promo_id"`
+	MinAmount float64 `gorm:"not null;type:numeric(10,2)" json:"min_amount"`
+	// DiscountType is PERCENTAGE or FIXED.
+	DiscountType  string  `gorm:"not null;size:20;check:discount_type IN ('PERCENTAGE', 'FIXED')" json:"discount_type"`
+	DiscountValue float64 `gorm:"not null;type:numeric(10,2)" json:"discount_value"`
+	// MaxDiscountAmount caps a percentage tier, nil means no cap.
+	MaxDiscountAmount *float64  `gorm:"type:numeric(10,2)" json:"max_discount_amount"`
+	CreatedAt         time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt         time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+
+	// Relationships
+	Promo *Promo `gorm:"foreignKey:PromoID" json:"-"`
+}
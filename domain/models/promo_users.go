@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// PromoUser represents the promo_users table. A USER_LIST promo is only
+// available to the users listed for it.
+type PromoUser struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	PromoID   uint      `gorm:"not null;uniqueIndex:idx_promo_users_promo_user" json:"promo_id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_promo_users_promo_user" json:"user_id"`
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+
+	// Relationships
+	Promo *Promo `gorm:"foreignKey:PromoID" json:"-"`
+	User  *User  `gorm:"foreignKey:UserID" json:"-"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// PromoVariant represents the promo_variants table, one arm of an A/B test of
+// a discount. Every user the promo is rolled out to is assigned one variant.
+type PromoVariant struct {
+	ID      uint   `gorm:"primaryKey" json:"id"`
+	PromoID uint   `gorm:"not null;index;uniqueIndex:idx_promo_variants_promo_name" json:"promo_id"`
+	Name    string `gorm:"not null;size:50;uniqueIndex:idx_promo_variants_promo_name" json:"name"`
+	// Weight is the share of users assigned to the variant relative to the
+	// other variants of the promo.
+	Weight        int     `gorm:"not null;default:1" json:"weight"`
+	DiscountValue float64 `gorm:"not null;type:numeric(10,2)" json:"discount_value"`
+	// MaxDiscountAmount replaces the cap of a percentage discount, nil keeps
+	// the promo's.
+	MaxDiscountAmount *float64  `gorm:"type:numeric(10,2)" json:"max_discount_amount"`
+	CreatedAt         time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt         time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+
+	// Relationships
+	Promo *Promo `gorm:"foreignKey:PromoID" json:"-"`
+}
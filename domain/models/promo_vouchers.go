@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// PromoVoucher represents the promo_vouchers table
+type PromoVoucher struct {
+	ID               uint       `gorm:"primaryKey" json:"id"`
+	PromoID          uint       `gorm:"not null;index" json:"promo_id"`
+	Code             string     `gorm:"not null;uniqueIndex;size:50" json:"code"`
+	RedeemedByUserID *uint      `json:"redeemed_by_user_id"`
+	RedeemedOrderID  *uint      `json:"redeemed_order_id"`
+	RedeemedAt       *time.Time `json:"redeemed_at"`
+	CreatedAt        time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt        time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+
+	// Relationships
+	Promo *Promo `gorm:"foreignKey:PromoID" json:"promo"`
+}
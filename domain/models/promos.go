@@ -4,26 +4,51 @@ import "time"
 
 // Promo represents the promos table
 type Promo struct {
-	ID                uint      `gorm:"primaryKey" json:"id"`
-	Name              string    `gorm:"not null;size:255" json:"name"`
-	Description       string    `gorm:"type:text" json:"description"`
-	Segmentation      string    `gorm:"not null;size:255;check:segmentation IN ('ALL', 'LOYAL_USER', 'NEW_USER', 'CITY')" json:"segmentation"`
-	Type              string    `gorm:"not null;size:50;check:type IN ('PERCENTAGE_DISCOUNT', 'BUY_X_GET_Y_FREE')" json:"type"`
-	MinOrderAmount    float64   `gorm:"type:numeric(10,2)" json:"min_order_amount"`
-	DiscountValue     float64   `gorm:"type:numeric(10,2)" json:"discount_value"`
-	MaxDiscountAmount float64   `gorm:"type:numeric(10,2)" json:"max_discount_amount"`
-	BuyProductID      *uint     `gorm:"index" json:"buy_product_id"`
-	FreeProductID     *uint     `gorm:"index" json:"free_product_id"`
-	BuyProductQty     int       `json:"buy_product_qty"`
-	FreeProductQty    int       `json:"free_product_qty"`
-	StartDate         time.Time `gorm:"not null" json:"start_date"`
-	EndDate           time.Time `gorm:"not null" json:"end_date"`
-	MaxUsageLimit     *int      `json:"max_usage_limit"`
-	CurrentUsageCount int       `gorm:"default:0" json:"current_usage_count"`
-	CreatedAt         time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt         time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+	ID                uint       `gorm:"primaryKey" json:"id"`
+	Name              string     `gorm:"not null;size:255" json:"name"`
+	Description       string     `gorm:"type:text" json:"description"`
+	Segmentation      string     `gorm:"not null;size:255" json:"segmentation"`
+	Type              string     `gorm:"not null;size:50;check:type IN ('PERCENTAGE_DISCOUNT', 'FIXED_AMOUNT_DISCOUNT', 'BUY_X_GET_Y_FREE', 'TIERED_DISCOUNT')" json:"type"`
+	MinOrderAmount    float64    `gorm:"type:numeric(10,2)" json:"min_order_amount"`
+	DiscountValue     float64    `gorm:"type:numeric(10,2)" json:"discount_value"`
+	MaxDiscountAmount float64    `gorm:"type:numeric(10,2)" json:"max_discount_amount"`
+	BuyProductID      *uint      `gorm:"index" json:"buy_product_id"`
+	FreeProductID     *uint      `gorm:"index" json:"free_product_id"`
+	BuyProductQty     int        `json:"buy_product_qty"`
+	FreeProductQty    int        `json:"free_product_qty"`
+	StartDate         time.Time  `gorm:"not null" json:"start_date"`
+	EndDate           time.Time  `gorm:"not null" json:"end_date"`
+	MaxUsageLimit     *int       `json:"max_usage_limit"`
+	CurrentUsageCount int        `gorm:"default:0" json:"current_usage_count"`
+	MaxUsagePerUser   *int       `json:"max_usage_per_user"`
+	MaxBudgetAmount   *float64   `gorm:"type:numeric(12,2)" json:"max_budget_amount"`
+	SpentAmount       float64    `gorm:"type:numeric(12,2);default:0" json:"spent_amount"`
+	Code              *string    `gorm:"uniqueIndex;size:50" json:"code"`
+	IsCodeOnly        bool       `gorm:"default:false" json:"is_code_only"`
+	IsRepeatable      bool       `gorm:"default:false" json:"is_repeatable"`
+	MaxRepeat         *int       `json:"max_repeat"`
+	IsExclusive       bool       `gorm:"default:false" json:"is_exclusive"`
+	StackGroup        *string    `gorm:"size:50" json:"stack_group"`
+	Priority          int        `gorm:"default:0" json:"priority"`
+	Timezone          string     `gorm:"not null;size:64;default:'Asia/Jakarta'" json:"timezone"`
+	Status            string     `gorm:"not null;size:20;default:'DRAFT';check:status IN ('DRAFT', 'PENDING_APPROVAL', 'APPROVED', 'REJECTED', 'PAUSED', 'ENDED', 'ARCHIVED')" json:"status"`
+	ReviewedBy        *string    `gorm:"size:255" json:"reviewed_by"`
+	ReviewedAt        *time.Time `json:"reviewed_at"`
+	RejectionReason   *string    `gorm:"type:text" json:"rejection_reason"`
+	NewUserDays       *int       `json:"new_user_days"`
+	LapsedDays        *int       `json:"lapsed_days"`
+	OrderNumber       *int       `json:"order_number"`
+	RolloutPercentage *int       `json:"rollout_percentage"`
+	CreatedAt         time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt         time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
 
 	// Relationships
-	PromoCities []PromoCity  `gorm:"foreignKey:PromoID" json:"promo_cities"`
-	OrderPromos []OrderPromo `gorm:"foreignKey:PromoID" json:"order_promos"`
+	FreeProduct    *Product        `gorm:"foreignKey:FreeProductID" json:"free_product,omitempty"`
+	PromoCities    []PromoCity     `gorm:"foreignKey:PromoID" json:"promo_cities"`
+	PromoSchedules []PromoSchedule `gorm:"foreignKey:PromoID" json:"promo_schedules"`
+	PromoProducts  []PromoProduct  `gorm:"foreignKey:PromoID" json:"promo_products"`
+	PromoTiers     []PromoTier     `gorm:"foreignKey:PromoID" json:"promo_tiers"`
+	PromoUsers     []PromoUser     `gorm:"foreignKey:PromoID" json:"promo_users,omitempty"`
+	PromoVariants  []PromoVariant  `gorm:"foreignKey:PromoID" json:"promo_variants"`
+	OrderPromos    []OrderPromo    `gorm:"foreignKey:PromoID" json:"order_promos"`
 }
@@ -0,0 +1,139 @@
+package eligibility
+
+import (
+	"hangry/constants"
+	"hangry/domain/models"
+	"hangry/pricing"
+	"strconv"
+	"time"
+)
+
+const (
+	CheckStatus         = "STATUS"
+	CheckDateWindow     = "DATE_WINDOW"
+	CheckUsageLimit     = "USAGE_LIMIT"
+	CheckBudget         = "BUDGET"
+	CheckUserUsageLimit = "USER_USAGE_LIMIT"
+)
+
+type statusRule struct{}
+
+func (statusRule) Check(promo models.Promo, subject Subject) Check {
+	check := Check{
+		Name:     CheckStatus,
+		Passed:   true,
+		Actual:   promo.Status,
+		Required: constants.PROMOSTATUSAPPROVED,
+	}
+
+	switch promo.Status {
+	case constants.PROMOSTATUSAPPROVED:
+	case constants.PROMOSTATUSDRAFT:
+		check.Passed = false
+		check.Message = "promo is a draft"
+	case constants.PROMOSTATUSPENDINGAPPROVAL:
+		check.Passed = false
+		check.Message = "promo is waiting for approval"
+	case constants.PROMOSTATUSREJECTED:
+		check.Passed = false
+		check.Message = "promo was rejected"
+	case constants.PROMOSTATUSPAUSED:
+		check.Passed = false
+		check.Message = "promo is paused"
+	case constants.PROMOSTATUSENDED:
+		check.Passed = false
+		check.Message = "promo was ended"
+	case constants.PROMOSTATUSARCHIVED:
+		check.Passed = false
+		check.Message = "promo is archived"
+	default:
+		check.Passed = false
+		check.Message = "unknown promo status"
+	}
+
+	return check
+}
+
+type dateWindowRule struct{}
+
+func (dateWindowRule) Check(promo models.Promo, subject Subject) Check {
+	check := Check{
+		Name:     CheckDateWindow,
+		Passed:   true,
+		Actual:   subject.Now.Format(time.RFC3339),
+		Required: promo.StartDate.Format(time.RFC3339) + " - " + promo.EndDate.Format(time.RFC3339),
+	}
+
+	if subject.Now.Before(promo.StartDate) {
+		check.Passed = false
+		check.Message = "promo has not started yet"
+	} else if subject.Now.After(promo.EndDate) {
+		check.Passed = false
+		check.Message = "promo has ended"
+	}
+
+	return check
+}
+
+type usageLimitRule struct{}
+
+func (usageLimitRule) Check(promo models.Promo, subject Subject) Check {
+	check := Check{
+		Name:     CheckUsageLimit,
+		Passed:   true,
+		Actual:   strconv.Itoa(promo.CurrentUsageCount),
+		Required: "unlimited",
+	}
+
+	if promo.MaxUsageLimit != nil {
+		check.Required = "less than " + strconv.Itoa(*promo.MaxUsageLimit)
+		if promo.CurrentUsageCount >= *promo.MaxUsageLimit {
+			check.Passed = false
+			check.Message = "promo has reached its usage limit"
+		}
+	}
+
+	return check
+}
+
+type budgetRule struct{}
+
+func (budgetRule) Check(promo models.Promo, subject Subject) Check {
+	check := Check{
+		Name:     CheckBudget,
+		Passed:   true,
+		Actual:   formatRupiah(promo.SpentAmount) + " spent",
+		Required: "unlimited",
+	}
+
+	if promo.MaxBudgetAmount != nil {
+		check.Required = "less than " + formatRupiah(*promo.MaxBudgetAmount)
+		if pricing.BudgetExhausted(promo) {
+			check.Passed = false
+			check.Message = "promo budget has been used up"
+		}
+	}
+
+	return check
+}
+
+type userUsageLimitRule struct{}
+
+func (userUsageLimitRule) Check(promo models.Promo, subject Subject) Check {
+	check := Check{
+		Name:     CheckUserUsageLimit,
+		Passed:   true,
+		Actual:   strconv.Itoa(subject.UserUsageCount),
+		Required: "unlimited",
+	}
+
+	if promo.MaxUsagePerUser != nil {
+		check.Required = "less than " + strconv.Itoa(*promo.MaxUsagePerUser)
+		if subject.UserUsageCount >= *promo.MaxUsagePerUser {
+			check.Passed = false
+			check.Message = "user has used the promo the maximum number of times"
+		}
+	}
+
+	return check
+}
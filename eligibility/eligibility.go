@@ -0,0 +1,189 @@
+// Package eligibility decides, rule by rule, whether a user can get a promo.
+// Each segmentation and promo type has its own PromoRule, registered from the
+// file that implements it.
+package eligibility
+
+import (
+	"hangry/domain/models"
+	"hangry/pricing"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Subject is what a promo is checked against.
+type Subject struct {
+	Now  time.Time
+	User models.User
+	// Lines is the user's cart, empty when the user has no cart.
+	Lines []pricing.Line
+	// UserUsageCount is how many of the user's orders already used the promo.
+	UserUsageCount int
+	// OrderCount and LastOrderAt come from the user's orders, they are only
+	// loaded when NeedsOrderHistory says a promo reads them. LastOrderAt is
+	// zero when the user has no order.
+	OrderCount  int
+	LastOrderAt time.Time
+}
+
+type Check struct {
+	Name     string
+	Passed   bool
+	Actual   string
+	Required string
+	// Message tells what is missing, it is empty when the check passed.
+	Message string
+}
+
+// PromoRule decides one part of a promo's eligibility.
+type PromoRule interface {
+	Check(promo models.Promo, subject Subject) Check
+}
+
+// orderHistoryRule is implemented by the rules reading the subject's
+// OrderCount and LastOrderAt, so the history is only loaded when needed.
+type orderHistoryRule interface {
+	usesOrderHistory()
+}
+
+var (
+	segmentationRules = map[string]PromoRule{}
+	typeRules         = map[string]PromoRule{}
+
+	// availabilityRules are already applied by the promo query, so they only
+	// show up in Explain.
+	availabilityRules = []PromoRule{statusRule{}, dateWindowRule{}, usageLimitRule{}, budgetRule{}, userUsageLimitRule{}}
+	// promoRules apply to every promo whatever its type and segmentation.
+	promoRules = []PromoRule{scheduleRule{}, rolloutRule{}}
+)
+
+// RegisterSegmentation adds the rule deciding who can get promos of the
+// segmentation. It is meant to be called from init.
+func RegisterSegmentation(segmentation string, rule PromoRule) {
+	if _, ok := segmentationRules[segmentation]; ok {
+		panic("eligibility: segmentation " + segmentation + " registered twice")
+	}
+	segmentationRules[segmentation] = rule
+}
+
+// RegisterType adds the rule deciding which carts qualify for promos of the
+// type. It is meant to be called from init.
+func RegisterType(promoType string, rule PromoRule) {
+	if _, ok := typeRules[promoType]; ok {
+		panic("eligibility: type " + promoType + " registered twice")
+	}
+	typeRules[promoType] = rule
+}
+
+// IsSegmentation reports whether the segmentation has a registered rule.
+func IsSegmentation(segmentation string) bool {
+	_, ok := segmentationRules[segmentation]
+	return ok
+}
+
+// Segmentations lists the registered segmentations in alphabetical order.
+func Segmentations() []string {
+	segmentations := make([]string, 0, len(segmentationRules))
+	for segmentation := range segmentationRules {
+		segmentations = append(segmentations, segmentation)
+	}
+	sort.Strings(segmentations)
+
+	return segmentations
+}
+
+// NeedsOrderHistory reports whether any of the promos has a rule reading the
+// subject's order history.
+func NeedsOrderHistory(promos ...models.Promo) bool {
+	for _, promo := range promos {
+		if _, ok := segmentationRules[promo.Segmentation].(orderHistoryRule); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Explain runs every check that applies to the promo, including the
+// availability ones.
+func Explain(promo models.Promo, subject Subject) []Check {
+	checks := make([]Check, 0, len(availabilityRules)+len(promoRules)+2)
+	for _, rule := range availabilityRules {
+		checks = append(checks, rule.Check(promo, subject))
+	}
+
+	return append(checks, ruleChecks(promo, subject)...)
+}
+
+// Filter keeps the promos whose schedule, segmentation and type rules pass
+// for the subject. The promos must have their PromoCities and PromoSchedules
+// loaded.
+func Filter(promos []models.Promo, subject Subject) []models.Promo {
+	eligible := make([]models.Promo, 0, len(promos))
+	for _, promo := range promos {
+		if Eligible(ruleChecks(promo, subject)) {
+			eligible = append(eligible, promo)
+		}
+	}
+
+	return eligible
+}
+
+// Eligible reports whether every check passed.
+func Eligible(checks []Check) bool {
+	for _, check := range checks {
+		if !check.Passed {
+			return false
+		}
+	}
+
+	return true
+}
+
+func ruleChecks(promo models.Promo, subject Subject) []Check {
+	checks := make([]Check, 0, len(promoRules)+2)
+	for _, rule := range promoRules {
+		checks = append(checks, rule.Check(promo, subject))
+	}
+
+	if rule, ok := typeRules[promo.Type]; ok {
+		checks = append(checks, rule.Check(promo, subject))
+	} else {
+		checks = append(checks, Check{
+			Name:    "TYPE",
+			Actual:  promo.Type,
+			Message: "unknown promo type",
+		})
+	}
+
+	if rule, ok := segmentationRules[promo.Segmentation]; ok {
+		checks = append(checks, rule.Check(promo, subject))
+	} else {
+		checks = append(checks, Check{
+			Name:     "SEGMENTATION",
+			Actual:   promo.Segmentation,
+			Required: strings.Join(Segmentations(), ", "),
+			Message:  "unknown segmentation",
+		})
+	}
+
+	return checks
+}
+
+// formatRupiah formats an amount the way prices are shown to customers, e.g.
+// Rp12.000.
+func formatRupiah(amount float64) string {
+	digits := strconv.FormatInt(int64(amount+0.5), 10)
+
+	var b strings.Builder
+	b.WriteString("Rp")
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(digit)
+	}
+
+	return b.String()
+}
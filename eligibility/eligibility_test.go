@@ -0,0 +1,684 @@
+package eligibility
+
+import (
+	"hangry/constants"
+	"hangry/domain/models"
+	"hangry/pricing"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExplain(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	buyProductId := uint(3)
+	maxUsage := 10
+	maxPerUser := 1
+	maxBudget := float64(1000000)
+
+	active := models.Promo{
+		ID:        1,
+		StartDate: now.AddDate(0, 0, -1),
+		EndDate:   now.AddDate(0, 0, 1),
+		Status:    constants.PROMOSTATUSAPPROVED,
+	}
+
+	tests := []struct {
+		name    string
+		promo   func(models.Promo) models.Promo
+		subject Subject
+		want    map[string]string
+	}{
+		{
+			name: "all checks passed",
+			promo: func(p models.Promo) models.Promo {
+				p.Type = constants.PROMOTYPEPERCENTAGE
+				p.Segmentation = constants.PROMOSEGMENTATIONALL
+				p.MinOrderAmount = 20000
+				return p
+			},
+			subject: Subject{
+				Now:   now,
+				Lines: []pricing.Line{{ProductID: 1, Price: 10000, Quantity: 2}},
+			},
+			want: map[string]string{
+				CheckStatus:                    "",
+				CheckDateWindow:                "",
+				CheckUsageLimit:                "",
+				CheckBudget:                    "",
+				CheckUserUsageLimit:            "",
+				CheckSchedule:                  "",
+				CheckRollout:                   "",
+				CheckMinOrderAmount:            "",
+				constants.PROMOSEGMENTATIONALL: "",
+			},
+		},
+		{
+			name: "min order amount not reached",
+			promo: func(p models.Promo) models.Promo {
+				p.Type = constants.PROMOTYPEFIXEDAMOUNT
+				p.Segmentation = constants.PROMOSEGMENTATIONALL
+				p.MinOrderAmount = 20000
+				return p
+			},
+			subject: Subject{
+				Now:   now,
+				Lines: []pricing.Line{{ProductID: 1, Price: 8000, Quantity: 1}},
+			},
+			want: map[string]string{
+				CheckStatus:                    "",
+				CheckDateWindow:                "",
+				CheckUsageLimit:                "",
+				CheckBudget:                    "",
+				CheckUserUsageLimit:            "",
+				CheckSchedule:                  "",
+				CheckRollout:                   "",
+				CheckMinOrderAmount:            "need Rp12.000 more",
+				constants.PROMOSEGMENTATIONALL: "",
+			},
+		},
+		{
+			name: "min order amount counts the promo products only",
+			promo: func(p models.Promo) models.Promo {
+				p.Type = constants.PROMOTYPEPERCENTAGE
+				p.Segmentation = constants.PROMOSEGMENTATIONALL
+				p.MinOrderAmount = 20000
+				p.PromoProducts = []models.PromoProduct{{ProductID: 2}}
+				return p
+			},
+			subject: Subject{
+				Now: now,
+				Lines: []pricing.Line{
+					{ProductID: 1, Price: 50000, Quantity: 1},
+					{ProductID: 2, Price: 5000, Quantity: 2},
+				},
+			},
+			want: map[string]string{
+				CheckStatus:                    "",
+				CheckDateWindow:                "",
+				CheckUsageLimit:                "",
+				CheckBudget:                    "",
+				CheckUserUsageLimit:            "",
+				CheckSchedule:                  "",
+				CheckRollout:                   "",
+				CheckMinOrderAmount:            "need Rp10.000 more",
+				constants.PROMOSEGMENTATIONALL: "",
+			},
+		},
+		{
+			name: "none of the promo products in the cart",
+			promo: func(p models.Promo) models.Promo {
+				p.Type = constants.PROMOTYPEPERCENTAGE
+				p.Segmentation = constants.PROMOSEGMENTATIONALL
+				p.PromoProducts = []models.PromoProduct{{ProductID: 2}}
+				return p
+			},
+			subject: Subject{
+				Now:   now,
+				Lines: []pricing.Line{{ProductID: 1, Price: 50000, Quantity: 1}},
+			},
+			want: map[string]string{
+				CheckStatus:                    "",
+				CheckDateWindow:                "",
+				CheckUsageLimit:                "",
+				CheckBudget:                    "",
+				CheckUserUsageLimit:            "",
+				CheckSchedule:                  "",
+				CheckRollout:                   "",
+				CheckMinOrderAmount:            "cart has none of the promo products",
+				constants.PROMOSEGMENTATIONALL: "",
+			},
+		},
+		{
+			name: "lowest spend tier not reached",
+			promo: func(p models.Promo) models.Promo {
+				p.Type = constants.PROMOTYPETIERED
+				p.Segmentation = constants.PROMOSEGMENTATIONALL
+				p.PromoTiers = []models.PromoTier{{MinAmount: 100000}, {MinAmount: 50000}}
+				return p
+			},
+			subject: Subject{
+				Now:   now,
+				Lines: []pricing.Line{{ProductID: 1, Price: 20000, Quantity: 2}},
+			},
+			want: map[string]string{
+				CheckStatus:                    "",
+				CheckDateWindow:                "",
+				CheckUsageLimit:                "",
+				CheckBudget:                    "",
+				CheckUserUsageLimit:            "",
+				CheckSchedule:                  "",
+				CheckRollout:                   "",
+				CheckSpendTier:                 "need Rp10.000 more",
+				constants.PROMOSEGMENTATIONALL: "",
+			},
+		},
+		{
+			name: "spend tier reached",
+			promo: func(p models.Promo) models.Promo {
+				p.Type = constants.PROMOTYPETIERED
+				p.Segmentation = constants.PROMOSEGMENTATIONALL
+				p.PromoTiers = []models.PromoTier{{MinAmount: 100000}, {MinAmount: 50000}}
+				return p
+			},
+			subject: Subject{
+				Now:   now,
+				Lines: []pricing.Line{{ProductID: 1, Price: 20000, Quantity: 3}},
+			},
+			want: map[string]string{
+				CheckStatus:                    "",
+				CheckDateWindow:                "",
+				CheckUsageLimit:                "",
+				CheckBudget:                    "",
+				CheckUserUsageLimit:            "",
+				CheckSchedule:                  "",
+				CheckRollout:                   "",
+				CheckSpendTier:                 "",
+				constants.PROMOSEGMENTATIONALL: "",
+			},
+		},
+		{
+			name: "paused and ended promo with used up limits",
+			promo: func(p models.Promo) models.Promo {
+				p.Type = constants.PROMOTYPEBUYXGETY
+				p.Segmentation = constants.PROMOSEGMENTATIONALL
+				p.Status = constants.PROMOSTATUSPAUSED
+				p.EndDate = now.AddDate(0, 0, -1)
+				p.MaxUsageLimit = &maxUsage
+				p.CurrentUsageCount = 10
+				p.MaxBudgetAmount = &maxBudget
+				p.SpentAmount = 1000000
+				p.MaxUsagePerUser = &maxPerUser
+				p.BuyProductID = &buyProductId
+				p.BuyProductQty = 3
+				return p
+			},
+			subject: Subject{
+				Now:            now,
+				Lines:          []pricing.Line{{ProductID: 3, Price: 5000, Quantity: 1}},
+				UserUsageCount: 1,
+			},
+			want: map[string]string{
+				CheckStatus:                    "promo is paused",
+				CheckDateWindow:                "promo has ended",
+				CheckUsageLimit:                "promo has reached its usage limit",
+				CheckBudget:                    "promo budget has been used up",
+				CheckUserUsageLimit:            "user has used the promo the maximum number of times",
+				CheckSchedule:                  "",
+				CheckRollout:                   "",
+				CheckBuyQuantity:               "need 2 more of product 3",
+				constants.PROMOSEGMENTATIONALL: "",
+			},
+		},
+		{
+			name: "city matched ignoring case and spaces",
+			promo: func(p models.Promo) models.Promo {
+				p.Type = constants.PROMOTYPEPERCENTAGE
+				p.Segmentation = constants.PROMOSEGMENTATIONCITY
+				p.PromoCities = []models.PromoCity{{City: "Jakarta"}, {City: "Bandung"}}
+				return p
+			},
+			subject: Subject{
+				Now:  now,
+				User: models.User{City: " jakarta "},
+			},
+			want: map[string]string{
+				CheckStatus:                     "",
+				CheckDateWindow:                 "",
+				CheckUsageLimit:                 "",
+				CheckBudget:                     "",
+				CheckUserUsageLimit:             "",
+				CheckSchedule:                   "",
+				CheckRollout:                    "",
+				CheckMinOrderAmount:             "",
+				constants.PROMOSEGMENTATIONCITY: "",
+			},
+		},
+		{
+			name: "not loyal user",
+			promo: func(p models.Promo) models.Promo {
+				p.Type = constants.PROMOTYPEPERCENTAGE
+				p.Segmentation = constants.PROMOSEGMENTATIONLOYALUSER
+				return p
+			},
+			subject: Subject{
+				Now: now,
+			},
+			want: map[string]string{
+				CheckStatus:                          "",
+				CheckDateWindow:                      "",
+				CheckUsageLimit:                      "",
+				CheckBudget:                          "",
+				CheckUserUsageLimit:                  "",
+				CheckSchedule:                        "",
+				CheckRollout:                         "",
+				CheckMinOrderAmount:                  "",
+				constants.PROMOSEGMENTATIONLOYALUSER: "promo is only for loyal users",
+			},
+		},
+		{
+			name: "user registered too long ago",
+			promo: func(p models.Promo) models.Promo {
+				p.Type = constants.PROMOTYPEPERCENTAGE
+				p.Segmentation = constants.PROMOSEGMENTATIONNEWUSER
+				return p
+			},
+			subject: Subject{
+				Now:  now,
+				User: models.User{CreatedAt: now.AddDate(0, -2, 0)},
+			},
+			want: map[string]string{
+				CheckStatus:                        "",
+				CheckDateWindow:                    "",
+				CheckUsageLimit:                    "",
+				CheckBudget:                        "",
+				CheckUserUsageLimit:                "",
+				CheckSchedule:                      "",
+				CheckRollout:                       "",
+				CheckMinOrderAmount:                "",
+				constants.PROMOSEGMENTATIONNEWUSER: "promo is only for users registered in the last month",
+			},
+		},
+		{
+			name: "unknown segmentation",
+			promo: func(p models.Promo) models.Promo {
+				p.Type = constants.PROMOTYPEPERCENTAGE
+				p.Segmentation = "VIP"
+				return p
+			},
+			subject: Subject{
+				Now: now,
+			},
+			want: map[string]string{
+				CheckStatus:         "",
+				CheckDateWindow:     "",
+				CheckUsageLimit:     "",
+				CheckBudget:         "",
+				CheckUserUsageLimit: "",
+				CheckSchedule:       "",
+				CheckRollout:        "",
+				CheckMinOrderAmount: "",
+				"SEGMENTATION":      "unknown segmentation",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checks := Explain(tt.promo(active), tt.subject)
+
+			got := map[string]string{}
+			for _, check := range checks {
+				if check.Passed != (check.Message == "") {
+					t.Errorf("check %s passed = %v with message %q", check.Name, check.Passed, check.Message)
+				}
+				got[check.Name] = check.Message
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Explain() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_statusRule(t *testing.T) {
+	tests := []struct {
+		status  string
+		want    bool
+		message string
+	}{
+		{status: constants.PROMOSTATUSAPPROVED, want: true},
+		{status: constants.PROMOSTATUSDRAFT, message: "promo is a draft"},
+		{status: constants.PROMOSTATUSPENDINGAPPROVAL, message: "promo is waiting for approval"},
+		{status: constants.PROMOSTATUSREJECTED, message: "promo was rejected"},
+		{status: constants.PROMOSTATUSENDED, message: "promo was ended"},
+		{status: "ACTIVE", message: "unknown promo status"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			check := statusRule{}.Check(models.Promo{Status: tt.status}, Subject{})
+			if check.Passed != tt.want {
+				t.Errorf("statusRule.Check() passed = %v, want %v", check.Passed, tt.want)
+			}
+			if check.Message != tt.message {
+				t.Errorf("statusRule.Check() message = %q, want %q", check.Message, tt.message)
+			}
+		})
+	}
+}
+
+func Test_budgetRule(t *testing.T) {
+	budget := 100.0
+	freeProduct := &models.Product{ID: 2, Price: 30}
+
+	tests := []struct {
+		name    string
+		promo   models.Promo
+		want    bool
+		message string
+	}{
+		{name: "without budget", promo: models.Promo{SpentAmount: 500}, want: true},
+		{name: "budget left", promo: models.Promo{MaxBudgetAmount: &budget, SpentAmount: 99.99}, want: true},
+		{name: "budget used up", promo: models.Promo{MaxBudgetAmount: &budget, SpentAmount: 100}, message: "promo budget has been used up"},
+		{name: "budget left for a free item", promo: models.Promo{MaxBudgetAmount: &budget, SpentAmount: 70, FreeProduct: freeProduct}, want: true},
+		{name: "budget left below the free item price", promo: models.Promo{MaxBudgetAmount: &budget, SpentAmount: 80, FreeProduct: freeProduct}, message: "promo budget has been used up"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := budgetRule{}.Check(tt.promo, Subject{})
+			if check.Passed != tt.want {
+				t.Errorf("budgetRule.Check() passed = %v, want %v", check.Passed, tt.want)
+			}
+			if check.Message != tt.message {
+				t.Errorf("budgetRule.Check() message = %q, want %q", check.Message, tt.message)
+			}
+		})
+	}
+}
+
+func Test_scheduleRule(t *testing.T) {
+	// 2024-06-14 is a Friday, 09:30 UTC is 16:30 in Jakarta
+	now := time.Date(2024, 6, 14, 9, 30, 0, 0, time.UTC)
+	weekdays := []models.PromoSchedule{
+		{DayOfWeek: 1, StartTime: "14:00", EndTime: "17:00"},
+		{DayOfWeek: 5, StartTime: "14:00", EndTime: "17:00"},
+	}
+
+	tests := []struct {
+		name    string
+		promo   models.Promo
+		now     time.Time
+		want    bool
+		message string
+	}{
+		{
+			name:  "no schedules",
+			promo: models.Promo{},
+			now:   now,
+			want:  true,
+		},
+		{
+			name:  "inside window in the default timezone",
+			promo: models.Promo{PromoSchedules: weekdays},
+			now:   now,
+			want:  true,
+		},
+		{
+			name:    "window read in the promo timezone",
+			promo:   models.Promo{Timezone: "UTC", PromoSchedules: weekdays},
+			now:     now,
+			want:    false,
+			message: "promo is not available at this time",
+		},
+		{
+			name:    "end of window is exclusive",
+			promo:   models.Promo{PromoSchedules: weekdays},
+			now:     now.Add(30 * time.Minute),
+			want:    false,
+			message: "promo is not available at this time",
+		},
+		{
+			name:    "other day",
+			promo:   models.Promo{PromoSchedules: weekdays},
+			now:     now.AddDate(0, 0, 1),
+			want:    false,
+			message: "promo is not available at this time",
+		},
+		{
+			name: "window past midnight on the next day",
+			promo: models.Promo{PromoSchedules: []models.PromoSchedule{
+				{DayOfWeek: 4, StartTime: "22:00", EndTime: "02:00"},
+			}},
+			// Friday 01:00 in Jakarta
+			now:  time.Date(2024, 6, 13, 18, 0, 0, 0, time.UTC),
+			want: true,
+		},
+		{
+			name: "window past midnight two days later",
+			promo: models.Promo{PromoSchedules: []models.PromoSchedule{
+				{DayOfWeek: 3, StartTime: "22:00", EndTime: "02:00"},
+			}},
+			now:     time.Date(2024, 6, 13, 18, 0, 0, 0, time.UTC),
+			want:    false,
+			message: "promo is not available at this time",
+		},
+		{
+			name:    "unknown timezone",
+			promo:   models.Promo{Timezone: "Mars/Olympus", PromoSchedules: weekdays},
+			now:     now,
+			want:    false,
+			message: "unknown timezone Mars/Olympus",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := scheduleRule{}.Check(tt.promo, Subject{Now: tt.now})
+			if check.Passed != tt.want {
+				t.Errorf("scheduleRule.Check() passed = %v, want %v (%s, %s)", check.Passed, tt.want, check.Actual, check.Required)
+			}
+			if check.Message != tt.message {
+				t.Errorf("scheduleRule.Check() message = %q, want %q", check.Message, tt.message)
+			}
+		})
+	}
+}
+
+func Test_lifecycleRules(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	days := func(n int) *int { return &n }
+
+	tests := []struct {
+		name    string
+		promo   models.Promo
+		subject Subject
+		want    bool
+		message string
+	}{
+		{
+			name:    "new user in the default month",
+			promo:   models.Promo{Segmentation: constants.PROMOSEGMENTATIONNEWUSER},
+			subject: Subject{Now: now, User: models.User{CreatedAt: now.AddDate(0, 0, -20)}},
+			want:    true,
+		},
+		{
+			name:    "new user outside the promo window",
+			promo:   models.Promo{Segmentation: constants.PROMOSEGMENTATIONNEWUSER, NewUserDays: days(14)},
+			subject: Subject{Now: now, User: models.User{CreatedAt: now.AddDate(0, 0, -20)}},
+			want:    false,
+			message: "promo is only for users registered in the last 14 days",
+		},
+		{
+			name:    "first order",
+			promo:   models.Promo{Segmentation: constants.PROMOSEGMENTATIONFIRSTORDER},
+			subject: Subject{Now: now},
+			want:    true,
+		},
+		{
+			name:    "first order after ordering",
+			promo:   models.Promo{Segmentation: constants.PROMOSEGMENTATIONFIRSTORDER},
+			subject: Subject{Now: now, OrderCount: 2, LastOrderAt: now.AddDate(0, 0, -1)},
+			want:    false,
+			message: "promo is only for the user's first order",
+		},
+		{
+			name:    "lapsed user",
+			promo:   models.Promo{Segmentation: constants.PROMOSEGMENTATIONLAPSEDUSER, LapsedDays: days(30)},
+			subject: Subject{Now: now, OrderCount: 3, LastOrderAt: now.AddDate(0, 0, -45)},
+			want:    true,
+		},
+		{
+			name:    "lapsed user ordered recently",
+			promo:   models.Promo{Segmentation: constants.PROMOSEGMENTATIONLAPSEDUSER, LapsedDays: days(30)},
+			subject: Subject{Now: now, OrderCount: 3, LastOrderAt: now.AddDate(0, 0, -10)},
+			want:    false,
+			message: "promo is only for users who have not ordered in the last 30 days",
+		},
+		{
+			name:    "lapsed user never ordered",
+			promo:   models.Promo{Segmentation: constants.PROMOSEGMENTATIONLAPSEDUSER, LapsedDays: days(30)},
+			subject: Subject{Now: now},
+			want:    false,
+			message: "promo is only for users who have ordered before",
+		},
+		{
+			name:    "lapsed user without lapsed days",
+			promo:   models.Promo{Segmentation: constants.PROMOSEGMENTATIONLAPSEDUSER},
+			subject: Subject{Now: now, OrderCount: 3, LastOrderAt: now.AddDate(0, 0, -45)},
+			want:    false,
+			message: "promo has no lapsed days",
+		},
+		{
+			name:    "third order",
+			promo:   models.Promo{Segmentation: constants.PROMOSEGMENTATIONNTHORDER, OrderNumber: days(3)},
+			subject: Subject{Now: now, OrderCount: 2, LastOrderAt: now.AddDate(0, 0, -1)},
+			want:    true,
+		},
+		{
+			name:    "fourth order of a third order promo",
+			promo:   models.Promo{Segmentation: constants.PROMOSEGMENTATIONNTHORDER, OrderNumber: days(3)},
+			subject: Subject{Now: now, OrderCount: 3, LastOrderAt: now.AddDate(0, 0, -1)},
+			want:    false,
+			message: "promo is only for the user's order number 3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := segmentationRules[tt.promo.Segmentation].Check(tt.promo, tt.subject)
+			if check.Passed != tt.want {
+				t.Errorf("%s.Check() passed = %v, want %v (%s, %s)", tt.promo.Segmentation, check.Passed, tt.want, check.Actual, check.Required)
+			}
+			if check.Message != tt.message {
+				t.Errorf("%s.Check() message = %q, want %q", tt.promo.Segmentation, check.Message, tt.message)
+			}
+		})
+	}
+}
+
+func Test_userListRule(t *testing.T) {
+	promo := models.Promo{
+		Segmentation: constants.PROMOSEGMENTATIONUSERLIST,
+		PromoUsers:   []models.PromoUser{{PromoID: 1, UserID: 7}},
+	}
+
+	if check := (userListRule{}).Check(promo, Subject{User: models.User{ID: 7}}); !check.Passed {
+		t.Errorf("userListRule.Check() listed user passed = false, want true")
+	}
+
+	check := (userListRule{}).Check(promo, Subject{User: models.User{ID: 8}})
+	if check.Passed {
+		t.Errorf("userListRule.Check() unlisted user passed = true, want false")
+	}
+	if want := "promo is only for the users listed for it"; check.Message != want {
+		t.Errorf("userListRule.Check() message = %q, want %q", check.Message, want)
+	}
+}
+
+func Test_rolloutRule(t *testing.T) {
+	percentage := func(n int) *int { return &n }
+
+	bucket := RolloutBucket(1, 7)
+	if again := RolloutBucket(1, 7); again != bucket {
+		t.Fatalf("RolloutBucket() = %v then %v, want the same bucket", bucket, again)
+	}
+
+	tests := []struct {
+		name       string
+		percentage *int
+		want       bool
+	}{
+		{name: "no rollout percentage", percentage: nil, want: true},
+		{name: "bucket inside the rollout", percentage: percentage(bucket + 1), want: true},
+		{name: "bucket outside the rollout", percentage: percentage(bucket), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			promo := models.Promo{ID: 1, RolloutPercentage: tt.percentage}
+			check := rolloutRule{}.Check(promo, Subject{User: models.User{ID: 7}})
+			if check.Passed != tt.want {
+				t.Errorf("rolloutRule.Check() passed = %v, want %v (%s, %s)", check.Passed, tt.want, check.Actual, check.Required)
+			}
+		})
+	}
+}
+
+func TestNeedsOrderHistory(t *testing.T) {
+	all := models.Promo{Segmentation: constants.PROMOSEGMENTATIONALL}
+	firstOrder := models.Promo{Segmentation: constants.PROMOSEGMENTATIONFIRSTORDER}
+
+	if NeedsOrderHistory(all) {
+		t.Errorf("NeedsOrderHistory(ALL) = true, want false")
+	}
+	if !NeedsOrderHistory(all, firstOrder) {
+		t.Errorf("NeedsOrderHistory(ALL, FIRST_ORDER) = false, want true")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	buyProductId := uint(1)
+	// a whole day, but not the one the subject is checked on
+	closedDay := (time.Now().AddDate(1, 0, 0).Weekday() + 3) % 7
+	promos := []models.Promo{
+		{ID: 1, Type: constants.PROMOTYPEPERCENTAGE, Segmentation: constants.PROMOSEGMENTATIONALL, MinOrderAmount: 10000},
+		{ID: 2, Type: constants.PROMOTYPEPERCENTAGE, Segmentation: constants.PROMOSEGMENTATIONALL, MinOrderAmount: 50000},
+		{ID: 3, Type: constants.PROMOTYPEBUYXGETY, Segmentation: constants.PROMOSEGMENTATIONALL, BuyProductID: &buyProductId, BuyProductQty: 2},
+		{ID: 4, Type: constants.PROMOTYPEFIXEDAMOUNT, Segmentation: constants.PROMOSEGMENTATIONCITY, PromoCities: []models.PromoCity{{City: "Jakarta"}}},
+		{ID: 5, Type: constants.PROMOTYPEFIXEDAMOUNT, Segmentation: constants.PROMOSEGMENTATIONCITY, PromoCities: []models.PromoCity{{City: "Surabaya"}}},
+		{ID: 6, Type: constants.PROMOTYPEFIXEDAMOUNT, Segmentation: constants.PROMOSEGMENTATIONLOYALUSER},
+		{ID: 7, Type: constants.PROMOTYPEFIXEDAMOUNT, Segmentation: constants.PROMOSEGMENTATIONALL, PromoSchedules: []models.PromoSchedule{
+			{DayOfWeek: int(closedDay), StartTime: "00:00", EndTime: "00:00"},
+		}},
+	}
+
+	// availability is left to the promo query, an ended promo is kept
+	now := time.Now().AddDate(1, 0, 0)
+	subject := Subject{
+		Now:   now,
+		User:  models.User{City: "Jakarta"},
+		Lines: []pricing.Line{{ProductID: 1, Price: 10000, Quantity: 2}},
+	}
+
+	got := []uint{}
+	for _, promo := range Filter(promos, subject) {
+		got = append(got, promo.ID)
+	}
+
+	if want := []uint{1, 3, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Filter() = %v, want %v", got, want)
+	}
+}
+
+func TestSegmentations(t *testing.T) {
+	want := []string{"ALL", "CITY", "FIRST_ORDER", "LAPSED_USER", "LOYAL_USER", "NEW_USER", "NTH_ORDER", "USER_LIST"}
+	if got := Segmentations(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Segmentations() = %v, want %v", got, want)
+	}
+	if IsSegmentation("VIP") {
+		t.Errorf("IsSegmentation(VIP) = true, want false")
+	}
+}
+
+func TestEligible(t *testing.T) {
+	if !Eligible([]Check{{Passed: true}, {Passed: true}}) {
+		t.Errorf("Eligible() = false, want true")
+	}
+	if Eligible([]Check{{Passed: true}, {Passed: false}}) {
+		t.Errorf("Eligible() = true, want false")
+	}
+}
+
+func Test_formatRupiah(t *testing.T) {
+	tests := []struct {
+		amount float64
+		want   string
+	}{
+		{0, "Rp0"},
+		{500, "Rp500"},
+		{12000, "Rp12.000"},
+		{1250000.4, "Rp1.250.000"},
+	}
+	for _, tt := range tests {
+		if got := formatRupiah(tt.amount); got != tt.want {
+			t.Errorf("formatRupiah(%v) = %v, want %v", tt.amount, got, tt.want)
+		}
+	}
+}
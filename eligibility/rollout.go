@@ -0,0 +1,42 @@
+package eligibility
+
+import (
+	"fmt"
+	"hangry/domain/models"
+	"hangry/utils"
+	"strconv"
+)
+
+const CheckRollout = "ROLLOUT"
+
+// rolloutRule lets only the users whose bucket falls in the promo's rollout
+// percentage get it. A promo without a rollout percentage is for everyone.
+type rolloutRule struct{}
+
+func (rolloutRule) Check(promo models.Promo, subject Subject) Check {
+	check := Check{
+		Name:     CheckRollout,
+		Passed:   true,
+		Required: "100%",
+	}
+
+	if promo.RolloutPercentage == nil {
+		return check
+	}
+
+	bucket := RolloutBucket(promo.ID, subject.User.ID)
+	check.Actual = "bucket " + strconv.Itoa(bucket)
+	check.Required = "bucket below " + strconv.Itoa(*promo.RolloutPercentage)
+	if bucket >= *promo.RolloutPercentage {
+		check.Passed = false
+		check.Message = "promo is not rolled out to the user yet"
+	}
+
+	return check
+}
+
+// RolloutBucket is the user's bucket, 0 to 99, for the promo's rollout. Raising
+// the rollout percentage keeps every user that already had the promo.
+func RolloutBucket(promoID uint, userID uint) int {
+	return utils.Bucket(fmt.Sprintf("rollout:%d:%d", promoID, userID), 100)
+}
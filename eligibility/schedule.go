@@ -0,0 +1,101 @@
+package eligibility
+
+import (
+	"hangry/constants"
+	"hangry/domain/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const CheckSchedule = "SCHEDULE"
+
+// scheduleRule lets the promo be redeemed only inside one of its schedules,
+// read in the promo's timezone. A promo without schedules is always open.
+type scheduleRule struct{}
+
+func (scheduleRule) Check(promo models.Promo, subject Subject) Check {
+	timezone := promo.Timezone
+	if timezone == "" {
+		timezone = constants.PROMODEFAULTTIMEZONE
+	}
+
+	check := Check{
+		Name:     CheckSchedule,
+		Passed:   true,
+		Actual:   subject.Now.Format(time.RFC3339),
+		Required: "any time",
+	}
+
+	if len(promo.PromoSchedules) == 0 {
+		return check
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		check.Passed = false
+		check.Message = "unknown timezone " + timezone
+		return check
+	}
+
+	now := subject.Now.In(location)
+	check.Actual = now.Format("Mon 15:04") + " " + timezone
+
+	windows := make([]string, 0, len(promo.PromoSchedules))
+	check.Passed = false
+	for _, schedule := range promo.PromoSchedules {
+		windows = append(windows, time.Weekday(schedule.DayOfWeek).String()[:3]+" "+schedule.StartTime+"-"+schedule.EndTime)
+		if scheduleOpen(schedule, now) {
+			check.Passed = true
+		}
+	}
+	check.Required = strings.Join(windows, ", ")
+
+	if !check.Passed {
+		check.Message = "promo is not available at this time"
+	}
+
+	return check
+}
+
+// scheduleOpen reports whether now, already in the promo's timezone, falls in
+// the schedule. A window whose end is not after its start runs past midnight.
+func scheduleOpen(schedule models.PromoSchedule, now time.Time) bool {
+	start, ok := clockMinutes(schedule.StartTime)
+	if !ok {
+		return false
+	}
+	end, ok := clockMinutes(schedule.EndTime)
+	if !ok {
+		return false
+	}
+
+	day := int(now.Weekday())
+	minute := now.Hour()*60 + now.Minute()
+
+	if start < end {
+		return day == schedule.DayOfWeek && minute >= start && minute < end
+	}
+
+	return (day == schedule.DayOfWeek && minute >= start) ||
+		(day == (schedule.DayOfWeek+1)%7 && minute < end)
+}
+
+// clockMinutes returns the minutes since midnight of a HH:MM time.
+func clockMinutes(clock string) (int, bool) {
+	hour, minute, found := strings.Cut(clock, ":")
+	if !found {
+		return 0, false
+	}
+
+	h, err := strconv.Atoi(hour)
+	if err != nil || h < 0 || h > 23 {
+		return 0, false
+	}
+	m, err := strconv.Atoi(minute)
+	if err != nil || m < 0 || m > 59 {
+		return 0, false
+	}
+
+	return h*60 + m, true
+}
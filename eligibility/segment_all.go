@@ -0,0 +1,22 @@
+package eligibility
+
+import (
+	"hangry/constants"
+	"hangry/domain/models"
+)
+
+func init() {
+	RegisterSegmentation(constants.PROMOSEGMENTATIONALL, allRule{})
+}
+
+// allRule lets every user get the promo.
+type allRule struct{}
+
+func (allRule) Check(promo models.Promo, subject Subject) Check {
+	return Check{
+		Name:     constants.PROMOSEGMENTATIONALL,
+		Passed:   true,
+		Actual:   "any user",
+		Required: "any user",
+	}
+}
@@ -0,0 +1,41 @@
+package eligibility
+
+import (
+	"hangry/constants"
+	"hangry/domain/models"
+	"hangry/utils"
+	"strings"
+)
+
+func init() {
+	RegisterSegmentation(constants.PROMOSEGMENTATIONCITY, cityRule{})
+}
+
+// cityRule matches the user's city against the promo cities, ignoring case and
+// extra spaces.
+// The promo must have its PromoCities loaded.
+type cityRule struct{}
+
+func (cityRule) Check(promo models.Promo, subject Subject) Check {
+	cities := make([]string, 0, len(promo.PromoCities))
+	passed := false
+	for _, promoCity := range promo.PromoCities {
+		cities = append(cities, promoCity.City)
+		if strings.EqualFold(promoCity.City, utils.NormalizeCity(subject.User.City)) {
+			passed = true
+		}
+	}
+
+	check := Check{
+		Name:     constants.PROMOSEGMENTATIONCITY,
+		Passed:   passed,
+		Actual:   subject.User.City,
+		Required: strings.Join(cities, ", "),
+	}
+
+	if !passed {
+		check.Message = "promo is not available in the user's city"
+	}
+
+	return check
+}
@@ -0,0 +1,31 @@
+package eligibility
+
+import (
+	"hangry/constants"
+	"hangry/domain/models"
+	"strconv"
+)
+
+func init() {
+	RegisterSegmentation(constants.PROMOSEGMENTATIONFIRSTORDER, firstOrderRule{})
+}
+
+// firstOrderRule lets users who have never ordered get the promo.
+type firstOrderRule struct{}
+
+func (firstOrderRule) usesOrderHistory() {}
+
+func (firstOrderRule) Check(promo models.Promo, subject Subject) Check {
+	check := Check{
+		Name:     constants.PROMOSEGMENTATIONFIRSTORDER,
+		Passed:   subject.OrderCount == 0,
+		Actual:   strconv.Itoa(subject.OrderCount) + " orders",
+		Required: "0 orders",
+	}
+
+	if !check.Passed {
+		check.Message = "promo is only for the user's first order"
+	}
+
+	return check
+}
@@ -0,0 +1,43 @@
+package eligibility
+
+import (
+	"hangry/constants"
+	"hangry/domain/models"
+	"strconv"
+	"time"
+)
+
+func init() {
+	RegisterSegmentation(constants.PROMOSEGMENTATIONLAPSEDUSER, lapsedUserRule{})
+}
+
+// lapsedUserRule lets users who have ordered before, but not in the promo's
+// LapsedDays, get the promo. Users who never ordered are not lapsed.
+type lapsedUserRule struct{}
+
+func (lapsedUserRule) usesOrderHistory() {}
+
+func (lapsedUserRule) Check(promo models.Promo, subject Subject) Check {
+	check := Check{Name: constants.PROMOSEGMENTATIONLAPSEDUSER}
+	if promo.LapsedDays == nil {
+		check.Message = "promo has no lapsed days"
+		return check
+	}
+
+	orderedBefore := subject.Now.AddDate(0, 0, -*promo.LapsedDays)
+	check.Required = "last order before " + orderedBefore.Format(time.RFC3339)
+
+	if subject.LastOrderAt.IsZero() {
+		check.Actual = "no order"
+		check.Message = "promo is only for users who have ordered before"
+		return check
+	}
+
+	check.Actual = subject.LastOrderAt.Format(time.RFC3339)
+	check.Passed = subject.LastOrderAt.Before(orderedBefore)
+	if !check.Passed {
+		check.Message = "promo is only for users who have not ordered in the last " + strconv.Itoa(*promo.LapsedDays) + " days"
+	}
+
+	return check
+}
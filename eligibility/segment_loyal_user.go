@@ -0,0 +1,29 @@
+package eligibility
+
+import (
+	"hangry/constants"
+	"hangry/domain/models"
+	"strconv"
+)
+
+func init() {
+	RegisterSegmentation(constants.PROMOSEGMENTATIONLOYALUSER, loyalUserRule{})
+}
+
+// loyalUserRule lets users marked as loyal get the promo.
+type loyalUserRule struct{}
+
+func (loyalUserRule) Check(promo models.Promo, subject Subject) Check {
+	check := Check{
+		Name:     constants.PROMOSEGMENTATIONLOYALUSER,
+		Passed:   subject.User.IsLoyal,
+		Actual:   strconv.FormatBool(subject.User.IsLoyal),
+		Required: "true",
+	}
+
+	if !check.Passed {
+		check.Message = "promo is only for loyal users"
+	}
+
+	return check
+}
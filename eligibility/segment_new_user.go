@@ -0,0 +1,38 @@
+package eligibility
+
+import (
+	"hangry/constants"
+	"hangry/domain/models"
+	"strconv"
+	"time"
+)
+
+func init() {
+	RegisterSegmentation(constants.PROMOSEGMENTATIONNEWUSER, newUserRule{})
+}
+
+// newUserRule lets users registered in the promo's NewUserDays, or in the
+// last month when it is not set, get the promo.
+type newUserRule struct{}
+
+func (newUserRule) Check(promo models.Promo, subject Subject) Check {
+	registeredAfter := subject.Now.AddDate(0, -1, 0)
+	window := "the last month"
+	if promo.NewUserDays != nil {
+		registeredAfter = subject.Now.AddDate(0, 0, -*promo.NewUserDays)
+		window = "the last " + strconv.Itoa(*promo.NewUserDays) + " days"
+	}
+
+	check := Check{
+		Name:     constants.PROMOSEGMENTATIONNEWUSER,
+		Passed:   subject.User.CreatedAt.After(registeredAfter),
+		Actual:   subject.User.CreatedAt.Format(time.RFC3339),
+		Required: "after " + registeredAfter.Format(time.RFC3339),
+	}
+
+	if !check.Passed {
+		check.Message = "promo is only for users registered in " + window
+	}
+
+	return check
+}
@@ -0,0 +1,36 @@
+package eligibility
+
+import (
+	"hangry/constants"
+	"hangry/domain/models"
+	"strconv"
+)
+
+func init() {
+	RegisterSegmentation(constants.PROMOSEGMENTATIONNTHORDER, nthOrderRule{})
+}
+
+// nthOrderRule lets users get the promo on their OrderNumber-th order, e.g. on
+// the third order when OrderNumber is 3.
+type nthOrderRule struct{}
+
+func (nthOrderRule) usesOrderHistory() {}
+
+func (nthOrderRule) Check(promo models.Promo, subject Subject) Check {
+	check := Check{
+		Name:   constants.PROMOSEGMENTATIONNTHORDER,
+		Actual: "order " + strconv.Itoa(subject.OrderCount+1),
+	}
+	if promo.OrderNumber == nil {
+		check.Message = "promo has no order number"
+		return check
+	}
+
+	check.Required = "order " + strconv.Itoa(*promo.OrderNumber)
+	check.Passed = subject.OrderCount+1 == *promo.OrderNumber
+	if !check.Passed {
+		check.Message = "promo is only for the user's order number " + strconv.Itoa(*promo.OrderNumber)
+	}
+
+	return check
+}
@@ -0,0 +1,35 @@
+package eligibility
+
+import (
+	"hangry/constants"
+	"hangry/domain/models"
+	"strconv"
+)
+
+func init() {
+	RegisterSegmentation(constants.PROMOSEGMENTATIONUSERLIST, userListRule{})
+}
+
+// userListRule lets the users listed for the promo get it. The promo must have
+// its PromoUsers loaded, at least the row of the subject's user when listed.
+type userListRule struct{}
+
+func (userListRule) Check(promo models.Promo, subject Subject) Check {
+	check := Check{
+		Name:     constants.PROMOSEGMENTATIONUSERLIST,
+		Actual:   "user " + strconv.FormatUint(uint64(subject.User.ID), 10),
+		Required: "listed user",
+	}
+
+	for _, promoUser := range promo.PromoUsers {
+		if subject.User.ID != 0 && promoUser.UserID == subject.User.ID {
+			check.Passed = true
+		}
+	}
+
+	if !check.Passed {
+		check.Message = "promo is only for the users listed for it"
+	}
+
+	return check
+}
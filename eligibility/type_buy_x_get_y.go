@@ -0,0 +1,40 @@
+package eligibility
+
+import (
+	"fmt"
+	"hangry/constants"
+	"hangry/domain/models"
+	"strconv"
+)
+
+const CheckBuyQuantity = "BUY_QUANTITY"
+
+func init() {
+	RegisterType(constants.PROMOTYPEBUYXGETY, buyQuantityRule{})
+}
+
+// buyQuantityRule requires the cart to hold at least the promo's buy quantity
+// of the buy product.
+type buyQuantityRule struct{}
+
+func (buyQuantityRule) Check(promo models.Promo, subject Subject) Check {
+	quantity := 0
+	for _, line := range subject.Lines {
+		if promo.BuyProductID != nil && line.ProductID == *promo.BuyProductID {
+			quantity += line.Quantity
+		}
+	}
+
+	check := Check{
+		Name:     CheckBuyQuantity,
+		Passed:   quantity >= promo.BuyProductQty,
+		Actual:   strconv.Itoa(quantity),
+		Required: strconv.Itoa(promo.BuyProductQty),
+	}
+
+	if !check.Passed && promo.BuyProductID != nil {
+		check.Message = fmt.Sprintf("need %d more of product %d", promo.BuyProductQty-quantity, *promo.BuyProductID)
+	}
+
+	return check
+}
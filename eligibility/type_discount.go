@@ -0,0 +1,52 @@
+package eligibility
+
+import (
+	"hangry/constants"
+	"hangry/domain/models"
+	"hangry/pricing"
+	"strconv"
+	"strings"
+)
+
+const CheckMinOrderAmount = "MIN_ORDER_AMOUNT"
+
+func init() {
+	RegisterType(constants.PROMOTYPEPERCENTAGE, minOrderAmountRule{})
+	RegisterType(constants.PROMOTYPEFIXEDAMOUNT, minOrderAmountRule{})
+}
+
+// minOrderAmountRule lets discounts through once the cart subtotal reaches the
+// promo's min order amount. For a product scoped promo only the matching lines
+// count, and at least one of them has to be in the cart.
+type minOrderAmountRule struct{}
+
+func (minOrderAmountRule) Check(promo models.Promo, subject Subject) Check {
+	subtotal, productIDs := pricing.TargetLines(subject.Lines, promo)
+
+	check := Check{
+		Name:     CheckMinOrderAmount,
+		Passed:   subtotal >= promo.MinOrderAmount,
+		Actual:   formatRupiah(subtotal),
+		Required: formatRupiah(promo.MinOrderAmount),
+	}
+
+	if len(promo.PromoProducts) > 0 {
+		targets := make([]string, 0, len(promo.PromoProducts))
+		for _, promoProduct := range promo.PromoProducts {
+			targets = append(targets, strconv.FormatUint(uint64(promoProduct.ProductID), 10))
+		}
+		check.Required += " of products " + strings.Join(targets, ", ")
+
+		if len(productIDs) == 0 {
+			check.Passed = false
+			check.Message = "cart has none of the promo products"
+			return check
+		}
+	}
+
+	if !check.Passed {
+		check.Message = "need " + formatRupiah(promo.MinOrderAmount-subtotal) + " more"
+	}
+
+	return check
+}
@@ -0,0 +1,53 @@
+package eligibility
+
+import (
+	"hangry/constants"
+	"hangry/domain/models"
+	"hangry/pricing"
+)
+
+const CheckSpendTier = "SPEND_TIER"
+
+func init() {
+	RegisterType(constants.PROMOTYPETIERED, spendTierRule{})
+}
+
+// spendTierRule lets tiered discounts through once the cart subtotal reaches
+// the lowest tier.
+type spendTierRule struct{}
+
+func (spendTierRule) Check(promo models.Promo, subject Subject) Check {
+	subtotal := 0.0
+	for _, line := range subject.Lines {
+		subtotal += line.Price * float64(line.Quantity)
+	}
+
+	check := Check{
+		Name:   CheckSpendTier,
+		Actual: formatRupiah(subtotal),
+	}
+
+	if len(promo.PromoTiers) == 0 {
+		check.Message = "promo has no tiers"
+		return check
+	}
+
+	lowest := promo.PromoTiers[0]
+	for _, tier := range promo.PromoTiers {
+		if tier.MinAmount < lowest.MinAmount {
+			lowest = tier
+		}
+	}
+	check.Required = formatRupiah(lowest.MinAmount)
+
+	tier, ok := pricing.SelectTier(promo.PromoTiers, subtotal)
+	if !ok {
+		check.Message = "need " + formatRupiah(lowest.MinAmount-subtotal) + " more"
+		return check
+	}
+
+	check.Passed = true
+	check.Actual += ", tier " + formatRupiah(tier.MinAmount) + " reached"
+
+	return check
+}
@@ -17,34 +17,87 @@ import (
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/labstack/echo/v4"
 	"github.com/oapi-codegen/runtime"
+	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
 // Defines values for CreatePromoRequestSegmentation.
 const (
-	CreatePromoRequestSegmentationALL       CreatePromoRequestSegmentation = "ALL"
-	CreatePromoRequestSegmentationCITY      CreatePromoRequestSegmentation = "CITY"
-	CreatePromoRequestSegmentationLOYALUSER CreatePromoRequestSegmentation = "LOYAL_USER"
-	CreatePromoRequestSegmentationNEWUSER   CreatePromoRequestSegmentation = "NEW_USER"
+	CreatePromoRequestSegmentationALL        CreatePromoRequestSegmentation = "ALL"
+	CreatePromoRequestSegmentationCITY       CreatePromoRequestSegmentation = "CITY"
+	CreatePromoRequestSegmentationFIRSTORDER CreatePromoRequestSegmentation = "FIRST_ORDER"
+	CreatePromoRequestSegmentationLAPSEDUSER CreatePromoRequestSegmentation = "LAPSED_USER"
+	CreatePromoRequestSegmentationLOYALUSER  CreatePromoRequestSegmentation = "LOYAL_USER"
+	CreatePromoRequestSegmentationNEWUSER    CreatePromoRequestSegmentation = "NEW_USER"
+	CreatePromoRequestSegmentationNTHORDER   CreatePromoRequestSegmentation = "NTH_ORDER"
+	CreatePromoRequestSegmentationUSERLIST   CreatePromoRequestSegmentation = "USER_LIST"
 )
 
 // Defines values for CreatePromoRequestType.
 const (
-	CreatePromoRequestTypeBUYXGETYFREE       CreatePromoRequestType = "BUY_X_GET_Y_FREE"
-	CreatePromoRequestTypePERCENTAGEDISCOUNT CreatePromoRequestType = "PERCENTAGE_DISCOUNT"
+	CreatePromoRequestTypeBUYXGETYFREE        CreatePromoRequestType = "BUY_X_GET_Y_FREE"
+	CreatePromoRequestTypeFIXEDAMOUNTDISCOUNT CreatePromoRequestType = "FIXED_AMOUNT_DISCOUNT"
+	CreatePromoRequestTypePERCENTAGEDISCOUNT  CreatePromoRequestType = "PERCENTAGE_DISCOUNT"
+	CreatePromoRequestTypeTIEREDDISCOUNT      CreatePromoRequestType = "TIERED_DISCOUNT"
 )
 
 // Defines values for PromoSegmentation.
 const (
-	PromoSegmentationALL       PromoSegmentation = "ALL"
-	PromoSegmentationCITY      PromoSegmentation = "CITY"
-	PromoSegmentationLOYALUSER PromoSegmentation = "LOYAL_USER"
-	PromoSegmentationNEWUSER   PromoSegmentation = "NEW_USER"
+	PromoSegmentationALL        PromoSegmentation = "ALL"
+	PromoSegmentationCITY       PromoSegmentation = "CITY"
+	PromoSegmentationFIRSTORDER PromoSegmentation = "FIRST_ORDER"
+	PromoSegmentationLAPSEDUSER PromoSegmentation = "LAPSED_USER"
+	PromoSegmentationLOYALUSER  PromoSegmentation = "LOYAL_USER"
+	PromoSegmentationNEWUSER    PromoSegmentation = "NEW_USER"
+	PromoSegmentationNTHORDER   PromoSegmentation = "NTH_ORDER"
+	PromoSegmentationUSERLIST   PromoSegmentation = "USER_LIST"
+)
+
+// Defines values for PromoStatus.
+const (
+	PromoStatusAPPROVED        PromoStatus = "APPROVED"
+	PromoStatusARCHIVED        PromoStatus = "ARCHIVED"
+	PromoStatusDRAFT           PromoStatus = "DRAFT"
+	PromoStatusENDED           PromoStatus = "ENDED"
+	PromoStatusPAUSED          PromoStatus = "PAUSED"
+	PromoStatusPENDINGAPPROVAL PromoStatus = "PENDING_APPROVAL"
+	PromoStatusREJECTED        PromoStatus = "REJECTED"
 )
 
 // Defines values for PromoType.
 const (
-	PromoTypeBUYXGETYFREE       PromoType = "BUY_X_GET_Y_FREE"
-	PromoTypePERCENTAGEDISCOUNT PromoType = "PERCENTAGE_DISCOUNT"
+	PromoTypeBUYXGETYFREE        PromoType = "BUY_X_GET_Y_FREE"
+	PromoTypeFIXEDAMOUNTDISCOUNT PromoType = "FIXED_AMOUNT_DISCOUNT"
+	PromoTypePERCENTAGEDISCOUNT  PromoType = "PERCENTAGE_DISCOUNT"
+	PromoTypeTIEREDDISCOUNT      PromoType = "TIERED_DISCOUNT"
+)
+
+// Defines values for PromoAuditLogAction.
+const (
+	ADDCITIES    PromoAuditLogAction = "ADD_CITIES"
+	ADDUSERS     PromoAuditLogAction = "ADD_USERS"
+	CHANGESTATUS PromoAuditLogAction = "CHANGE_STATUS"
+	CREATE       PromoAuditLogAction = "CREATE"
+	EXTEND       PromoAuditLogAction = "EXTEND"
+	REMOVECITY   PromoAuditLogAction = "REMOVE_CITY"
+	REMOVEUSERS  PromoAuditLogAction = "REMOVE_USERS"
+	UPDATE       PromoAuditLogAction = "UPDATE"
+)
+
+// Defines values for PostPromoIdUsersCsvParamsAction.
+const (
+	Add    PostPromoIdUsersCsvParamsAction = "add"
+	Remove PostPromoIdUsersCsvParamsAction = "remove"
+)
+
+// Defines values for GetPromosParamsStatus.
+const (
+	GetPromosParamsStatusAPPROVED        GetPromosParamsStatus = "APPROVED"
+	GetPromosParamsStatusARCHIVED        GetPromosParamsStatus = "ARCHIVED"
+	GetPromosParamsStatusDRAFT           GetPromosParamsStatus = "DRAFT"
+	GetPromosParamsStatusENDED           GetPromosParamsStatus = "ENDED"
+	GetPromosParamsStatusPAUSED          GetPromosParamsStatus = "PAUSED"
+	GetPromosParamsStatusPENDINGAPPROVAL GetPromosParamsStatus = "PENDING_APPROVAL"
+	GetPromosParamsStatusREJECTED        GetPromosParamsStatus = "REJECTED"
 )
 
 // AddCartRequest defines model for AddCartRequest.
@@ -54,23 +107,114 @@ type AddCartRequest struct {
 	UserId    int `json:"userId"`
 }
 
+// AddPromoCitiesRequest defines model for AddPromoCitiesRequest.
+type AddPromoCitiesRequest struct {
+	Cities []string `json:"cities"`
+}
+
+// Cart defines model for Cart.
+type Cart struct {
+	CartId   int        `json:"cart_id"`
+	Items    []CartItem `json:"items"`
+	Subtotal float32    `json:"subtotal"`
+	UserId   int        `json:"user_id"`
+}
+
+// CartItem defines model for CartItem.
+type CartItem struct {
+	LineTotal   float32 `json:"line_total"`
+	ProductId   int     `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	Quantity    int     `json:"quantity"`
+	UnitPrice   float32 `json:"unit_price"`
+}
+
+// ChangePromoUsersResponse defines model for ChangePromoUsersResponse.
+type ChangePromoUsersResponse struct {
+	Data struct {
+		// Changed How many users were added or removed
+		Changed int `json:"changed"`
+
+		// Requested How many distinct user IDs were sent
+		Requested int `json:"requested"`
+	} `json:"data"`
+	Message string `json:"message"`
+}
+
 // CreatePromoRequest defines model for CreatePromoRequest.
 type CreatePromoRequest struct {
-	BuyItemCount      *int                           `json:"buyItemCount,omitempty"`
-	BuyProductId      *int                           `json:"buyProductId,omitempty"`
-	Cities            *[]string                      `json:"cities,omitempty"`
-	Description       *string                        `json:"description,omitempty"`
-	DiscountValue     *float32                       `json:"discountValue,omitempty"`
-	EndDate           time.Time                      `json:"endDate"`
-	FreeItemCount     *int                           `json:"freeItemCount,omitempty"`
-	FreeProductId     *int                           `json:"freeProductId,omitempty"`
-	MaxDiscountAmount *float32                       `json:"maxDiscountAmount,omitempty"`
-	MaxUsageLimit     *int                           `json:"maxUsageLimit,omitempty"`
-	MinOrderAmount    *float32                       `json:"minOrderAmount,omitempty"`
-	Name              string                         `json:"name"`
-	Segmentation      CreatePromoRequestSegmentation `json:"segmentation"`
-	StartDate         time.Time                      `json:"startDate"`
-	Type              CreatePromoRequestType         `json:"type"`
+	BuyItemCount *int      `json:"buyItemCount,omitempty"`
+	BuyProductId *int      `json:"buyProductId,omitempty"`
+	Cities       *[]string `json:"cities,omitempty"`
+
+	// Code Voucher code customers can type to redeem the promo
+	Code *string `json:"code,omitempty"`
+
+	// CodeOnly When true the promo is not listed and can only be redeemed by its code
+	CodeOnly      *bool     `json:"codeOnly,omitempty"`
+	Description   *string   `json:"description,omitempty"`
+	DiscountValue *float32  `json:"discountValue,omitempty"`
+	EndDate       time.Time `json:"endDate"`
+
+	// Exclusive An exclusive promo can not be combined with any other promo in the same order
+	Exclusive     *bool `json:"exclusive,omitempty"`
+	FreeItemCount *int  `json:"freeItemCount,omitempty"`
+	FreeProductId *int  `json:"freeProductId,omitempty"`
+
+	// LapsedDays For LAPSED_USER, how many days the user must have gone without ordering, required for LAPSED_USER
+	LapsedDays *int `json:"lapsedDays,omitempty"`
+
+	// MaxBudgetAmount Total rupiah the promo may give away in discounts and free items, unlimited when empty. The order reaching it gets only what is left.
+	MaxBudgetAmount   *float32 `json:"maxBudgetAmount,omitempty"`
+	MaxDiscountAmount *float32 `json:"maxDiscountAmount,omitempty"`
+
+	// MaxRepeat Maximum number of times a repeatable reward is granted per order
+	MaxRepeat     *int `json:"maxRepeat,omitempty"`
+	MaxUsageLimit *int `json:"maxUsageLimit,omitempty"`
+
+	// MaxUsagePerUser Maximum number of times a single user can redeem the promo
+	MaxUsagePerUser *int     `json:"maxUsagePerUser,omitempty"`
+	MinOrderAmount  *float32 `json:"minOrderAmount,omitempty"`
+	Name            string   `json:"name"`
+
+	// NewUserDays For NEW_USER, how many days after registering a user counts as new, one month when empty
+	NewUserDays *int `json:"newUserDays,omitempty"`
+
+	// OrderNumber For NTH_ORDER, which of the user's orders the promo is for counting from 1, required for NTH_ORDER
+	OrderNumber *int `json:"orderNumber,omitempty"`
+
+	// Priority Promos with a higher priority are applied first
+	Priority *int `json:"priority,omitempty"`
+
+	// ProductIds For PERCENTAGE_DISCOUNT, take the discount and check minOrderAmount on these products only instead of the whole cart
+	ProductIds *[]int `json:"productIds,omitempty"`
+
+	// Repeatable For BUY_X_GET_Y_FREE, grant the free items again for every multiple of buyItemCount in the cart
+	Repeatable *bool `json:"repeatable,omitempty"`
+
+	// RolloutPercentage Percentage of users the promo is rolled out to, picked by a stable hash of the user and the promo, every user when empty
+	RolloutPercentage *int `json:"rolloutPercentage,omitempty"`
+
+	// Schedules Recurring windows the promo can be redeemed in, the promo is always available when empty
+	Schedules    *[]PromoSchedule               `json:"schedules,omitempty"`
+	Segmentation CreatePromoRequestSegmentation `json:"segmentation"`
+
+	// StackGroup Only one promo of the same stack group can be applied to an order
+	StackGroup *string   `json:"stackGroup,omitempty"`
+	StartDate  time.Time `json:"startDate"`
+
+	// Tiers For TIERED_DISCOUNT, the spend levels of the discount, the highest one the cart subtotal reaches is applied
+	Tiers *[]PromoTier `json:"tiers,omitempty"`
+
+	// Timezone IANA timezone the schedules are read in, defaults to Asia/Jakarta
+	Timezone *string                `json:"timezone,omitempty"`
+	Type     CreatePromoRequestType `json:"type"`
+
+	// UserIds For USER_LIST, the users who can see and redeem the promo, more can be added later
+	UserIds *[]int `json:"userIds,omitempty"`
+
+	// Variants For PERCENTAGE_DISCOUNT and FIXED_AMOUNT_DISCOUNT, the A/B variants of the discount, each user is assigned one by a stable hash of the user and the promo
+	Variants *[]PromoVariant `json:"variants,omitempty"`
 }
 
 // CreatePromoRequestSegmentation defines model for CreatePromoRequest.Segmentation.
@@ -87,6 +231,19 @@ type CreatePromoResponse struct {
 	Message string `json:"message"`
 }
 
+// EligibilityCheck defines model for EligibilityCheck.
+type EligibilityCheck struct {
+	Actual string `json:"actual"`
+
+	// Message Why the check failed, empty when it passed
+	Message *string `json:"message,omitempty"`
+
+	// Name An availability check (STATUS, DATE_WINDOW, USAGE_LIMIT, BUDGET, USER_USAGE_LIMIT), SCHEDULE, ROLLOUT, a type check (MIN_ORDER_AMOUNT, BUY_QUANTITY, SPEND_TIER) or the promo segmentation
+	Name     string `json:"name"`
+	Passed   bool   `json:"passed"`
+	Required string `json:"required"`
+}
+
 // ErrorResponse defines model for ErrorResponse.
 type ErrorResponse struct {
 	Data    *map[string]interface{} `json:"data,omitempty"`
@@ -99,6 +256,35 @@ type ExtendPromoRequest struct {
 	StartDate time.Time `json:"startDate"`
 }
 
+// GenerateVoucherRequest defines model for GenerateVoucherRequest.
+type GenerateVoucherRequest struct {
+	Count int `json:"count"`
+
+	// Prefix Prepended to every generated code
+	Prefix *string `json:"prefix,omitempty"`
+}
+
+// GenerateVoucherResponse defines model for GenerateVoucherResponse.
+type GenerateVoucherResponse struct {
+	Data struct {
+		Count   *int `json:"count,omitempty"`
+		PromoId *int `json:"promoId,omitempty"`
+	} `json:"data"`
+	Message string `json:"message"`
+}
+
+// GetCartResponse defines model for GetCartResponse.
+type GetCartResponse struct {
+	Data    Cart   `json:"data"`
+	Message string `json:"message"`
+}
+
+// GetPromoDetailResponse defines model for GetPromoDetailResponse.
+type GetPromoDetailResponse struct {
+	Data    Promo  `json:"data"`
+	Message string `json:"message"`
+}
+
 // GetPromoResponse defines model for GetPromoResponse.
 type GetPromoResponse struct {
 	Data    *[]Promo `json:"data,omitempty"`
@@ -114,38 +300,276 @@ type Meta struct {
 	Total   int `json:"total"`
 }
 
+// OrderQuote defines model for OrderQuote.
+type OrderQuote struct {
+	DiscountTotal float32              `json:"discount_total"`
+	FreeItems     []OrderQuoteFreeItem `json:"free_items"`
+	Promos        []OrderQuotePromo    `json:"promos"`
+	Subtotal      float32              `json:"subtotal"`
+	Total         float32              `json:"total"`
+}
+
+// OrderQuoteFreeItem defines model for OrderQuoteFreeItem.
+type OrderQuoteFreeItem struct {
+	ProductId int `json:"product_id"`
+	PromoId   int `json:"promo_id"`
+	Quantity  int `json:"quantity"`
+}
+
+// OrderQuotePromo defines model for OrderQuotePromo.
+type OrderQuotePromo struct {
+	DiscountAmount float32 `json:"discount_amount"`
+	FreeProductId  *int    `json:"free_product_id"`
+	FreeProductQty int     `json:"free_product_qty"`
+	Name           string  `json:"name"`
+	PromoId        int     `json:"promo_id"`
+
+	// TierId Tier a TIERED_DISCOUNT promo was applied with
+	TierId *int   `json:"tier_id"`
+	Type   string `json:"type"`
+
+	// VariantId Variant of the promo the user is assigned to, the same one the order records
+	VariantId *int `json:"variant_id"`
+}
+
+// OrderQuoteResponse defines model for OrderQuoteResponse.
+type OrderQuoteResponse struct {
+	Data    OrderQuote `json:"data"`
+	Message string     `json:"message"`
+}
+
 // PostOrderRequest defines model for PostOrderRequest.
 type PostOrderRequest struct {
-	PromoIds *[]int `json:"promoIds,omitempty"`
-	UserId   int    `json:"userId"`
+	PromoCodes *[]string `json:"promoCodes,omitempty"`
+	PromoIds   *[]int    `json:"promoIds,omitempty"`
+	UserId     int       `json:"userId"`
 }
 
 // Promo defines model for Promo.
 type Promo struct {
-	BuyItemCount      *int              `json:"buyItemCount,omitempty"`
-	BuyProductId      *int              `json:"buyProductId,omitempty"`
-	Cities            []string          `json:"cities"`
-	Description       string            `json:"description"`
-	DiscountValue     *float32          `json:"discountValue,omitempty"`
-	EndDate           time.Time         `json:"endDate"`
-	FreeItemCount     *int              `json:"freeItemCount,omitempty"`
-	FreeProductId     *int              `json:"freeProductId,omitempty"`
-	Id                int               `json:"id"`
-	MaxDiscountAmount *float32          `json:"maxDiscountAmount,omitempty"`
-	MaxUsageLimit     int               `json:"maxUsageLimit"`
-	MinOrderAmount    *float32          `json:"minOrderAmount,omitempty"`
-	Name              string            `json:"name"`
-	Segmentation      PromoSegmentation `json:"segmentation"`
-	StartDate         time.Time         `json:"startDate"`
-	Type              PromoType         `json:"type"`
+	BuyItemCount *int     `json:"buyItemCount,omitempty"`
+	BuyProductId *int     `json:"buyProductId,omitempty"`
+	Cities       []string `json:"cities"`
+
+	// Code Voucher code customers can type to redeem the promo
+	Code *string `json:"code,omitempty"`
+
+	// CodeOnly When true the promo is not listed and can only be redeemed by its code
+	CodeOnly      *bool     `json:"codeOnly,omitempty"`
+	Description   string    `json:"description"`
+	DiscountValue *float32  `json:"discountValue,omitempty"`
+	EndDate       time.Time `json:"endDate"`
+
+	// Exclusive An exclusive promo can not be combined with any other promo in the same order
+	Exclusive     *bool `json:"exclusive,omitempty"`
+	FreeItemCount *int  `json:"freeItemCount,omitempty"`
+	FreeProductId *int  `json:"freeProductId,omitempty"`
+	Id            int   `json:"id"`
+
+	// LapsedDays For LAPSED_USER, how many days the user must have gone without ordering, required for LAPSED_USER
+	LapsedDays *int `json:"lapsedDays,omitempty"`
+
+	// MaxBudgetAmount Total rupiah the promo may give away in discounts and free items, unlimited when empty
+	MaxBudgetAmount   *float32 `json:"maxBudgetAmount,omitempty"`
+	MaxDiscountAmount *float32 `json:"maxDiscountAmount,omitempty"`
+
+	// MaxRepeat Maximum number of times a repeatable reward is granted per order
+	MaxRepeat     *int `json:"maxRepeat,omitempty"`
+	MaxUsageLimit int  `json:"maxUsageLimit"`
+
+	// MaxUsagePerUser Maximum number of times a single user can redeem the promo
+	MaxUsagePerUser *int     `json:"maxUsagePerUser,omitempty"`
+	MinOrderAmount  *float32 `json:"minOrderAmount,omitempty"`
+	Name            string   `json:"name"`
+
+	// NewUserDays For NEW_USER, how many days after registering a user counts as new, one month when empty
+	NewUserDays *int `json:"newUserDays,omitempty"`
+
+	// OrderNumber For NTH_ORDER, which of the user's orders the promo is for counting from 1, required for NTH_ORDER
+	OrderNumber *int `json:"orderNumber,omitempty"`
+
+	// Priority Promos with a higher priority are applied first
+	Priority *int `json:"priority,omitempty"`
+
+	// ProductIds For PERCENTAGE_DISCOUNT, take the discount and check minOrderAmount on these products only instead of the whole cart
+	ProductIds *[]int `json:"productIds,omitempty"`
+
+	// RejectionReason Why the promo was rejected
+	RejectionReason *string `json:"rejectionReason"`
+
+	// Repeatable For BUY_X_GET_Y_FREE, grant the free items again for every multiple of buyItemCount in the cart
+	Repeatable *bool `json:"repeatable,omitempty"`
+
+	// ReviewedAt When the promo was approved or rejected
+	ReviewedAt *time.Time `json:"reviewedAt"`
+
+	// ReviewedBy Who approved or rejected the promo
+	ReviewedBy *string `json:"reviewedBy"`
+
+	// RolloutPercentage Percentage of users the promo is rolled out to, picked by a stable hash of the user and the promo, every user when empty
+	RolloutPercentage *int `json:"rolloutPercentage,omitempty"`
+
+	// Schedules Recurring windows the promo can be redeemed in, the promo is always available when empty
+	Schedules    *[]PromoSchedule  `json:"schedules,omitempty"`
+	Segmentation PromoSegmentation `json:"segmentation"`
+
+	// SpentAmount Rupiah the promo has given away so far
+	SpentAmount *float32 `json:"spentAmount,omitempty"`
+
+	// StackGroup Only one promo of the same stack group can be applied to an order
+	StackGroup *string   `json:"stackGroup,omitempty"`
+	StartDate  time.Time `json:"startDate"`
+
+	// Status A promo starts as a DRAFT, only APPROVED promos can be redeemed
+	Status *PromoStatus `json:"status,omitempty"`
+
+	// Tiers For TIERED_DISCOUNT, the spend levels of the discount, the highest one the cart subtotal reaches is applied
+	Tiers *[]PromoTier `json:"tiers,omitempty"`
+
+	// Timezone IANA timezone the schedules are read in, defaults to Asia/Jakarta
+	Timezone *string   `json:"timezone,omitempty"`
+	Type     PromoType `json:"type"`
+
+	// Variants For PERCENTAGE_DISCOUNT and FIXED_AMOUNT_DISCOUNT, the A/B variants of the discount, each user is assigned one by a stable hash of the user and the promo
+	Variants *[]PromoVariant `json:"variants,omitempty"`
 }
 
 // PromoSegmentation defines model for Promo.Segmentation.
 type PromoSegmentation string
 
+// PromoStatus A promo starts as a DRAFT, only APPROVED promos can be redeemed
+type PromoStatus string
+
 // PromoType defines model for Promo.Type.
 type PromoType string
 
+// PromoAuditLog defines model for PromoAuditLog.
+type PromoAuditLog struct {
+	Action *PromoAuditLogAction `json:"action,omitempty"`
+
+	// Actor Who made the change, empty when the request did not say
+	Actor *string `json:"actor"`
+
+	// Changes Every changed field of the promo, or its cities or listed users, with its value before and after
+	Changes *map[string]struct {
+		After  *interface{} `json:"after,omitempty"`
+		Before *interface{} `json:"before,omitempty"`
+	} `json:"changes,omitempty"`
+
+	// CreatedAt When the change was made
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+	Id        *int       `json:"id,omitempty"`
+	PromoId   *int       `json:"promoId,omitempty"`
+}
+
+// PromoAuditLogAction defines model for PromoAuditLog.Action.
+type PromoAuditLogAction string
+
+// PromoCitiesResponse defines model for PromoCitiesResponse.
+type PromoCitiesResponse struct {
+	Data    []string `json:"data"`
+	Message string   `json:"message"`
+}
+
+// PromoEligibility defines model for PromoEligibility.
+type PromoEligibility struct {
+	Checks []EligibilityCheck `json:"checks"`
+
+	// Eligible True when every check passed
+	Eligible bool `json:"eligible"`
+	PromoId  int  `json:"promo_id"`
+}
+
+// PromoEligibilityResponse defines model for PromoEligibilityResponse.
+type PromoEligibilityResponse struct {
+	Data    PromoEligibility `json:"data"`
+	Message string           `json:"message"`
+}
+
+// PromoHistoryResponse defines model for PromoHistoryResponse.
+type PromoHistoryResponse struct {
+	Data    []PromoAuditLog `json:"data"`
+	Message string          `json:"message"`
+	Meta    *Meta           `json:"meta,omitempty"`
+}
+
+// PromoRecommendation defines model for PromoRecommendation.
+type PromoRecommendation struct {
+	// FreeItemsValue Value of the free products at their current price
+	FreeItemsValue float32    `json:"free_items_value"`
+	PromoIds       []int      `json:"promo_ids"`
+	Quote          OrderQuote `json:"quote"`
+
+	// Saving Discount total plus the value of the free products
+	Saving float32 `json:"saving"`
+}
+
+// PromoRecommendationResponse defines model for PromoRecommendationResponse.
+type PromoRecommendationResponse struct {
+	Data    PromoRecommendation `json:"data"`
+	Message string              `json:"message"`
+}
+
+// PromoSchedule defines model for PromoSchedule.
+type PromoSchedule struct {
+	// Days Days of the week the window repeats on, 0 is Sunday
+	Days []int `json:"days"`
+
+	// EndTime End of the window as HH:MM, a window not ending after it starts runs past midnight, 00:00 to 00:00 is the whole day
+	EndTime string `json:"endTime"`
+
+	// StartTime Start of the window as HH:MM
+	StartTime string `json:"startTime"`
+}
+
+// PromoTier defines model for PromoTier.
+type PromoTier struct {
+	// DiscountType PERCENTAGE or FIXED
+	DiscountType  string  `json:"discountType"`
+	DiscountValue float32 `json:"discountValue"`
+
+	// MaxDiscountAmount Cap of a PERCENTAGE tier
+	MaxDiscountAmount *float32 `json:"maxDiscountAmount,omitempty"`
+
+	// MinAmount Cart subtotal the tier starts at
+	MinAmount float32 `json:"minAmount"`
+}
+
+// PromoUsersRequest defines model for PromoUsersRequest.
+type PromoUsersRequest struct {
+	UserIds []int `json:"userIds"`
+}
+
+// PromoUsersResponse defines model for PromoUsersResponse.
+type PromoUsersResponse struct {
+	// Data IDs of the listed users
+	Data    []int  `json:"data"`
+	Message string `json:"message"`
+	Meta    *Meta  `json:"meta,omitempty"`
+}
+
+// PromoVariant defines model for PromoVariant.
+type PromoVariant struct {
+	// DiscountValue Replaces the discount value of the promo for the users assigned to the variant
+	DiscountValue float32 `json:"discountValue"`
+	Id            *int    `json:"id,omitempty"`
+
+	// MaxDiscountAmount For PERCENTAGE_DISCOUNT, replaces the max discount amount of the promo for the users assigned to the variant
+	MaxDiscountAmount *float32 `json:"maxDiscountAmount,omitempty"`
+
+	// Name Unique within the promo
+	Name string `json:"name"`
+
+	// Weight Share of the rolled out users assigned to the variant relative to the other variants, defaults to 1
+	Weight *int `json:"weight,omitempty"`
+}
+
+// RejectPromoRequest defines model for RejectPromoRequest.
+type RejectPromoRequest struct {
+	// Reason Why the promo was rejected
+	Reason *string `json:"reason,omitempty"`
+}
+
 // RemoveFromCartRequest defines model for RemoveFromCartRequest.
 type RemoveFromCartRequest struct {
 	ProductId int `json:"productId"`
@@ -158,6 +582,50 @@ type SuccessResponse struct {
 	Message string                  `json:"message"`
 }
 
+// UpdateCartItemRequest defines model for UpdateCartItemRequest.
+type UpdateCartItemRequest struct {
+	// Quantity Zero removes the item from the cart
+	Quantity *int `json:"quantity"`
+	UserId   int  `json:"userId"`
+}
+
+// UpdatePromoRequest Only the given fields are changed. Type, segmentation, products, code and dates are fixed after creation, dates are changed with the extend endpoint.
+type UpdatePromoRequest struct {
+	Description *string `json:"description,omitempty"`
+
+	// DiscountValue Only for PERCENTAGE_DISCOUNT and FIXED_AMOUNT_DISCOUNT promos
+	DiscountValue *float32 `json:"discountValue,omitempty"`
+	Exclusive     *bool    `json:"exclusive,omitempty"`
+
+	// MaxBudgetAmount Can not be lower than the amount already spent
+	MaxBudgetAmount *float32 `json:"maxBudgetAmount,omitempty"`
+
+	// MaxDiscountAmount Only for PERCENTAGE_DISCOUNT promos
+	MaxDiscountAmount *float32 `json:"maxDiscountAmount,omitempty"`
+
+	// MaxUsageLimit Can not be lower than the current usage count
+	MaxUsageLimit   *int `json:"maxUsageLimit,omitempty"`
+	MaxUsagePerUser *int `json:"maxUsagePerUser,omitempty"`
+
+	// MinOrderAmount Only for PERCENTAGE_DISCOUNT and FIXED_AMOUNT_DISCOUNT promos
+	MinOrderAmount *float32 `json:"minOrderAmount,omitempty"`
+	Name           *string  `json:"name,omitempty"`
+	Priority       *int     `json:"priority,omitempty"`
+
+	// RolloutPercentage Raising it keeps the promo for every user who already had it
+	RolloutPercentage *int    `json:"rolloutPercentage,omitempty"`
+	StackGroup        *string `json:"stackGroup,omitempty"`
+}
+
+// Actor defines model for Actor.
+type Actor = string
+
+// GetCartParams defines parameters for GetCart.
+type GetCartParams struct {
+	// UserId User ID
+	UserId int `form:"userId" json:"userId"`
+}
+
 // GetGetPromoParams defines parameters for GetGetPromo.
 type GetGetPromoParams struct {
 	// UserId User ID
@@ -170,18 +638,128 @@ type GetGetPromoParams struct {
 	PerPage *int `form:"perPage,omitempty" json:"perPage,omitempty"`
 }
 
+// GetGetPromoRecommendationParams defines parameters for GetGetPromoRecommendation.
+type GetGetPromoRecommendationParams struct {
+	// UserId User ID
+	UserId int `form:"userId" json:"userId"`
+}
+
+// PostPromoIdApproveParams defines parameters for PostPromoIdApprove.
+type PostPromoIdApproveParams struct {
+	// XActor Who makes the change, e.g. the admin's email
+	XActor Actor `json:"X-Actor"`
+}
+
+// GetPromoIdEligibilityParams defines parameters for GetPromoIdEligibility.
+type GetPromoIdEligibilityParams struct {
+	// UserId User ID
+	UserId int `form:"userId" json:"userId"`
+}
+
+// GetPromoIdHistoryParams defines parameters for GetPromoIdHistory.
+type GetPromoIdHistoryParams struct {
+	// Page Page number
+	Page *int `form:"page,omitempty" json:"page,omitempty"`
+
+	// PerPage Number of items per page
+	PerPage *int `form:"perPage,omitempty" json:"perPage,omitempty"`
+}
+
+// PostPromoIdRejectParams defines parameters for PostPromoIdReject.
+type PostPromoIdRejectParams struct {
+	// XActor Who makes the change, e.g. the admin's email
+	XActor Actor `json:"X-Actor"`
+}
+
+// GetPromoIdUsersParams defines parameters for GetPromoIdUsers.
+type GetPromoIdUsersParams struct {
+	// Page Page number
+	Page *int `form:"page,omitempty" json:"page,omitempty"`
+
+	// PerPage Number of items per page
+	PerPage *int `form:"perPage,omitempty" json:"perPage,omitempty"`
+}
+
+// PostPromoIdUsersCsvMultipartBody defines parameters for PostPromoIdUsersCsv.
+type PostPromoIdUsersCsvMultipartBody struct {
+	File openapi_types.File `json:"file"`
+}
+
+// PostPromoIdUsersCsvParams defines parameters for PostPromoIdUsersCsv.
+type PostPromoIdUsersCsvParams struct {
+	// Action Whether the users in the file are added or removed
+	Action PostPromoIdUsersCsvParamsAction `form:"action" json:"action"`
+}
+
+// PostPromoIdUsersCsvParamsAction defines parameters for PostPromoIdUsersCsv.
+type PostPromoIdUsersCsvParamsAction string
+
+// GetPromosParams defines parameters for GetPromos.
+type GetPromosParams struct {
+	// Status Promo status, archived promos are only listed when asked for
+	Status *GetPromosParamsStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// Type Promo type
+	Type *string `form:"type,omitempty" json:"type,omitempty"`
+
+	// Segmentation Promo segmentation
+	Segmentation *string `form:"segmentation,omitempty" json:"segmentation,omitempty"`
+
+	// StartDate Only promos still running at or after this time
+	StartDate *time.Time `form:"startDate,omitempty" json:"startDate,omitempty"`
+
+	// EndDate Only promos starting at or before this time
+	EndDate *time.Time `form:"endDate,omitempty" json:"endDate,omitempty"`
+
+	// Page Page number
+	Page *int `form:"page,omitempty" json:"page,omitempty"`
+
+	// PerPage Number of items per page
+	PerPage *int `form:"perPage,omitempty" json:"perPage,omitempty"`
+}
+
+// GetPromosParamsStatus defines parameters for GetPromos.
+type GetPromosParamsStatus string
+
 // PostAddCartJSONRequestBody defines body for PostAddCart for application/json ContentType.
 type PostAddCartJSONRequestBody = AddCartRequest
 
+// PutCartItemsProductIdJSONRequestBody defines body for PutCartItemsProductId for application/json ContentType.
+type PutCartItemsProductIdJSONRequestBody = UpdateCartItemRequest
+
 // PostOrderJSONRequestBody defines body for PostOrder for application/json ContentType.
 type PostOrderJSONRequestBody = PostOrderRequest
 
+// PostOrderQuoteJSONRequestBody defines body for PostOrderQuote for application/json ContentType.
+type PostOrderQuoteJSONRequestBody = PostOrderRequest
+
 // PostPromoJSONRequestBody defines body for PostPromo for application/json ContentType.
 type PostPromoJSONRequestBody = CreatePromoRequest
 
+// PatchPromoIdJSONRequestBody defines body for PatchPromoId for application/json ContentType.
+type PatchPromoIdJSONRequestBody = UpdatePromoRequest
+
+// PostPromoIdCitiesJSONRequestBody defines body for PostPromoIdCities for application/json ContentType.
+type PostPromoIdCitiesJSONRequestBody = AddPromoCitiesRequest
+
 // PostPromoIdExtendJSONRequestBody defines body for PostPromoIdExtend for application/json ContentType.
 type PostPromoIdExtendJSONRequestBody = ExtendPromoRequest
 
+// PostPromoIdRejectJSONRequestBody defines body for PostPromoIdReject for application/json ContentType.
+type PostPromoIdRejectJSONRequestBody = RejectPromoRequest
+
+// PostPromoIdUsersJSONRequestBody defines body for PostPromoIdUsers for application/json ContentType.
+type PostPromoIdUsersJSONRequestBody = PromoUsersRequest
+
+// PostPromoIdUsersCsvMultipartRequestBody defines body for PostPromoIdUsersCsv for multipart/form-data ContentType.
+type PostPromoIdUsersCsvMultipartRequestBody PostPromoIdUsersCsvMultipartBody
+
+// PostPromoIdUsersRemoveJSONRequestBody defines body for PostPromoIdUsersRemove for application/json ContentType.
+type PostPromoIdUsersRemoveJSONRequestBody = PromoUsersRequest
+
+// PostPromoIdVouchersJSONRequestBody defines body for PostPromoIdVouchers for application/json ContentType.
+type PostPromoIdVouchersJSONRequestBody = GenerateVoucherRequest
+
 // PostRemoveFromCartJSONRequestBody defines body for PostRemoveFromCart for application/json ContentType.
 type PostRemoveFromCartJSONRequestBody = RemoveFromCartRequest
 
@@ -190,21 +768,96 @@ type ServerInterface interface {
 	// Add a product to the cart
 	// (POST /add-cart)
 	PostAddCart(ctx echo.Context) error
+	// Get the user's cart with priced line items
+	// (GET /cart)
+	GetCart(ctx echo.Context, params GetCartParams) error
+	// Set the quantity of a product in the cart
+	// (PUT /cart/items/{productId})
+	PutCartItemsProductId(ctx echo.Context, productId int) error
 	// Get promos
 	// (GET /get-promo)
 	GetGetPromo(ctx echo.Context, params GetGetPromoParams) error
+	// Recommend the promo combination that saves the most on the user's cart
+	// (GET /get-promo/recommendation)
+	GetGetPromoRecommendation(ctx echo.Context, params GetGetPromoRecommendationParams) error
 	// Check the health of the service
 	// (GET /health)
 	GetHealth(ctx echo.Context) error
 	// Place an order
 	// (POST /order)
 	PostOrder(ctx echo.Context) error
+	// Price the cart with the selected promos without placing an order
+	// (POST /order/quote)
+	PostOrderQuote(ctx echo.Context) error
 	// Create a promo
 	// (POST /promo)
 	PostPromo(ctx echo.Context) error
+	// Get a promo with its cities, schedules, products and tiers
+	// (GET /promo/{id})
+	GetPromoId(ctx echo.Context, id int) error
+	// Update the editable fields of a promo
+	// (PATCH /promo/{id})
+	PatchPromoId(ctx echo.Context, id int) error
+	// Approve a promo waiting for approval, it can be redeemed from then on
+	// (POST /promo/{id}/approve)
+	PostPromoIdApprove(ctx echo.Context, id int, params PostPromoIdApproveParams) error
+	// Archive the promo, it is kept for the order history but can not be used or changed again
+	// (POST /promo/{id}/archive)
+	PostPromoIdArchive(ctx echo.Context, id int) error
+	// List the cities of a CITY promo
+	// (GET /promo/{id}/cities)
+	GetPromoIdCities(ctx echo.Context, id int) error
+	// Add cities to a CITY promo, cities it already has are skipped
+	// (POST /promo/{id}/cities)
+	PostPromoIdCities(ctx echo.Context, id int) error
+	// Remove a city from a CITY promo, the last city can not be removed
+	// (DELETE /promo/{id}/cities/{city})
+	DeletePromoIdCitiesCity(ctx echo.Context, id int, city string) error
+	// Explain which eligibility checks of the promo the user passes or fails
+	// (GET /promo/{id}/eligibility)
+	GetPromoIdEligibility(ctx echo.Context, id int, params GetPromoIdEligibilityParams) error
+	// End an approved or paused promo before its end date, it can not be used or changed again
+	// (POST /promo/{id}/end)
+	PostPromoIdEnd(ctx echo.Context, id int) error
 	// Extend the promo
 	// (POST /promo/{id}/extend)
 	PostPromoIdExtend(ctx echo.Context, id int) error
+	// List the changes made to the promo, newest first
+	// (GET /promo/{id}/history)
+	GetPromoIdHistory(ctx echo.Context, id int, params GetPromoIdHistoryParams) error
+	// Pause the promo, it stops being eligible until resumed
+	// (POST /promo/{id}/pause)
+	PostPromoIdPause(ctx echo.Context, id int) error
+	// Reject a promo waiting for approval, it can be changed and submitted again
+	// (POST /promo/{id}/reject)
+	PostPromoIdReject(ctx echo.Context, id int, params PostPromoIdRejectParams) error
+	// Resume a paused promo
+	// (POST /promo/{id}/resume)
+	PostPromoIdResume(ctx echo.Context, id int) error
+	// Submit a draft or rejected promo for approval
+	// (POST /promo/{id}/submit)
+	PostPromoIdSubmit(ctx echo.Context, id int) error
+	// List the users of a USER_LIST promo
+	// (GET /promo/{id}/users)
+	GetPromoIdUsers(ctx echo.Context, id int, params GetPromoIdUsersParams) error
+	// Add users to a USER_LIST promo, unknown users and users already listed are skipped
+	// (POST /promo/{id}/users)
+	PostPromoIdUsers(ctx echo.Context, id int) error
+	// Add or remove the users of a USER_LIST promo from a CSV file with one user ID per line, a header line is skipped
+	// (POST /promo/{id}/users/csv)
+	PostPromoIdUsersCsv(ctx echo.Context, id int, params PostPromoIdUsersCsvParams) error
+	// Remove users from a USER_LIST promo
+	// (POST /promo/{id}/users/remove)
+	PostPromoIdUsersRemove(ctx echo.Context, id int) error
+	// Generate single-use voucher codes for the promo
+	// (POST /promo/{id}/vouchers)
+	PostPromoIdVouchers(ctx echo.Context, id int) error
+	// Export the promo's voucher codes as CSV
+	// (GET /promo/{id}/vouchers/export)
+	GetPromoIdVouchersExport(ctx echo.Context, id int) error
+	// List promos for the admin
+	// (GET /promos)
+	GetPromos(ctx echo.Context, params GetPromosParams) error
 	// Remove a product from the cart
 	// (POST /remove-from-cart)
 	PostRemoveFromCart(ctx echo.Context) error
@@ -224,6 +877,40 @@ func (w *ServerInterfaceWrapper) PostAddCart(ctx echo.Context) error {
 	return err
 }
 
+// GetCart converts echo context to params.
+func (w *ServerInterfaceWrapper) GetCart(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetCartParams
+	// ------------- Required query parameter "userId" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "userId", ctx.QueryParams(), &params.UserId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter userId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetCart(ctx, params)
+	return err
+}
+
+// PutCartItemsProductId converts echo context to params.
+func (w *ServerInterfaceWrapper) PutCartItemsProductId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "productId" -------------
+	var productId int
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "productId", runtime.ParamLocationPath, ctx.Param("productId"), &productId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter productId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PutCartItemsProductId(ctx, productId)
+	return err
+}
+
 // GetGetPromo converts echo context to params.
 func (w *ServerInterfaceWrapper) GetGetPromo(ctx echo.Context) error {
 	var err error
@@ -256,6 +943,24 @@ func (w *ServerInterfaceWrapper) GetGetPromo(ctx echo.Context) error {
 	return err
 }
 
+// GetGetPromoRecommendation converts echo context to params.
+func (w *ServerInterfaceWrapper) GetGetPromoRecommendation(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetGetPromoRecommendationParams
+	// ------------- Required query parameter "userId" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "userId", ctx.QueryParams(), &params.UserId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter userId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetGetPromoRecommendation(ctx, params)
+	return err
+}
+
 // GetHealth converts echo context to params.
 func (w *ServerInterfaceWrapper) GetHealth(ctx echo.Context) error {
 	var err error
@@ -274,6 +979,15 @@ func (w *ServerInterfaceWrapper) PostOrder(ctx echo.Context) error {
 	return err
 }
 
+// PostOrderQuote converts echo context to params.
+func (w *ServerInterfaceWrapper) PostOrderQuote(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostOrderQuote(ctx)
+	return err
+}
+
 // PostPromo converts echo context to params.
 func (w *ServerInterfaceWrapper) PostPromo(ctx echo.Context) error {
 	var err error
@@ -283,8 +997,8 @@ func (w *ServerInterfaceWrapper) PostPromo(ctx echo.Context) error {
 	return err
 }
 
-// PostPromoIdExtend converts echo context to params.
-func (w *ServerInterfaceWrapper) PostPromoIdExtend(ctx echo.Context) error {
+// GetPromoId converts echo context to params.
+func (w *ServerInterfaceWrapper) GetPromoId(ctx echo.Context) error {
 	var err error
 	// ------------- Path parameter "id" -------------
 	var id int
@@ -295,7 +1009,489 @@ func (w *ServerInterfaceWrapper) PostPromoIdExtend(ctx echo.Context) error {
 	}
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.PostPromoIdExtend(ctx, id)
+	err = w.Handler.GetPromoId(ctx, id)
+	return err
+}
+
+// PatchPromoId converts echo context to params.
+func (w *ServerInterfaceWrapper) PatchPromoId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PatchPromoId(ctx, id)
+	return err
+}
+
+// PostPromoIdApprove converts echo context to params.
+func (w *ServerInterfaceWrapper) PostPromoIdApprove(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostPromoIdApproveParams
+
+	headers := ctx.Request().Header
+	// ------------- Required header parameter "X-Actor" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Actor")]; found {
+		var XActor Actor
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for X-Actor, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "X-Actor", runtime.ParamLocationHeader, valueList[0], &XActor)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter X-Actor: %s", err))
+		}
+
+		params.XActor = XActor
+	} else {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Header parameter X-Actor is required, but not found"))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostPromoIdApprove(ctx, id, params)
+	return err
+}
+
+// PostPromoIdArchive converts echo context to params.
+func (w *ServerInterfaceWrapper) PostPromoIdArchive(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostPromoIdArchive(ctx, id)
+	return err
+}
+
+// GetPromoIdCities converts echo context to params.
+func (w *ServerInterfaceWrapper) GetPromoIdCities(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetPromoIdCities(ctx, id)
+	return err
+}
+
+// PostPromoIdCities converts echo context to params.
+func (w *ServerInterfaceWrapper) PostPromoIdCities(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostPromoIdCities(ctx, id)
+	return err
+}
+
+// DeletePromoIdCitiesCity converts echo context to params.
+func (w *ServerInterfaceWrapper) DeletePromoIdCitiesCity(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "city" -------------
+	var city string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "city", runtime.ParamLocationPath, ctx.Param("city"), &city)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter city: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeletePromoIdCitiesCity(ctx, id, city)
+	return err
+}
+
+// GetPromoIdEligibility converts echo context to params.
+func (w *ServerInterfaceWrapper) GetPromoIdEligibility(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetPromoIdEligibilityParams
+	// ------------- Required query parameter "userId" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "userId", ctx.QueryParams(), &params.UserId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter userId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetPromoIdEligibility(ctx, id, params)
+	return err
+}
+
+// PostPromoIdEnd converts echo context to params.
+func (w *ServerInterfaceWrapper) PostPromoIdEnd(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostPromoIdEnd(ctx, id)
+	return err
+}
+
+// PostPromoIdExtend converts echo context to params.
+func (w *ServerInterfaceWrapper) PostPromoIdExtend(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostPromoIdExtend(ctx, id)
+	return err
+}
+
+// GetPromoIdHistory converts echo context to params.
+func (w *ServerInterfaceWrapper) GetPromoIdHistory(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetPromoIdHistoryParams
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
+	}
+
+	// ------------- Optional query parameter "perPage" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "perPage", ctx.QueryParams(), &params.PerPage)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter perPage: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetPromoIdHistory(ctx, id, params)
+	return err
+}
+
+// PostPromoIdPause converts echo context to params.
+func (w *ServerInterfaceWrapper) PostPromoIdPause(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostPromoIdPause(ctx, id)
+	return err
+}
+
+// PostPromoIdReject converts echo context to params.
+func (w *ServerInterfaceWrapper) PostPromoIdReject(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostPromoIdRejectParams
+
+	headers := ctx.Request().Header
+	// ------------- Required header parameter "X-Actor" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Actor")]; found {
+		var XActor Actor
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for X-Actor, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "X-Actor", runtime.ParamLocationHeader, valueList[0], &XActor)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter X-Actor: %s", err))
+		}
+
+		params.XActor = XActor
+	} else {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Header parameter X-Actor is required, but not found"))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostPromoIdReject(ctx, id, params)
+	return err
+}
+
+// PostPromoIdResume converts echo context to params.
+func (w *ServerInterfaceWrapper) PostPromoIdResume(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostPromoIdResume(ctx, id)
+	return err
+}
+
+// PostPromoIdSubmit converts echo context to params.
+func (w *ServerInterfaceWrapper) PostPromoIdSubmit(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostPromoIdSubmit(ctx, id)
+	return err
+}
+
+// GetPromoIdUsers converts echo context to params.
+func (w *ServerInterfaceWrapper) GetPromoIdUsers(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetPromoIdUsersParams
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
+	}
+
+	// ------------- Optional query parameter "perPage" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "perPage", ctx.QueryParams(), &params.PerPage)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter perPage: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetPromoIdUsers(ctx, id, params)
+	return err
+}
+
+// PostPromoIdUsers converts echo context to params.
+func (w *ServerInterfaceWrapper) PostPromoIdUsers(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostPromoIdUsers(ctx, id)
+	return err
+}
+
+// PostPromoIdUsersCsv converts echo context to params.
+func (w *ServerInterfaceWrapper) PostPromoIdUsersCsv(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostPromoIdUsersCsvParams
+	// ------------- Required query parameter "action" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "action", ctx.QueryParams(), &params.Action)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter action: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostPromoIdUsersCsv(ctx, id, params)
+	return err
+}
+
+// PostPromoIdUsersRemove converts echo context to params.
+func (w *ServerInterfaceWrapper) PostPromoIdUsersRemove(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostPromoIdUsersRemove(ctx, id)
+	return err
+}
+
+// PostPromoIdVouchers converts echo context to params.
+func (w *ServerInterfaceWrapper) PostPromoIdVouchers(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostPromoIdVouchers(ctx, id)
+	return err
+}
+
+// GetPromoIdVouchersExport converts echo context to params.
+func (w *ServerInterfaceWrapper) GetPromoIdVouchersExport(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetPromoIdVouchersExport(ctx, id)
+	return err
+}
+
+// GetPromos converts echo context to params.
+func (w *ServerInterfaceWrapper) GetPromos(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetPromosParams
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", ctx.QueryParams(), &params.Status)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter status: %s", err))
+	}
+
+	// ------------- Optional query parameter "type" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "type", ctx.QueryParams(), &params.Type)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter type: %s", err))
+	}
+
+	// ------------- Optional query parameter "segmentation" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "segmentation", ctx.QueryParams(), &params.Segmentation)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter segmentation: %s", err))
+	}
+
+	// ------------- Optional query parameter "startDate" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "startDate", ctx.QueryParams(), &params.StartDate)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter startDate: %s", err))
+	}
+
+	// ------------- Optional query parameter "endDate" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "endDate", ctx.QueryParams(), &params.EndDate)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter endDate: %s", err))
+	}
+
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
+	}
+
+	// ------------- Optional query parameter "perPage" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "perPage", ctx.QueryParams(), &params.PerPage)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter perPage: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetPromos(ctx, params)
 	return err
 }
 
@@ -337,11 +1533,36 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 	}
 
 	router.POST(baseURL+"/add-cart", wrapper.PostAddCart)
+	router.GET(baseURL+"/cart", wrapper.GetCart)
+	router.PUT(baseURL+"/cart/items/:productId", wrapper.PutCartItemsProductId)
 	router.GET(baseURL+"/get-promo", wrapper.GetGetPromo)
+	router.GET(baseURL+"/get-promo/recommendation", wrapper.GetGetPromoRecommendation)
 	router.GET(baseURL+"/health", wrapper.GetHealth)
 	router.POST(baseURL+"/order", wrapper.PostOrder)
+	router.POST(baseURL+"/order/quote", wrapper.PostOrderQuote)
 	router.POST(baseURL+"/promo", wrapper.PostPromo)
+	router.GET(baseURL+"/promo/:id", wrapper.GetPromoId)
+	router.PATCH(baseURL+"/promo/:id", wrapper.PatchPromoId)
+	router.POST(baseURL+"/promo/:id/approve", wrapper.PostPromoIdApprove)
+	router.POST(baseURL+"/promo/:id/archive", wrapper.PostPromoIdArchive)
+	router.GET(baseURL+"/promo/:id/cities", wrapper.GetPromoIdCities)
+	router.POST(baseURL+"/promo/:id/cities", wrapper.PostPromoIdCities)
+	router.DELETE(baseURL+"/promo/:id/cities/:city", wrapper.DeletePromoIdCitiesCity)
+	router.GET(baseURL+"/promo/:id/eligibility", wrapper.GetPromoIdEligibility)
+	router.POST(baseURL+"/promo/:id/end", wrapper.PostPromoIdEnd)
 	router.POST(baseURL+"/promo/:id/extend", wrapper.PostPromoIdExtend)
+	router.GET(baseURL+"/promo/:id/history", wrapper.GetPromoIdHistory)
+	router.POST(baseURL+"/promo/:id/pause", wrapper.PostPromoIdPause)
+	router.POST(baseURL+"/promo/:id/reject", wrapper.PostPromoIdReject)
+	router.POST(baseURL+"/promo/:id/resume", wrapper.PostPromoIdResume)
+	router.POST(baseURL+"/promo/:id/submit", wrapper.PostPromoIdSubmit)
+	router.GET(baseURL+"/promo/:id/users", wrapper.GetPromoIdUsers)
+	router.POST(baseURL+"/promo/:id/users", wrapper.PostPromoIdUsers)
+	router.POST(baseURL+"/promo/:id/users/csv", wrapper.PostPromoIdUsersCsv)
+	router.POST(baseURL+"/promo/:id/users/remove", wrapper.PostPromoIdUsersRemove)
+	router.POST(baseURL+"/promo/:id/vouchers", wrapper.PostPromoIdVouchers)
+	router.GET(baseURL+"/promo/:id/vouchers/export", wrapper.GetPromoIdVouchersExport)
+	router.GET(baseURL+"/promos", wrapper.GetPromos)
 	router.POST(baseURL+"/remove-from-cart", wrapper.PostRemoveFromCart)
 
 }
@@ -349,30 +1570,116 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xZXU8bORf+K5bfd++GZgplpc4dDSkbidIIwu6yFYrM+EzidmwPtocSofz3le3Jx0xM",
-	"JtUCykWkvSCz9jnPOec5X+4TTiUvpABhNE6esE4nwIn784TSLlHmEu5L0MZ+KZQsQBkGuvpFy9T0qf0B",
-	"j4QXOeDkfYTNtACcYCYMjEHhWYTvSyIMM9P2k6UG1S5xFmEF9yVTQHHybX4pWoG0ovN2cV/efYfUWDVd",
-	"BcTAQEkun7Xvrpz2DfCuLIWpAToMAb8rp4PtHZKyuZbFuW/4An6iG6l+4AifS41OxBhy0BY/M8Dd6UqS",
-	"NoqJsRVUfSBKkan9TUGnihWGSVGTjg/j35DMMiQFInmOKk9plEmFzASQLjkHhTQQLcU7HK2rokyn1hd/",
-	"kryEukPiCGdScWJwgrNcErO8L0p+520GQU+JgSasw6OD+PeDo3h4eJQcf0yOP/6DV6RRYuDAMA4hRJkC",
-	"CIco6HR7PByjYEQ5eTytTD7haxqOt7KZk8drTcZwzjhrIIzjoFImvioKKqDR3WhXKQhv+PjKh/aK5EEv",
-	"ahhzEIYsOCNKbul4cn5umfj15uR8dH3Vu8QRvuj9Nf+z2x/eWGou1fjz6+INUeb5wMfvh3GcuP+2D7z/",
-	"sIQ66F12exfDk7Pe6LR/1f16fTHEEf50fTP6e3TWG45uRp8ve7063PCdhqpGoXG+bXisurNq6JLrrbVH",
-	"F1JoWC8+lBgSLLlcblUe17Ry0JaI9SA4ECh1gCjSZZqC1lmZ59NWV8zlRR5qyNCeUlK1m7gJ6nYYgsof",
-	"DQi6ucK/fEV6ebI3TN6WZGdgtmTYorf8X0GGE/y/znIi6FTjQMfJCvWb52mlUQYmnbTyysrwSDYB+GLP",
-	"NJ3h0kHjSkLIDV8glEZ5oBqHinHRtCzYVgpQo/WTQYFGGpK3t4GGmf5WBWdFX1QZEjJ8ILVxjWTT+GZr",
-	"ia5xIAC5EfD/MqAFgTpm7Yev/fBVO862iON+QNsPaI1Cw+zmV01pq9n4KzNbkxSLghEqX5fA5QN8VpK/",
-	"1K78UhtwCO2V78TtQ4Eo85zcWd1GlbDtOFmJ33p2XIdoTzKRSd+lU6hA+pTBX/pDRy5mnLprbZMG1ANL",
-	"bdQeQGmXIfj9u/hdbE/KAgQpGE7wkftke6iZOEs7hNKDlCgfLOmDZl3hGGID4Hpo9QiCvQGgzSdJ3TNG",
-	"KoUBXwBIUeQsdfc637XPUT+3tE01jSeWWd1R1vXug4+Wg30Yxy+mvckGp77Ww3BVsRGhFCgyEjmPzSL8",
-	"4QVx1PeEAIq+eCA5o0jN/WT1f3g7/VeSg5kwMUY/iUZCGpTJUlCL4/ht/WBACZIjDeoBFAJ7wWWXHSCI",
-	"mtoiTyki8wnDRszOFz5qswh3xmAOivnINYYA68/AzJcHly+KcDCgNE6+PTXwuATsn2KbszjB9yWo6bz+",
-	"JsuaVGd0tOKN9arWVDEgY0BV7wyrqUbhXxF64eQhmSE326ECFKrEBDWAGrQquX3FRF1b5sKZ+uzOtQP5",
-	"unN5cgYGVfujS4wJkNxMNmXFH/5EOM6NguGbEmIaeblT74OjjUdLsThcg9qdQPrD5bH//5a4bmuoWp/D",
-	"L+1QurmXubn1lTrZ2r65e73MwUNFTtJ9frTnx8D6CRGBPLEcxxaN43mOzfvGa3As8I82b8yy0NPtc7V4",
-	"/qy6Z1eAXd6RflDhcoVdnSdGZx1wj7dbMK1P/Ttv26TiI7IcVewasGzw7BenlNvX4XfgyXonNwIukQ/Q",
-	"bpD7TVcBb/5urwCeR25EWEkv5V4qDjIl+RZ7b/1d45UKevjxZHe3YO9CiqwPd2YT3jn6+aiuLKHOXStr",
-	"qHvStNd9pS5VjhM8MaZIOp1cpiSfWFrObmf/BgAA//8sD57xnSIAAA==",
+	"H4sIAAAAAAAC/+w9a28bOZJ/hei7w94e2rHsJDu3/nSKpSRaOI5WkjMzOwgEWl2SOO4mOyTbsjbwfz/w",
+	"0W+21Jo4tiYWECCy1E1WkfWuYvGrN2NRzChQKbyzr16MOY5AAtd/dWeScfUhADHjJJaEUe/M+3nJUIRv",
+	"QCC5BDRbYroAH8GLxQv9BQ4iQv8iEESYhJ7vEfXOEnAA3PM9iiPwzrxfjszgvsfhS0I4BN6Z5An4npgt",
+	"IcJqVrmO1aNCckIX3v39ffqjAS4IzjGXI/iSgJAaeM5i4JKAsH8FyUwOAvUH3OEoDsE7O/HTYQmVsADu",
+	"3fvelwRTSeR6+5OJAL59xPsiVr+lL/kFkApzfs7eZ9e/w0yqabpBMOQsYudEYdOI4oyknzJofvPeYBok",
+	"dOH53jjh+BqvsZqCSIiEY1GzyTHneF0D3c7gglEtvgMkzOWUtFjzDKDsw39ymHtn3n8c5yR5bPf7WE02",
+	"kBDVAfY9kVxLJnFYmvN1p9Pp+N6c8QhL78ybhwxLL3uZJtF1vqMtAK6ui0Uzfz/FqABP06ppRGorFxIK",
+	"029AxBJXq8VPnzXMWHjau8SCoHeMg6agGrE4OeXUySmUyGnMyaw8wenrVuhUlruAWwX4AkilOf3iejo3",
+	"QostzWVXArgYgYgZFVDfmABLXP/WiL2gLh3fsxWKMF0jRRkCrYArkRhAgBhHHCJ2CwqLzavHDctvHD8g",
+	"QhI6k3oiNOjZuQRQWRz/5VZizifzM7RcSxaBEHhRIZhYraDFVaNZJ5vKdOkwvllZ5+ZwwNJsTqPwu07W",
+	"ipHOWULldnK8TtbD9grBKVgvYYV+ZfzG870LJlCXLiA0srGtcPW9GQugvqWfWDJbAkfqVzRLhGSRWs4Z",
+	"pkgNgCRDHAKASOtXveLFHfbe9z90J6cdF8OqIT/ScO3S4kCRUrn5oIgIRJlEIVHUgDANNAyMhmt0DRYG",
+	"CND1GhEpNLhFOOY4FJDBcM1YCJgqIEoTF4nntPNfiM3niFGEwxBZzhZozriGSiRRBBwJwILRFy78AiJm",
+	"igI+4TCpSJpWUhNo0MMSqmCdvjzq/O3oZWdy+vLs9d/PXv/9X15htABLOJIkgnzEHCK4m4WJILeOfe5S",
+	"lP1qV1ytr1rya0AzFl0TCgFaEblEisOZVFRht4aaFcERIMaNLbV95eccwM0lTrpXj7vZxMlUIY4FBD28",
+	"FnVc3zKOLrrDcb83vRr3Rz5aZnILr43lqOVWlAiJlvgW0IJR0LizRBoUCV34KBUdaF4esYj/3zou8CJ8",
+	"9yYJFiC7UYp+GcaJ0g6IJzHBywIXRHiNFmqL8Aqv1cKnRCY0S6hFQprlfZTQkEREMctKsRNEsVy/QJOl",
+	"3SPEAc+WhC4QkWgBUhheWi2xVLwWwly+KOKhlX07dR/hu54FK0evMFDLMUYQA3YszQd8R6IkQuZhxOZI",
+	"0btAGHH9Br4OAXFYYR4oTBYcU7UKMXCD+RYNpOe+UmrgQq1fmTI7nU0vDIErhb0LyILQRWjpTTHcJmHq",
+	"5IuI0I8KK8dKn7TbrrqlNTaybYxDpxihsFJoNjPXZf9nJ2fhudSEt1BCXI2GsMXckrBAFFY+UtwWMSqX",
+	"BdItLcQr10rozb00aLnBmryffhz1FFyrJZkt9T5YZv+LMMQhyipnzixwCtg5ZxE6qbB9NuhWsoo5Ydwa",
+	"qGXgtDUhrHBFS7IwstU8jjAHhOM4JGpGwkXJhDrpbDCiB0HDBg37o/P+5aT7rj/tDcbnH68uJz6S+MYo",
+	"3FSmGC27hNkNKlOZUopyCQJytaiFB6FCAg7SdV0tWQhI+SNFiH975f/kMEwK0Fctk5yt3di8ufp1+sv0",
+	"XX8y/XX6dtTv+4bnNQy5RER4gQnVewa3wNcoSkJJ4hAUuEWLLdVnVcBNDKCuyDgLQ5bIIfAZUIkXDijz",
+	"39RkxiIt0ZkaQ5nhiUSS+SgmsxtjzGAkjEBbYlEiWL052Ri+xUn/4maa105KUV5skITgIJQRzBKuuXRF",
+	"aMBWRZCVqCqaXYT6ZYRwuNIcf4tJqOEvAdXKsdZcMbbwOb1rWERqVTPjjSaRsoa7FxfKEP74a/ciVcep",
+	"RPJ873ww+dXzvbeD0XiScW5ZeRd5Wn01vRiMJ97nwmLaOWqiUUg8u3nHWRLX11PZulq0mUVi89xq0q+h",
+	"hXovXdqU4yVDys6tKi4vxusArxtg4LLZcuycTDqdM/2vveUoiY271blvMuiP+r2iHFFYxUADFMIthCLF",
+	"NBUr5gkt5YTUC5JyG0rjE8Y4AaEpySzETlQzIW5JotD7N6MODh10L7so/dmgkHKGFsAcsKHyAOY4CaVQ",
+	"G9MVBB//A99gLnFpcyo/NPheOcU65LEm0V/6vWn3g/qz+H1V3nm+V9mEMq26R68BZWKBDbucsYGfSSCh",
+	"BLwmVwGgxVHVePFRxDhkFK0jDSGWZUr+7cQ/9V/uphFuMSeYygZYHfhq8JzrafDpHr9B6aB1elXEaESr",
+	"okchyEL5QopQ2kvonej3kwFlawzUxplKgtC+UpQDuS+5NaKxW6RJY9Yq6NwuYjM0ykUDFCCRzGYgxDwJ",
+	"w/VDhG76IVmQaxISuT5XVk0dHzyTSSXI6Y3i/33R6TgjGAUcqgGMtU1AKONpjkkIgW+0n1GERKIYC1EO",
+	"t3kUIECj+ORUTah5xzVraq/XHHirbDWCdur/Hk+6k6uxj3rdSX/68+Cy9/FnH12NFW9cDD4MJj56c9V7",
+	"15/4hsMLv/zVR+Pz9/3e1UXfR6OPFxcfryY+wibsY0f/MLg0itJyla9tsX9edS8ng8mvPhoP+5e9qRJO",
+	"f0U2bmKUX4Vi8yWojuhaALtyZ19bRBlyEinv6WnHvaluBsv2yhJI4SEnoXHO+HZe2sQT7YjdOfmdBBps",
+	"DlA+fGjp4Y2OCsptpdk7oMCxBBu4bE5Qudzljvbllatu/uxo/9r+7Xa0YE7uXB4dKAvI2G/GNF9YwIJa",
+	"cNIbdkeTS21tbl4DA3MrrHfMGDQshgvhh5X4twZgkS/PQ0j6dyBNAnbbMmxL7DXCrc3VAKTJIz8EwJpj",
+	"e3rEb4NbD7QlKfLwkG+Hub354zL5mu0FgeYgZ8utBoMaY/vqfVDPOJJ8EROeHcG1DB/AxVehI4Lo5Koq",
+	"Zm5hA3xaf9I5YD1Z6wxdVtA0b1lwCvP5FhEX4jos9M+ESdfOWwvakTv+6XW74OScA0x3S8jnEL21KQYX",
+	"Pdkt3X3QRgr9tmR//dVXp6//QD46g8LPqbawiH51U9KZN+9ttpJNtSxT0iIpowFqVQXQtuxlUzK+kH3P",
+	"Jt6MpdnaZjLG9Rj7TnRcXiuahCG+rkUWKzmv7KUvldVw8n09lj8BIZHxqwpRyDQ/4zSvXbvk3E9JsgKV",
+	"SvaKAEe4Ghqytv8KZ0EdHfL2/BZLkUVMdo1qWK/eCaZ1s1On3YCXue9FX18yv5DjtBGiNI82YzwQLbBw",
+	"KRVDqtbNsI57ld4chLCZjr/NfsjHadS7BvMv+pkHsCGGTEg966aKuYids6BW+ZDWF+xU6mCN2LL03x51",
+	"+pYKOyfabnFzqB45VI8cqkeetHqkjYlyqDBxVZgcSkYOJSOHkpFDyciTl4woI4swOtIatjlDk7sk5pVK",
+	"RibDiwgkGdML3Wzp5/S+7yUrcEtgBUFXNplPpaXBccxVbbgpE8+WyWk2tFgaM/ebtfv4jmuyBvPwd0zh",
+	"/1RVOF+/IEGryQ+1OodanTRlFQNttI5GVbtoiYW2i6gxjARDc1yyC05OW8f6ftQqISGxTBzU2bXI6Dm1",
+	"zsaoN+q+nfhGCXSHw9HHT/2eeUxUKdbzM3LRb3m+p/LKg8t3U/NmV+1vOojne6P+P/rnE/1x2L0a6w/9",
+	"y57+vzs6fz9Qj5VJJH/5UPv0DGufDgVFJlpVjEYW12GXGqOqN+RvOqqpoesmAZEXbOEsyKkojPNRvztR",
+	"297/ZdK/VAx7NeyZb7q93vR8MBn0x1oGfPj4qT+1GkT9pLRD4Zf0z/P33ct3/akplCkTTzZFjV7wpuPH",
+	"AZRPH+dlP+pre6QNBSTQARKB199o1JiJzHoFAVGw4HBYjZ8W/tJOlXf2VYUPYc644kdnyr6MXV+bLWY2",
+	"5VdAGJRC574y2XT8TO+3+ssG3LQV5RtvRT1wq4JayMytqdpAVFgGXaMyDax+sgAr7fTqqPPT0cuTUvwq",
+	"RcL+XolvOTGzNWYbDWCDqbaA1aa2VoMtj7n+0TqK0vHrbYH+Qpg3l8npOeydIrwbqgksfz9EJkCNV6jT",
+	"cx1uhdlN+7xtrebPgRnoZ1yu2oQnqTVsaV95tvXKvcYwZduMZ3NqKAPOT1Fvs24PUEFS3IXN2w+FJx+K",
+	"Bt4TIRlfP2RVSaZldiPupYHkQetJ2q7CCGYsioAGmd9UXoQ8sT+9TfME1RSnkrRWSqvH8/gL1uEGwpFy",
+	"HYFKlB5PLxWBtT3gr+m1klk68U93C9t8SetI2ucnBb5Vu1FDPA0nI2NUx2FifOLbxgUpYf7T6z/WC8Au",
+	"Q4qKX9+iDOSWu/4ArFwecAu98/LDD8XQWXDAgYYrKKxCxVlUEODGfNDBDRu0VxFEH3WUCT1OaFA2o8yp",
+	"Av+V/3o3CgQaTIirvrpP8yClgQIL9P792YcPqijafqXsOaCBDlPrwDWRqdPLEyqU5pAoIgEli6X0kXav",
+	"lTNlPhBRiIFW8PFOfjpzF6Hr8d0wj9VPDVCXB3911qIYWm9UccJ8uRq3XXujjRU1k3XsADt3t5QJqZ0s",
+	"z+3T7ZY4PfnjqagygOc4VquKC44hkqRswLbsJqKzMc3zFOMCahPVLFkYRdZk9e4iK5/dL+9KdSEbd9i2",
+	"KGko3CgcKvqGEz/OmgqxFaZtgrMSBullAqfotnzrWaVtrUmexLBI4wGNnPnJbU6MIA7xzLbXSp8t61SD",
+	"WlroYLuv5HVUVgmb6f1dqxrauFYtGLgxocWL+EX4LscR2zTWNyH5sq1B5T7jc0XJl8SUCRDakBN546Kn",
+	"FSiF41AQS8yzjSvkNTYihDiEWKoSAfu9qeawv4pymPBkSzbafd5mu+gZ6czQ5lMu/Lvk/uo60gFcxG7h",
+	"LWfRQ7WBe6jmbq6lHJuq+e3isiEQteWEhx2+tSHpAvEqDrCEtEFZ44IWq4bLW/4v4Mx2uDK8rQS4yc67",
+	"8qanhXM/9VSc790dLdiR/fZ/iImrftsWbey5Z9CvErsjb6RwMUkqHZ0z4XsbsHuBlF73S0fv/Mz58U2N",
+	"norFqbnMm3NyBzY2Zw5l6lfy3+3IJq6n5gZ99kyZwDEjVDeQqZDSIxbMOdZnvmswH2U19DsqqlKx3PY4",
+	"0dZqrfO8nC5kK1CLgo0KsIoJhxxwsNbZJ/mQVVg7LGN9sVpXbpWrp9rinkYuEvU2Siva/0Dt1a4FU9+J",
+	"tr5X+VWxjmhLHVCLCokRJsI2jroBiEXFIipVN7CMMJcqiShbVDmUkuPbU9l1Bay+InTOzAmsGVilZlbN",
+	"+zCYmHSo1MMqCkBj4Lcm9nULXBgsT150XnTUkywGimPinXkv9Ve+F2O51OLsGAfB0SztNsqMXGYxcC0q",
+	"lTbQte22H6yXNU98w4K1OXdJJRiK0qnemX7v+HdrtuT9Zjc5ApVus/dlHaNUtf7CaHcN9mmn82CzV60H",
+	"PX2tVk0Jc9sAQjKjbu9979UDwlE+c+2AYkBvcUiCNP9m5n/1ePOPWQRSN1xTFqeSZ3OW0EDB8fpx10EC",
+	"pzhEAvgtcATqBc1GSsdivlY5/CBAOFXCqZlvdu3e945Til+Ag+Dt2VvPL3Vs/q3my5jOoGkT5i8J6Bi7",
+	"ZdLMLtragjm3rD5/RyqvHih2rKv63X0SdQ8ofe8o7B3IYiGtroDRZqROQgQoJNQWQeYkd6z/Pv6auTP3",
+	"WuwmLscWpK6/VVOkprWi439X/YCUxkuugLIvK1I8kan3IYaFVtkbSdw+WKBypThyIi/23N6Vzh9ejbh9",
+	"rP3TJprPtPuWaIiDZ6dJ1P7kCkTV/qYKde/YfAyyzIM6bJ4yXbFqWXP5AuRRnJ6Ka9IuaceBx9cwfo3D",
+	"ldth7XL3NPb8/C6DXmYnPkwReAwc2WGcMwAfbp3kO+vGcgcItwnY2KjhoB7d6tG6h2XGOOa1koBtfDKq",
+	"JnR/ILtsU77csfTlJ/eWHB9Vm2h9ut/uSLZtxVMN+lCp2Ue5xBIJnNp0ERPpeaOifWk4aQk4lMtNfPPe",
+	"POGm2qqZqeMGKkNgxl2b9Xu58dGEZg+X0NQFahpq83tW/G9eNfCbGv+N4QYdq/pOwYbaUf39MxA1eEjn",
+	"8faRtf/+ePN3C2dXzKmCQJffmnCpPryLGEcY2S5c5rCXjddlpy8U1Kenjwf1JOVx4TxETqgulDd8sI/S",
+	"aqgoLz+Ok3PtcVbetoV3TXHbj8nAji4ljTys1+ugnjWTGk4u6ekDV+7ClVxp3+y0VZayFBCaI6ZxfqKa",
+	"JVLrD13HV+LjzDlt5uDUN/0ezOu4fOiR2dfVLLjJ30sb+R48PAc9moU0wZCIFajr+CsJ7jfZp0N7dGR7",
+	"9DFijbFHsl/BdVfzy0ayanbbHlEuD+vyeC+jCDgtc0rPXpnDOn5+xtMvHAlQLpY+7qqbUsrZ0iHj1NdP",
+	"R4PfK/D9lEK1XQ5VFYw+04i3g9ke1ZEaZq0S+GxJbnUzd2rvTVxhYlq9MG57V+DQz7udE21FqLA3LbW2",
+	"iHEiUqtD+13p9Yb7KEgMi2iMICDmdLKtMGPzJh12bNFtYS4Ngq599lEFiu9euhyCY3MT8P3nfWD+lHoe",
+	"nfs/EKHrfcyh5+fJ+6a1BQoYmNoNHIZshch+2paWmXLd7xRRRNZaw6RZcOXM1dnZyL527Gyf/WFs1PZc",
+	"ajXEgU/+BHxi9qrYwYDocv8biGVWemya3dpjwOg6kcUAiFbijGel0LpjV4118rarW5w6c6z/x2EbV6+C",
+	"5nCBfu7g3rUl3wsiTHWFXThtiqmGJ6k95m8X1E9GcN+lDrZEbk/iyrUkePOEqYj1bVsY87Q+eyj0IYjy",
+	"1mZC6tHtv0/K99Mjp2T43N2/R4++D4tdrO0JHtN0cGb7c+xlDbGlXslKgslPvyeycDrAHCkSNySOIWjQ",
+	"oMdfFbr3JrsegoS6bOvp70vS7dxeAvGojmWN39dIDeajCBv1RhaU6Y6NMyzAPe3MAL514uwoxBNbwBpL",
+	"U10bPFcppWxBvdkHadUgrdLYmP5MhA5Kh9i0Q9zT8qPIeNMaYu0jl8WZwkZjoB8oeAcZL1SkGZQ7XG1x",
+	"CoqdmJ5WjP0JiwNdTbEcdFB4DMFdHGJCnS7Iswu6M26O8u23K9Q3W2abtBfYy7RsEw1X/OhObrpRobqj",
+	"VdT5lAatwl19GjzDUJdOgRziXPsf51K9qzaln2wjTqWIwTYAyMLDO8W5zPn/dixjHv0RAg+OG3f3NYFs",
+	"NuiQQd5TJSZLNe419kpbUeYmY3OD3rLGI8LeU5d261ilnYqJNNd40PwIJItNC2H0y5HOf6Il4MDeQVNo",
+	"YBzhm/SIpZmyfmwyt2HfZ100n9J+PZzXSs3ian/VRpZJ8x6HqPzOUXnNFKZ3dHqG3jqMFFaKg8zVPFUu",
+	"16q5lQ4d6iefn+FpjJeD5bn/lqem0Ep+VUgWC3QNSnmk3a1RQiUJEQeRRI54ienP1oonTGO4fa4jengD",
+	"1NEM795aoE/NqllnvUPR0kFUbImyKkppXbOUOaJU2STXEZGy0S01YqWl+NCPPj+dmoneA6fsP6eorVKc",
+	"Ugji1IjeMEUroh+bR58f0WeC40D2+0/2hkoRRgHHc1m6HjJvPpiqiho7mF7b25NtunX4IUyxJ2GKciP3",
+	"5nMx6rG9DVE8Xe4do+xKzLx2bH8jJmYb2dwN91Y19gSs+50cuvrFCo99zFdb1+1YUD9gShkPpYnPvNjn",
+	"zyFwVGmikTWS1UH2UUJvKFtR+4zyMO0nW6xorwfZVK+oXzieidtWBrhmoXNx+8R2x89L0Fc55LLYduib",
+	"kxA0uprLjellKpvcxoO9tnMTNOl9njgwUKvhCs3vyyWNTRLW3IeOuTyeMx4dpRcV5LNUrksj5v6prMO2",
+	"6iDFt19ap9/77OwzvfciubhZB+l8kM5/DumcEe0WuzCrxhx/MkJKJ7cZNe+gQU+7OSGh4COc5rFD3VtY",
+	"bJHdZv724ttUiR7Mz6eRdQcRdxBxfx4RZyvKbdjCiLAa3BWpZHv0iaJEql5DMivkWvWRmvQle66VMhQy",
+	"uoDsYm5l26qEClM3mBRPgmdy1BT9RL4pBFzrIsFBL73pm0MidDeIGQsc9T8FGfkpBf9HEJDvgCo0wSL1",
+	"RFKyBsUGMZXSwcK+c6gA3M+GTWZ3kEpdh3CUCEhZWHNYfh/WZhlxDHcx23xHR4Ut++aFPUsBSbiTqRPd",
+	"fOqtfg5t/CkXYOn6xcARZ6sD3bmPTzAuc8L6i6iQHRbKxC7Q2/ZUSktJb1JQfmY+pH0Y9cWUSitZTaVv",
+	"4cfiRnUpYU1pETOY54oz9EbdtxPP94b9y97g8t20OxyOPn7qXni+Zz7qS4ZH/X/0zyf647B7NdYf+pc9",
+	"/X93dP5+oB5zRCl8N3L6MTeo9qdmqm4Ysnh9X9MqlB/ZYQp9kZndACFJGCKeUKrLaEynYH0VoFwSgaS5",
+	"ALphF7jsYVnGLwu6BFjCkX19R4gwlzkw9tTCNmiABg8EyyEtd7jt4fslwCyRpwoeB1FayWR82yPlJ7S4",
+	"bK58+az3vSoOXTfc7u/VczY8YHytfbl+bn8POjtvxTKjmteNPk946J15Synjs+PjkM1wuFRkef/5/v8H",
+	"ANhvG8u9xwAA",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -94,3 +94,78 @@ func (s *Server) PostRemoveFromCart(ctx echo.Context) error {
 		Message: "success",
 	})
 }
+
+func validationUpdateCartItemRequest(req *generated.UpdateCartItemRequest) (dto.UpdateCartItemInput, error) {
+	err := validation.ValidateStruct(
+		req,
+		validation.Field(&req.UserId, validation.Required),
+		// quantity required, a missing quantity must not remove the item
+		validation.Field(&req.Quantity, validation.NotNil, validation.Min(0)),
+	)
+
+	if err != nil {
+		return dto.UpdateCartItemInput{}, err
+	}
+
+	return dto.UpdateCartItemInput{
+		UserId:   uint(req.UserId),
+		Quantity: *req.Quantity,
+	}, nil
+}
+
+// PutCartItemsProductId implements generated.ServerInterface.
+func (s *Server) PutCartItemsProductId(ctx echo.Context, productId int) error {
+	req := generated.UpdateCartItemRequest{}
+	if err := ctx.Bind(&req); err != nil {
+		customError := utils.NewCustomError(err.Error(), nil, http.StatusBadRequest)
+		return ResponseError(ctx, customError)
+	}
+
+	dto, err := validationUpdateCartItemRequest(&req)
+	if err != nil {
+		customError := utils.NewCustomError("validation error", err, http.StatusBadRequest)
+		return ResponseError(ctx, customError)
+	}
+
+	dto.ProductId = uint(productId)
+
+	err = s.cartUsecase.UpdateCartItem(ctx.Request().Context(), dto)
+	if err != nil {
+		return ResponseError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, generated.SuccessResponse{
+		Message: "success",
+	})
+}
+
+func validationGetCartRequest(req *generated.GetCartParams) (dto.GetCartInput, error) {
+	err := validation.ValidateStruct(
+		req,
+		validation.Field(&req.UserId, validation.Required),
+	)
+
+	if err != nil {
+		return dto.GetCartInput{}, err
+	}
+
+	return dto.GetCartInput{
+		UserId: uint(req.UserId),
+	}, nil
+}
+
+// GetCart implements generated.ServerInterface.
+func (s *Server) GetCart(ctx echo.Context, params generated.GetCartParams) error {
+	dto, err := validationGetCartRequest(&params)
+	if err != nil {
+		customError := utils.NewCustomError("validation error", err, http.StatusBadRequest)
+		return ResponseError(ctx, customError)
+	}
+
+	cart, err := s.cartUsecase.GetCart(ctx.Request().Context(), dto)
+	if err != nil {
+		return ResponseError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, utils.NewResponse("cart detail", cart, nil))
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"encoding/json"
+	"hangry/domain/dto"
+	"hangry/generated"
+	"reflect"
+	"testing"
+)
+
+func Test_validationUpdateCartItemRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    dto.UpdateCartItemInput
+		wantErr bool
+	}{
+		{
+			name: "success",
+			body: `{"userId": 1, "quantity": 2}`,
+			want: dto.UpdateCartItemInput{UserId: 1, Quantity: 2},
+		},
+		{
+			name: "zero quantity removes the item",
+			body: `{"userId": 1, "quantity": 0}`,
+			want: dto.UpdateCartItemInput{UserId: 1, Quantity: 0},
+		},
+		{
+			name:    "missing quantity",
+			body:    `{"userId": 1}`,
+			wantErr: true,
+		},
+		{
+			name:    "null quantity",
+			body:    `{"userId": 1, "quantity": null}`,
+			wantErr: true,
+		},
+		{
+			name:    "negative quantity",
+			body:    `{"userId": 1, "quantity": -1}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := generated.UpdateCartItemRequest{}
+			if err := json.Unmarshal([]byte(tt.body), &req); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := validationUpdateCartItemRequest(&req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validationUpdateCartItemRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("validationUpdateCartItemRequest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"hangry/generated"
 	"hangry/utils"
 	"net/http"
+	"strings"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/labstack/echo/v4"
@@ -28,9 +29,20 @@ func validateCreateOrderRequest(req *generated.PostOrderJSONRequestBody) (dto.Or
 		}
 	}
 
+	promoCodes := []string{}
+	if req.PromoCodes != nil {
+		for _, promoCode := range *req.PromoCodes {
+			promoCode = strings.ToUpper(strings.TrimSpace(promoCode))
+			if promoCode != "" {
+				promoCodes = append(promoCodes, promoCode)
+			}
+		}
+	}
+
 	return dto.OrderInput{
-		UserId:   uint(req.UserId),
-		PromoIds: promoIds,
+		UserId:     uint(req.UserId),
+		PromoIds:   promoIds,
+		PromoCodes: promoCodes,
 	}, nil
 }
 
@@ -57,3 +69,25 @@ func (s *Server) PostOrder(ctx echo.Context) error {
 		"message": "Order placed successfully",
 	})
 }
+
+// PostOrderQuote implements generated.ServerInterface.
+func (s *Server) PostOrderQuote(ctx echo.Context) error {
+	req := generated.PostOrderJSONRequestBody{}
+	if err := ctx.Bind(&req); err != nil {
+		customError := utils.NewCustomError("failed to bind request", err, http.StatusBadRequest)
+		return ResponseError(ctx, customError)
+	}
+
+	dto, err := validateCreateOrderRequest(&req)
+	if err != nil {
+		customError := utils.NewCustomError("validation error", err, http.StatusBadRequest)
+		return ResponseError(ctx, customError)
+	}
+
+	quote, err := s.orderUsecase.QuoteOrder(ctx.Request().Context(), dto)
+	if err != nil {
+		return ResponseError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, utils.NewResponse("order quote", quote, nil))
+}
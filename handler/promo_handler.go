@@ -1,29 +1,224 @@
 package handler
 
 import (
+	"context"
+	"hangry/constants"
 	"hangry/domain/dto"
+	"hangry/domain/models"
+	"hangry/eligibility"
 	"hangry/generated"
 	"hangry/utils"
 	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/labstack/echo/v4"
 )
 
+var (
+	promoCodePattern = regexp.MustCompile(`^[A-Z0-9_-]+$`)
+	clockTimePattern = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+)
+
+// eachOf validates every element of the slice a pointer field points to,
+// validation.Each does not follow the pointer.
+func eachOf(rules ...validation.Rule) validation.Rule {
+	return validation.By(func(value interface{}) error {
+		slice, isNil := validation.Indirect(value)
+		if isNil {
+			return nil
+		}
+
+		return validation.Validate(slice, validation.Each(rules...))
+	})
+}
+
+// notBlankCity rejects a city that is empty once cleaned up.
+func notBlankCity(value interface{}) error {
+	city, _ := value.(string)
+	if utils.NormalizeCity(city) == "" {
+		return validation.NewError("validation_required", "cannot be blank")
+	}
+
+	return nil
+}
+
+func validSegmentation(value interface{}) error {
+	segmentation, _ := value.(generated.CreatePromoRequestSegmentation)
+	if !eligibility.IsSegmentation(string(segmentation)) {
+		return validation.NewError("validation_in_invalid", "must be one of "+strings.Join(eligibility.Segmentations(), ", "))
+	}
+
+	return nil
+}
+
+func validTimezone(value interface{}) error {
+	timezone, _ := value.(*string)
+	if timezone == nil {
+		return nil
+	}
+	if _, err := time.LoadLocation(*timezone); err != nil || *timezone == "" {
+		return validation.NewError("validation_timezone_invalid", "must be a valid IANA timezone")
+	}
+
+	return nil
+}
+
+func validSchedules(value interface{}) error {
+	schedules, _ := value.(*[]generated.PromoSchedule)
+	if schedules == nil {
+		return nil
+	}
+
+	errs := validation.Errors{}
+	for i := range *schedules {
+		schedule := &(*schedules)[i]
+		err := validation.ValidateStruct(
+			schedule,
+			// days required and each a day of the week
+			validation.Field(&schedule.Days, validation.Required, validation.Each(validation.Min(0), validation.Max(6))),
+			// startTime and endTime required as HH:MM
+			validation.Field(&schedule.StartTime, validation.Required, validation.Match(clockTimePattern)),
+			validation.Field(&schedule.EndTime, validation.Required, validation.Match(clockTimePattern)),
+		)
+		if err != nil {
+			errs[strconv.Itoa(i)] = err
+		}
+	}
+
+	return errs.Filter()
+}
+
+func validTiers(value interface{}) error {
+	tiers, _ := value.(*[]generated.PromoTier)
+	if tiers == nil {
+		return nil
+	}
+
+	errs := validation.Errors{}
+	minAmounts := map[float32]bool{}
+	for i := range *tiers {
+		tier := &(*tiers)[i]
+		isPercentage := tier.DiscountType == constants.PROMOTIERPERCENTAGE
+		err := validation.ValidateStruct(
+			tier,
+			// minAmount greater than 0
+			validation.Field(&tier.MinAmount, validation.Required, validation.Min(float32(1))),
+			// discountType PERCENTAGE or FIXED
+			validation.Field(&tier.DiscountType, validation.Required, validation.In(constants.PROMOTIERPERCENTAGE, constants.PROMOTIERFIXED)),
+			// discountValue greater than 0, a percentage is at most 100
+			validation.Field(&tier.DiscountValue, validation.Required, validation.Min(float32(1)), validation.When(isPercentage, validation.Max(float32(100)))),
+			// maxDiscountAmount only for a percentage, if it exists greater than 0
+			validation.Field(&tier.MaxDiscountAmount,
+				validation.When(tier.MaxDiscountAmount != nil && !isPercentage, validation.Nil.Error("only for PERCENTAGE")),
+				validation.When(tier.MaxDiscountAmount != nil, validation.Min(float32(1))),
+			),
+		)
+		// two tiers starting at the same amount would make the applied one
+		// ambiguous
+		if err == nil && minAmounts[tier.MinAmount] {
+			err = validation.Errors{"minAmount": validation.NewError("validation_not_unique", "must be unique")}
+		}
+		if err != nil {
+			errs[strconv.Itoa(i)] = err
+		}
+		minAmounts[tier.MinAmount] = true
+	}
+
+	return errs.Filter()
+}
+
+// validVariants checks the A/B variants of a discount, a percentage discount
+// can not go above 100 and only it has a max discount amount.
+func validVariants(isPercentage bool) validation.RuleFunc {
+	return func(value interface{}) error {
+		variants, _ := value.(*[]generated.PromoVariant)
+		if variants == nil {
+			return nil
+		}
+
+		errs := validation.Errors{}
+		names := map[string]bool{}
+		for i := range *variants {
+			variant := &(*variants)[i]
+			err := validation.ValidateStruct(
+				variant,
+				// name required
+				validation.Field(&variant.Name, validation.Required, validation.Length(1, 50)),
+				// weight if it exists greater than 0
+				validation.Field(&variant.Weight, validation.When(variant.Weight != nil, validation.Min(1))),
+				// discountValue greater than 0, a percentage is at most 100
+				validation.Field(&variant.DiscountValue, validation.Required, validation.Min(float32(1)), validation.When(isPercentage, validation.Max(float32(100)))),
+				// maxDiscountAmount only for a percentage, if it exists greater than 0
+				validation.Field(&variant.MaxDiscountAmount,
+					validation.When(variant.MaxDiscountAmount != nil && !isPercentage, validation.Nil.Error("only for PERCENTAGE_DISCOUNT")),
+					validation.When(variant.MaxDiscountAmount != nil, validation.Min(float32(1))),
+				),
+			)
+			// orders record the variant, two with the same name could not be
+			// told apart in the analysis
+			if err == nil && names[variant.Name] {
+				err = validation.Errors{"name": validation.NewError("validation_not_unique", "must be unique")}
+			}
+			if err != nil {
+				errs[strconv.Itoa(i)] = err
+			}
+			names[variant.Name] = true
+		}
+
+		return errs.Filter()
+	}
+}
+
 func validationCreatePromoRequest(req *generated.CreatePromoRequest) (dto.CreatePromoInput, error) {
+	// codes are matched case-insensitively, so they are stored uppercase
+	if req.Code != nil {
+		code := strings.ToUpper(strings.TrimSpace(*req.Code))
+		req.Code = &code
+	}
+	if req.StackGroup != nil {
+		stackGroup := strings.TrimSpace(*req.StackGroup)
+		req.StackGroup = &stackGroup
+	}
+	if req.Variants != nil {
+		for i := range *req.Variants {
+			(*req.Variants)[i].Name = strings.TrimSpace((*req.Variants)[i].Name)
+		}
+	}
+	codeOnly := req.CodeOnly != nil && *req.CodeOnly
+	repeatable := req.Repeatable != nil && *req.Repeatable
+	isDiscount := req.Type == generated.CreatePromoRequestTypePERCENTAGEDISCOUNT || req.Type == generated.CreatePromoRequestTypeFIXEDAMOUNTDISCOUNT
+
 	err := validation.ValidateStruct(
 		req,
 		// name required
 		validation.Field(&req.Name, validation.Required),
-		// segmentation required and check enum
-		validation.Field(&req.Segmentation, validation.Required, validation.In(generated.CreatePromoRequestSegmentationALL, generated.CreatePromoRequestSegmentationCITY, generated.CreatePromoRequestSegmentationLOYALUSER, generated.CreatePromoRequestSegmentationNEWUSER)),
+		// segmentation required and must have an eligibility rule
+		validation.Field(&req.Segmentation, validation.Required, validation.By(validSegmentation)),
+		// NewUserDays only for NEW_USER, if it exists greater than 0
+		validation.Field(&req.NewUserDays,
+			validation.When(req.NewUserDays != nil && req.Segmentation != generated.CreatePromoRequestSegmentationNEWUSER, validation.Nil.Error("only for NEW_USER")),
+			validation.When(req.NewUserDays != nil, validation.Min(1)),
+		),
+		// LapsedDays if segmentation is LAPSED_USER required and greater than 0
+		validation.Field(&req.LapsedDays,
+			validation.When(req.Segmentation == generated.CreatePromoRequestSegmentationLAPSEDUSER, validation.Required, validation.Min(1)),
+			validation.When(req.LapsedDays != nil && req.Segmentation != generated.CreatePromoRequestSegmentationLAPSEDUSER, validation.Nil.Error("only for LAPSED_USER")),
+		),
+		// OrderNumber if segmentation is NTH_ORDER required and greater than 0
+		validation.Field(&req.OrderNumber,
+			validation.When(req.Segmentation == generated.CreatePromoRequestSegmentationNTHORDER, validation.Required, validation.Min(1)),
+			validation.When(req.OrderNumber != nil && req.Segmentation != generated.CreatePromoRequestSegmentationNTHORDER, validation.Nil.Error("only for NTH_ORDER")),
+		),
 		// startDate
 		validation.Field(&req.StartDate, validation.Required),
 		// endDate required and greater than startDate
 		validation.Field(&req.EndDate, validation.Required, validation.Min(req.StartDate)),
 		// type required and check enum
-		validation.Field(&req.Type, validation.Required, validation.In(generated.CreatePromoRequestTypePERCENTAGEDISCOUNT, generated.CreatePromoRequestTypeBUYXGETYFREE)),
+		validation.Field(&req.Type, validation.Required, validation.In(generated.CreatePromoRequestTypePERCENTAGEDISCOUNT, generated.CreatePromoRequestTypeFIXEDAMOUNTDISCOUNT, generated.CreatePromoRequestTypeBUYXGETYFREE, generated.CreatePromoRequestTypeTIEREDDISCOUNT)),
 		// buyItemCount if type is BUYXGETYFREE required and greater than 0
 		validation.Field(&req.BuyItemCount, validation.When(req.Type == generated.CreatePromoRequestTypeBUYXGETYFREE, validation.Required, validation.Min(1))),
 		// freeItemCount if type is BUYXGETYFREE required and greater than 0
@@ -32,22 +227,116 @@ func validationCreatePromoRequest(req *generated.CreatePromoRequest) (dto.Create
 		validation.Field(&req.BuyProductId, validation.When(req.Type == generated.CreatePromoRequestTypeBUYXGETYFREE, validation.Required, validation.Min(1))),
 		// FreeProductId if type is BUYXGETYFREE required and greater than 0
 		validation.Field(&req.FreeProductId, validation.When(req.Type == generated.CreatePromoRequestTypeBUYXGETYFREE, validation.Required, validation.Min(1))),
+		// Repeatable only for BUYXGETYFREE
+		validation.Field(&req.Repeatable, validation.When(repeatable && req.Type != generated.CreatePromoRequestTypeBUYXGETYFREE, validation.Nil.Error("only for BUY_X_GET_Y_FREE"))),
+		// MaxRepeat if it exists requires repeatable and greater than 0
+		validation.Field(&req.MaxRepeat, validation.When(req.MaxRepeat != nil && !repeatable, validation.Nil.Error("requires repeatable")), validation.When(req.MaxRepeat != nil, validation.Min(1))),
+		// StackGroup if it exists not empty
+		validation.Field(&req.StackGroup, validation.When(req.StackGroup != nil, validation.Required, validation.Length(1, 50))),
 		// MaxUsageLimit if it exists required and greater than 0
 		validation.Field(&req.MaxUsageLimit, validation.When(req.MaxUsageLimit != nil, validation.Min(1))),
-		// DiscountValue if type is PERCENTAGEDISCOUNT required and greater than 0
-		validation.Field(&req.DiscountValue, validation.When(req.Type == generated.CreatePromoRequestTypePERCENTAGEDISCOUNT, validation.Required, validation.Min(float32(1))), validation.Max(float32(100))),
+		// MaxUsagePerUser if it exists required and greater than 0
+		validation.Field(&req.MaxUsagePerUser, validation.When(req.MaxUsagePerUser != nil, validation.Min(1))),
+		// MaxBudgetAmount if it exists greater than 0
+		validation.Field(&req.MaxBudgetAmount, validation.When(req.MaxBudgetAmount != nil, validation.Min(float32(1)))),
+		// DiscountValue if type is PERCENTAGEDISCOUNT or FIXEDAMOUNTDISCOUNT required and greater than 0, a percentage is at most 100
+		validation.Field(&req.DiscountValue,
+			validation.When(isDiscount, validation.Required, validation.Min(float32(1))),
+			validation.When(req.Type == generated.CreatePromoRequestTypePERCENTAGEDISCOUNT, validation.Max(float32(100))),
+		),
 		// MaxDiscountAmount if type is PERCENTAGEDISCOUNT and it exists required and greater than 0
-		validation.Field(&req.MaxDiscountAmount, validation.When(req.Type == generated.CreatePromoRequestTypePERCENTAGEDISCOUNT && req.MaxDiscountAmount != nil, validation.Min(float32(1)))),
+		validation.Field(&req.MaxDiscountAmount, validation.When(req.Type == generated.CreatePromoRequestTypePERCENTAGEDISCOUNT && req.MaxDiscountAmount != nil, validation.Required, validation.Min(float32(1)))),
 		// MinOrderAmount if it exists required and greater than 0
-		validation.Field(&req.MinOrderAmount, validation.When(req.Type == generated.CreatePromoRequestTypePERCENTAGEDISCOUNT && req.MinOrderAmount != nil, validation.Min(float32(1)))),
-		// Cities if segmentation is CITY required and not empty
-		validation.Field(&req.Cities, validation.When(req.Segmentation == generated.CreatePromoRequestSegmentationCITY, validation.Required, validation.Length(1, 0))),
+		validation.Field(&req.MinOrderAmount, validation.When(isDiscount && req.MinOrderAmount != nil, validation.Required, validation.Min(float32(1)))),
+		// Code if it exists only letters, numbers, dash and underscore
+		validation.Field(&req.Code, validation.When(req.Code != nil, validation.Required, validation.Length(3, 50), validation.Match(promoCodePattern))),
+		// CodeOnly requires a code, otherwise the promo can not be redeemed at all
+		validation.Field(&req.CodeOnly, validation.When(codeOnly && req.Code == nil, validation.Nil.Error("requires code"))),
+		// Cities if segmentation is CITY required, not empty and no blank city
+		validation.Field(&req.Cities,
+			validation.When(req.Segmentation == generated.CreatePromoRequestSegmentationCITY, validation.Required, validation.Length(1, 0)),
+			eachOf(validation.By(notBlankCity)),
+		),
+		// UserIds only for USER_LIST, if it exists not too many and each greater than 0
+		validation.Field(&req.UserIds,
+			validation.When(req.UserIds != nil && req.Segmentation != generated.CreatePromoRequestSegmentationUSERLIST, validation.Nil.Error("only for USER_LIST")),
+			validation.When(req.UserIds != nil, validation.Length(0, maxPromoUsersPerRequest), eachOf(validation.Required, validation.Min(1))),
+		),
+		// ProductIds only for PERCENTAGEDISCOUNT, if it exists not empty and each greater than 0
+		validation.Field(&req.ProductIds,
+			validation.When(req.ProductIds != nil && req.Type != generated.CreatePromoRequestTypePERCENTAGEDISCOUNT, validation.Nil.Error("only for PERCENTAGE_DISCOUNT")),
+			validation.When(req.ProductIds != nil, validation.Required, eachOf(validation.Required, validation.Min(1))),
+		),
+		// Tiers if type is TIEREDDISCOUNT required, each with a unique min amount
+		validation.Field(&req.Tiers,
+			validation.When(req.Type == generated.CreatePromoRequestTypeTIEREDDISCOUNT, validation.Required),
+			validation.When(req.Tiers != nil && req.Type != generated.CreatePromoRequestTypeTIEREDDISCOUNT, validation.Nil.Error("only for TIERED_DISCOUNT")),
+			validation.By(validTiers),
+		),
+		// Variants only for PERCENTAGEDISCOUNT or FIXEDAMOUNTDISCOUNT, if it exists at least two with unique names
+		validation.Field(&req.Variants,
+			validation.When(req.Variants != nil && !isDiscount, validation.Nil.Error("only for PERCENTAGE_DISCOUNT and FIXED_AMOUNT_DISCOUNT")),
+			validation.When(req.Variants != nil, validation.Length(2, 0)),
+			validation.By(validVariants(req.Type == generated.CreatePromoRequestTypePERCENTAGEDISCOUNT)),
+		),
+		// RolloutPercentage if it exists between 1 and 100
+		validation.Field(&req.RolloutPercentage, validation.When(req.RolloutPercentage != nil, validation.Min(1), validation.Max(100))),
+		// Timezone if it exists a known IANA timezone
+		validation.Field(&req.Timezone, validation.By(validTimezone)),
+		// Schedules if it exists each with days and a HH:MM window
+		validation.Field(&req.Schedules, validation.By(validSchedules)),
 	)
 
 	if err != nil {
 		return dto.CreatePromoInput{}, err
 	}
 
+	var variants []dto.PromoVariantInput
+	if req.Variants != nil {
+		for _, variant := range *req.Variants {
+			variantInput := dto.PromoVariantInput{
+				Name:          variant.Name,
+				Weight:        1,
+				DiscountValue: float64(variant.DiscountValue),
+			}
+			if variant.Weight != nil {
+				variantInput.Weight = *variant.Weight
+			}
+			if variant.MaxDiscountAmount != nil {
+				maxDiscountAmount := float64(*variant.MaxDiscountAmount)
+				variantInput.MaxDiscountAmount = &maxDiscountAmount
+			}
+			variants = append(variants, variantInput)
+		}
+	}
+
+	var schedules []dto.PromoScheduleInput
+	if req.Schedules != nil {
+		for _, schedule := range *req.Schedules {
+			schedules = append(schedules, dto.PromoScheduleInput{
+				Days:      schedule.Days,
+				StartTime: schedule.StartTime,
+				EndTime:   schedule.EndTime,
+			})
+		}
+	}
+
+	var tiers []dto.PromoTierInput
+	if req.Tiers != nil {
+		for _, tier := range *req.Tiers {
+			tierInput := dto.PromoTierInput{
+				MinAmount:     float64(tier.MinAmount),
+				DiscountType:  tier.DiscountType,
+				DiscountValue: float64(tier.DiscountValue),
+			}
+			if tier.MaxDiscountAmount != nil {
+				maxDiscountAmount := float64(*tier.MaxDiscountAmount)
+				tierInput.MaxDiscountAmount = &maxDiscountAmount
+			}
+			tiers = append(tiers, tierInput)
+		}
+	}
+
 	dto := dto.CreatePromoInput{
 		Name:         req.Name,
 		Segmentation: string(req.Segmentation),
@@ -60,6 +349,10 @@ func validationCreatePromoRequest(req *generated.CreatePromoRequest) (dto.Create
 		dto.Description = req.Description
 	}
 
+	dto.NewUserDays = req.NewUserDays
+	dto.LapsedDays = req.LapsedDays
+	dto.OrderNumber = req.OrderNumber
+
 	if req.MinOrderAmount != nil {
 		minOrderAmount := float64(*req.MinOrderAmount)
 		dto.MinOrderAmount = &minOrderAmount
@@ -100,10 +393,68 @@ func validationCreatePromoRequest(req *generated.CreatePromoRequest) (dto.Create
 		dto.MaxUsageLimit = &maxUsageLimit
 	}
 
+	if req.MaxUsagePerUser != nil {
+		maxUsagePerUser := int(*req.MaxUsagePerUser)
+		dto.MaxUsagePerUser = &maxUsagePerUser
+	}
+
+	if req.MaxBudgetAmount != nil {
+		maxBudgetAmount := float64(*req.MaxBudgetAmount)
+		dto.MaxBudgetAmount = &maxBudgetAmount
+	}
+
+	if req.Code != nil {
+		dto.Code = req.Code
+	}
+
+	dto.CodeOnly = codeOnly
+	dto.Repeatable = repeatable
+
+	if req.MaxRepeat != nil {
+		maxRepeat := int(*req.MaxRepeat)
+		dto.MaxRepeat = &maxRepeat
+	}
+
+	if req.Exclusive != nil {
+		dto.Exclusive = *req.Exclusive
+	}
+
+	if req.StackGroup != nil {
+		dto.StackGroup = req.StackGroup
+	}
+
+	if req.Priority != nil {
+		dto.Priority = *req.Priority
+	}
+
+	dto.RolloutPercentage = req.RolloutPercentage
+
 	if req.Cities != nil {
-		dto.Cities = *req.Cities
+		dto.Cities = utils.NormalizeCities(*req.Cities)
+	}
+
+	if req.UserIds != nil {
+		dto.UserIds = uniqueUserIDs(*req.UserIds)
+	}
+
+	if req.ProductIds != nil {
+		seen := map[uint]bool{}
+		for _, productId := range *req.ProductIds {
+			if !seen[uint(productId)] {
+				seen[uint(productId)] = true
+				dto.ProductIds = append(dto.ProductIds, uint(productId))
+			}
+		}
+	}
+
+	if req.Timezone != nil {
+		dto.Timezone = *req.Timezone
 	}
 
+	dto.Schedules = schedules
+	dto.Tiers = tiers
+	dto.Variants = variants
+
 	return dto, nil
 }
 
@@ -121,7 +472,7 @@ func (s *Server) PostPromo(ctx echo.Context) error {
 		return ResponseError(ctx, customErr)
 	}
 
-	promoId, err := s.promoUsecase.CreatePromo(ctx.Request().Context(), dto)
+	promoId, err := s.promoUsecase.CreatePromo(actorContext(ctx), dto)
 	if err != nil {
 		return ResponseError(ctx, err)
 	}
@@ -163,7 +514,7 @@ func (s *Server) PostPromoIdExtend(ctx echo.Context, id int) error {
 
 	dto.ID = uint(id)
 
-	err = s.promoUsecase.ExtendPromo(ctx.Request().Context(), dto)
+	err = s.promoUsecase.ExtendPromo(actorContext(ctx), dto)
 	if err != nil {
 		return ResponseError(ctx, err)
 	}
@@ -171,6 +522,427 @@ func (s *Server) PostPromoIdExtend(ctx echo.Context, id int) error {
 	return ctx.JSON(http.StatusOK, utils.NewResponse("promo extended", nil, nil))
 }
 
+// GetPromoId implements generated.ServerInterface.
+func (s *Server) GetPromoId(ctx echo.Context, id int) error {
+	promo, err := s.promoUsecase.GetPromoDetail(ctx.Request().Context(), uint(id))
+	if err != nil {
+		return ResponseError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, utils.NewResponse("promo detail", promo, nil))
+}
+
+func validationUpdatePromoRequest(req *generated.UpdatePromoRequest) (dto.UpdatePromoInput, error) {
+	err := validation.ValidateStruct(
+		req,
+		// name if it exists not empty
+		validation.Field(&req.Name, validation.When(req.Name != nil, validation.Required)),
+		// minOrderAmount if it exists greater than 0
+		validation.Field(&req.MinOrderAmount, validation.When(req.MinOrderAmount != nil, validation.Required, validation.Min(float32(1)))),
+		// discountValue if it exists greater than 0
+		validation.Field(&req.DiscountValue, validation.When(req.DiscountValue != nil, validation.Required, validation.Min(float32(1)))),
+		// maxDiscountAmount if it exists greater than 0
+		validation.Field(&req.MaxDiscountAmount, validation.When(req.MaxDiscountAmount != nil, validation.Required, validation.Min(float32(1)))),
+		// maxUsageLimit if it exists greater than 0
+		validation.Field(&req.MaxUsageLimit, validation.When(req.MaxUsageLimit != nil, validation.Min(1))),
+		// maxUsagePerUser if it exists greater than 0
+		validation.Field(&req.MaxUsagePerUser, validation.When(req.MaxUsagePerUser != nil, validation.Min(1))),
+		// maxBudgetAmount if it exists greater than 0
+		validation.Field(&req.MaxBudgetAmount, validation.When(req.MaxBudgetAmount != nil, validation.Min(float32(1)))),
+		// stackGroup if it exists not empty
+		validation.Field(&req.StackGroup, validation.When(req.StackGroup != nil, validation.Required, validation.Length(1, 50))),
+		// rolloutPercentage if it exists between 1 and 100
+		validation.Field(&req.RolloutPercentage, validation.When(req.RolloutPercentage != nil, validation.Min(1), validation.Max(100))),
+	)
+	if err != nil {
+		return dto.UpdatePromoInput{}, err
+	}
+
+	dto := dto.UpdatePromoInput{
+		Name:              req.Name,
+		Description:       req.Description,
+		MaxUsageLimit:     req.MaxUsageLimit,
+		MaxUsagePerUser:   req.MaxUsagePerUser,
+		Exclusive:         req.Exclusive,
+		StackGroup:        req.StackGroup,
+		Priority:          req.Priority,
+		RolloutPercentage: req.RolloutPercentage,
+	}
+
+	if req.MinOrderAmount != nil {
+		minOrderAmount := float64(*req.MinOrderAmount)
+		dto.MinOrderAmount = &minOrderAmount
+	}
+
+	if req.DiscountValue != nil {
+		discountValue := float64(*req.DiscountValue)
+		dto.DiscountValue = &discountValue
+	}
+
+	if req.MaxDiscountAmount != nil {
+		maxDiscountAmount := float64(*req.MaxDiscountAmount)
+		dto.MaxDiscountAmount = &maxDiscountAmount
+	}
+
+	if req.MaxBudgetAmount != nil {
+		maxBudgetAmount := float64(*req.MaxBudgetAmount)
+		dto.MaxBudgetAmount = &maxBudgetAmount
+	}
+
+	return dto, nil
+}
+
+// PatchPromoId implements generated.ServerInterface.
+func (s *Server) PatchPromoId(ctx echo.Context, id int) error {
+	req := generated.UpdatePromoRequest{}
+	if err := ctx.Bind(&req); err != nil {
+		customError := utils.NewCustomError(err.Error(), nil, http.StatusBadRequest)
+		return ResponseError(ctx, customError)
+	}
+
+	dto, err := validationUpdatePromoRequest(&req)
+	if err != nil {
+		customErr := utils.NewCustomError("validation error", err, http.StatusBadRequest)
+		return ResponseError(ctx, customErr)
+	}
+
+	dto.ID = uint(id)
+
+	if err := s.promoUsecase.UpdatePromo(actorContext(ctx), dto); err != nil {
+		return ResponseError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, utils.NewResponse("promo updated", nil, nil))
+}
+
+// PostPromoIdSubmit implements generated.ServerInterface.
+func (s *Server) PostPromoIdSubmit(ctx echo.Context, id int) error {
+	if err := s.promoUsecase.SubmitPromo(actorContext(ctx), uint(id)); err != nil {
+		return ResponseError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, utils.NewResponse("promo submitted", nil, nil))
+}
+
+// actorContext is the request context telling who makes the change, from the
+// optional X-Actor header, for the promo history.
+func actorContext(ctx echo.Context) context.Context {
+	actor := strings.TrimSpace(ctx.Request().Header.Get("X-Actor"))
+	if actor == "" {
+		return ctx.Request().Context()
+	}
+
+	return utils.WithActor(ctx.Request().Context(), actor)
+}
+
+func validationActor(actor string) (string, error) {
+	actor = strings.TrimSpace(actor)
+	err := validation.Errors{
+		// X-Actor required
+		"X-Actor": validation.Validate(actor, validation.Required, validation.Length(1, 255)),
+	}.Filter()
+
+	return actor, err
+}
+
+// PostPromoIdApprove implements generated.ServerInterface.
+func (s *Server) PostPromoIdApprove(ctx echo.Context, id int, params generated.PostPromoIdApproveParams) error {
+	actor, err := validationActor(params.XActor)
+	if err != nil {
+		customErr := utils.NewCustomError("validation error", err, http.StatusBadRequest)
+		return ResponseError(ctx, customErr)
+	}
+
+	dto := dto.ReviewPromoInput{
+		PromoID: uint(id),
+		Actor:   actor,
+	}
+
+	if err := s.promoUsecase.ApprovePromo(ctx.Request().Context(), dto); err != nil {
+		return ResponseError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, utils.NewResponse("promo approved", nil, nil))
+}
+
+// PostPromoIdReject implements generated.ServerInterface.
+func (s *Server) PostPromoIdReject(ctx echo.Context, id int, params generated.PostPromoIdRejectParams) error {
+	req := generated.RejectPromoRequest{}
+	if ctx.Request().ContentLength != 0 {
+		if err := ctx.Bind(&req); err != nil {
+			customError := utils.NewCustomError(err.Error(), nil, http.StatusBadRequest)
+			return ResponseError(ctx, customError)
+		}
+	}
+
+	actor, err := validationActor(params.XActor)
+	if err != nil {
+		customErr := utils.NewCustomError("validation error", err, http.StatusBadRequest)
+		return ResponseError(ctx, customErr)
+	}
+
+	dto := dto.ReviewPromoInput{
+		PromoID: uint(id),
+		Actor:   actor,
+	}
+	if req.Reason != nil && strings.TrimSpace(*req.Reason) != "" {
+		reason := strings.TrimSpace(*req.Reason)
+		dto.Reason = &reason
+	}
+
+	if err := s.promoUsecase.RejectPromo(ctx.Request().Context(), dto); err != nil {
+		return ResponseError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, utils.NewResponse("promo rejected", nil, nil))
+}
+
+// PostPromoIdPause implements generated.ServerInterface.
+func (s *Server) PostPromoIdPause(ctx echo.Context, id int) error {
+	if err := s.promoUsecase.PausePromo(actorContext(ctx), uint(id)); err != nil {
+		return ResponseError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, utils.NewResponse("promo paused", nil, nil))
+}
+
+// PostPromoIdResume implements generated.ServerInterface.
+func (s *Server) PostPromoIdResume(ctx echo.Context, id int) error {
+	if err := s.promoUsecase.ResumePromo(actorContext(ctx), uint(id)); err != nil {
+		return ResponseError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, utils.NewResponse("promo resumed", nil, nil))
+}
+
+// PostPromoIdEnd implements generated.ServerInterface.
+func (s *Server) PostPromoIdEnd(ctx echo.Context, id int) error {
+	if err := s.promoUsecase.EndPromo(actorContext(ctx), uint(id)); err != nil {
+		return ResponseError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, utils.NewResponse("promo ended", nil, nil))
+}
+
+// PostPromoIdArchive implements generated.ServerInterface.
+func (s *Server) PostPromoIdArchive(ctx echo.Context, id int) error {
+	if err := s.promoUsecase.ArchivePromo(actorContext(ctx), uint(id)); err != nil {
+		return ResponseError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, utils.NewResponse("promo archived", nil, nil))
+}
+
+// GetPromoIdHistory implements generated.ServerInterface.
+func (s *Server) GetPromoIdHistory(ctx echo.Context, id int, params generated.GetPromoIdHistoryParams) error {
+	dto := dto.GetPromoHistoryInput{
+		PromoID: uint(id),
+		Page:    1,
+		PerPage: 10,
+	}
+	if params.Page != nil && *params.Page > 0 {
+		dto.Page = *params.Page
+	}
+	if params.PerPage != nil && *params.PerPage > 0 {
+		dto.PerPage = *params.PerPage
+	}
+
+	logs, total, err := s.promoUsecase.GetPromoHistory(ctx.Request().Context(), dto)
+	if err != nil {
+		return ResponseError(ctx, err)
+	}
+
+	meta := utils.BuildMeta(dto.Page, dto.PerPage, int(total))
+
+	return ctx.JSON(http.StatusOK, utils.NewResponse("promo history", logs, meta))
+}
+
+// GetPromoIdCities implements generated.ServerInterface.
+func (s *Server) GetPromoIdCities(ctx echo.Context, id int) error {
+	cities, err := s.promoUsecase.GetPromoCities(ctx.Request().Context(), uint(id))
+	if err != nil {
+		return ResponseError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, utils.NewResponse("promo cities", cityNames(cities), nil))
+}
+
+func validationAddPromoCitiesRequest(req *generated.AddPromoCitiesRequest) (dto.AddPromoCitiesInput, error) {
+	err := validation.ValidateStruct(
+		req,
+		// cities required, not empty and no blank city
+		validation.Field(&req.Cities, validation.Required, validation.Each(validation.By(notBlankCity))),
+	)
+	if err != nil {
+		return dto.AddPromoCitiesInput{}, err
+	}
+
+	return dto.AddPromoCitiesInput{
+		Cities: utils.NormalizeCities(req.Cities),
+	}, nil
+}
+
+// PostPromoIdCities implements generated.ServerInterface.
+func (s *Server) PostPromoIdCities(ctx echo.Context, id int) error {
+	req := generated.AddPromoCitiesRequest{}
+	if err := ctx.Bind(&req); err != nil {
+		customError := utils.NewCustomError(err.Error(), nil, http.StatusBadRequest)
+		return ResponseError(ctx, customError)
+	}
+
+	dto, err := validationAddPromoCitiesRequest(&req)
+	if err != nil {
+		customErr := utils.NewCustomError("validation error", err, http.StatusBadRequest)
+		return ResponseError(ctx, customErr)
+	}
+
+	dto.PromoID = uint(id)
+
+	cities, err := s.promoUsecase.AddPromoCities(actorContext(ctx), dto)
+	if err != nil {
+		return ResponseError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, utils.NewResponse("promo cities added", cityNames(cities), nil))
+}
+
+// DeletePromoIdCitiesCity implements generated.ServerInterface.
+func (s *Server) DeletePromoIdCitiesCity(ctx echo.Context, id int, city string) error {
+	city = utils.NormalizeCity(city)
+	if city == "" {
+		customErr := utils.NewCustomError("validation error", map[string]string{"city": "cannot be blank"}, http.StatusBadRequest)
+		return ResponseError(ctx, customErr)
+	}
+
+	err := s.promoUsecase.RemovePromoCity(actorContext(ctx), dto.RemovePromoCityInput{
+		PromoID: uint(id),
+		City:    city,
+	})
+	if err != nil {
+		return ResponseError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, utils.NewResponse("promo city removed", nil, nil))
+}
+
+func cityNames(cities []models.PromoCity) []string {
+	names := make([]string, len(cities))
+	for i, city := range cities {
+		names[i] = city.City
+	}
+
+	return names
+}
+
+func validationListPromosRequest(req *generated.GetPromosParams) (dto.ListPromosInput, error) {
+	err := validation.ValidateStruct(
+		req,
+		// status if it exists check enum
+		validation.Field(&req.Status, validation.When(req.Status != nil, validation.In(
+			generated.GetPromosParamsStatusDRAFT, generated.GetPromosParamsStatusPENDINGAPPROVAL, generated.GetPromosParamsStatusAPPROVED,
+			generated.GetPromosParamsStatusREJECTED, generated.GetPromosParamsStatusPAUSED, generated.GetPromosParamsStatusENDED,
+			generated.GetPromosParamsStatusARCHIVED,
+		))),
+		// endDate if it exists not before startDate
+		validation.Field(&req.EndDate, validation.When(req.EndDate != nil && req.StartDate != nil, validation.By(func(value interface{}) error {
+			if req.EndDate.Before(*req.StartDate) {
+				return validation.NewError("validation_min_greater_equal_than_required", "must be no less than startDate")
+			}
+			return nil
+		}))),
+	)
+	if err != nil {
+		return dto.ListPromosInput{}, err
+	}
+
+	if req.Page == nil || (req.Page != nil && *req.Page < 1) {
+		page := 1
+		req.Page = &page
+	}
+
+	if req.PerPage == nil || (req.PerPage != nil && *req.PerPage < 1) {
+		perPage := 10
+		req.PerPage = &perPage
+	}
+
+	dto := dto.ListPromosInput{
+		Page:    *req.Page,
+		PerPage: *req.PerPage,
+	}
+
+	if req.Status != nil {
+		dto.Status = string(*req.Status)
+	}
+
+	if req.Type != nil {
+		dto.Type = *req.Type
+	}
+
+	if req.Segmentation != nil {
+		dto.Segmentation = *req.Segmentation
+	}
+
+	if req.StartDate != nil {
+		dto.StartDate = *req.StartDate
+	}
+
+	if req.EndDate != nil {
+		dto.EndDate = *req.EndDate
+	}
+
+	return dto, nil
+}
+
+// GetPromos implements generated.ServerInterface.
+func (s *Server) GetPromos(ctx echo.Context, params generated.GetPromosParams) error {
+	dto, err := validationListPromosRequest(&params)
+	if err != nil {
+		customError := utils.NewCustomError("validation error", err, http.StatusBadRequest)
+		return ResponseError(ctx, customError)
+	}
+
+	promos, total, err := s.promoUsecase.ListPromos(ctx.Request().Context(), dto)
+	if err != nil {
+		return ResponseError(ctx, err)
+	}
+
+	meta := utils.BuildMeta(dto.Page, dto.PerPage, int(total))
+
+	return ctx.JSON(http.StatusOK, utils.NewResponse("promo list", promos, meta))
+}
+
+func validationPromoEligibilityRequest(id int, req *generated.GetPromoIdEligibilityParams) (dto.PromoEligibilityInput, error) {
+	err := validation.ValidateStruct(
+		req,
+		validation.Field(&req.UserId, validation.Required),
+	)
+
+	if err != nil {
+		return dto.PromoEligibilityInput{}, err
+	}
+
+	return dto.PromoEligibilityInput{
+		PromoId: uint(id),
+		UserId:  uint(req.UserId),
+	}, nil
+}
+
+// GetPromoIdEligibility implements generated.ServerInterface.
+func (s *Server) GetPromoIdEligibility(ctx echo.Context, id int, params generated.GetPromoIdEligibilityParams) error {
+	dto, err := validationPromoEligibilityRequest(id, &params)
+	if err != nil {
+		customError := utils.NewCustomError("validation error", err, http.StatusBadRequest)
+		return ResponseError(ctx, customError)
+	}
+
+	eligibility, err := s.promoUsecase.ExplainEligibility(ctx.Request().Context(), dto)
+	if err != nil {
+		return ResponseError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, utils.NewResponse("promo eligibility", eligibility, nil))
+}
+
 func validationGetPromoRequest(req *generated.GetGetPromoParams) (dto.GetPromoInput, error) {
 	err := validation.ValidateStruct(
 		req,
@@ -215,3 +987,34 @@ func (s *Server) GetGetPromo(ctx echo.Context, params generated.GetGetPromoParam
 
 	return ctx.JSON(http.StatusOK, utils.NewResponse("promo list", promos, meta))
 }
+
+func validationRecommendPromoRequest(req *generated.GetGetPromoRecommendationParams) (dto.RecommendPromoInput, error) {
+	err := validation.ValidateStruct(
+		req,
+		validation.Field(&req.UserId, validation.Required),
+	)
+
+	if err != nil {
+		return dto.RecommendPromoInput{}, err
+	}
+
+	return dto.RecommendPromoInput{
+		UserId: uint(req.UserId),
+	}, nil
+}
+
+// GetGetPromoRecommendation implements generated.ServerInterface.
+func (s *Server) GetGetPromoRecommendation(ctx echo.Context, params generated.GetGetPromoRecommendationParams) error {
+	dto, err := validationRecommendPromoRequest(&params)
+	if err != nil {
+		customError := utils.NewCustomError("validation error", err, http.StatusBadRequest)
+		return ResponseError(ctx, customError)
+	}
+
+	recommendation, err := s.promoUsecase.RecommendPromos(ctx.Request().Context(), dto)
+	if err != nil {
+		return ResponseError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, utils.NewResponse("promo recommendation", recommendation, nil))
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"encoding/json"
+	"hangry/generated"
+	"reflect"
+	"testing"
+)
+
+func Test_validationCreatePromoRequest(t *testing.T) {
+	const (
+		cityPromo     = `{"name": "Jakarta Sale", "segmentation": "CITY", "type": "FIXED_AMOUNT_DISCOUNT", "discountValue": 5000, "startDate": "2024-06-01T00:00:00Z", "endDate": "2024-06-30T00:00:00Z", `
+		discountPromo = `{"name": "Payday Sale", "segmentation": "ALL", "type": "PERCENTAGE_DISCOUNT", "discountValue": 10, "startDate": "2024-06-01T00:00:00Z", "endDate": "2024-06-30T00:00:00Z", `
+	)
+
+	tests := []struct {
+		name           string
+		body           string
+		wantCities     []string
+		wantProductIds []uint
+		wantErr        bool
+	}{
+		{
+			name:       "cities cleaned up",
+			body:       cityPromo + `"cities": [" jakarta ", "Bandung"]}`,
+			wantCities: []string{"jakarta", "Bandung"},
+		},
+		{
+			name:    "city promo without cities",
+			body:    cityPromo + `"cities": []}`,
+			wantErr: true,
+		},
+		{
+			name:    "blank city",
+			body:    cityPromo + `"cities": [" "]}`,
+			wantErr: true,
+		},
+		{
+			name:    "blank city among others",
+			body:    cityPromo + `"cities": ["Jakarta", ""]}`,
+			wantErr: true,
+		},
+		{
+			name:    "zero max discount amount",
+			body:    discountPromo + `"maxDiscountAmount": 0}`,
+			wantErr: true,
+		},
+		{
+			name:           "product ids",
+			body:           discountPromo + `"productIds": [4, 7]}`,
+			wantProductIds: []uint{4, 7},
+		},
+		{
+			name:    "zero product id",
+			body:    discountPromo + `"productIds": [0]}`,
+			wantErr: true,
+		},
+		{
+			name:    "empty product ids",
+			body:    discountPromo + `"productIds": []}`,
+			wantErr: true,
+		},
+		{
+			name:    "zero user id",
+			body:    `{"name": "VIP Sale", "segmentation": "USER_LIST", "type": "FIXED_AMOUNT_DISCOUNT", "discountValue": 5000, "startDate": "2024-06-01T00:00:00Z", "endDate": "2024-06-30T00:00:00Z", "userIds": [3, 0]}`,
+			wantErr: true,
+		},
+		{
+			name: "without cities",
+			body: discountPromo + `"maxDiscountAmount": 10000}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := generated.CreatePromoRequest{}
+			if err := json.Unmarshal([]byte(tt.body), &req); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := validationCreatePromoRequest(&req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validationCreatePromoRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got.Cities, tt.wantCities) {
+				t.Errorf("validationCreatePromoRequest() cities = %v, want %v", got.Cities, tt.wantCities)
+			}
+			if !reflect.DeepEqual(got.ProductIds, tt.wantProductIds) {
+				t.Errorf("validationCreatePromoRequest() product ids = %v, want %v", got.ProductIds, tt.wantProductIds)
+			}
+		})
+	}
+}
+
+func Test_validationUpdatePromoRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{
+			name: "amounts",
+			body: `{"minOrderAmount": 50000, "discountValue": 10, "maxDiscountAmount": 20000}`,
+		},
+		{
+			name:    "zero min order amount",
+			body:    `{"minOrderAmount": 0}`,
+			wantErr: true,
+		},
+		{
+			name:    "zero max discount amount",
+			body:    `{"maxDiscountAmount": 0}`,
+			wantErr: true,
+		},
+		{
+			name:    "zero discount value",
+			body:    `{"discountValue": 0}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := generated.UpdatePromoRequest{}
+			if err := json.Unmarshal([]byte(tt.body), &req); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := validationUpdatePromoRequest(&req); (err != nil) != tt.wantErr {
+				t.Errorf("validationUpdatePromoRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
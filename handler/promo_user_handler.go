@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"hangry/domain/dto"
+	"hangry/generated"
+	"hangry/utils"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/labstack/echo/v4"
+)
+
+// maxPromoUsersPerRequest keeps one bulk change to a size a single
+// transaction handles comfortably.
+const maxPromoUsersPerRequest = 10000
+
+// uniqueUserIDs drops the repeated user IDs, keeping the first of each.
+func uniqueUserIDs(userIDs []int) []uint {
+	unique := make([]uint, 0, len(userIDs))
+	seen := map[int]bool{}
+	for _, userID := range userIDs {
+		if !seen[userID] {
+			seen[userID] = true
+			unique = append(unique, uint(userID))
+		}
+	}
+
+	return unique
+}
+
+func validationPromoUsersRequest(req *generated.PromoUsersRequest) (dto.PromoUsersInput, error) {
+	err := validation.ValidateStruct(
+		req,
+		// userIds required, not too many and each greater than 0
+		validation.Field(&req.UserIds, validation.Required, validation.Length(1, maxPromoUsersPerRequest), validation.Each(validation.Required, validation.Min(1))),
+	)
+	if err != nil {
+		return dto.PromoUsersInput{}, err
+	}
+
+	return dto.PromoUsersInput{
+		UserIds: uniqueUserIDs(req.UserIds),
+	}, nil
+}
+
+// readUserIDsCSV reads one user ID from the first column of every line. The
+// first line is skipped when it is not a number, so files with a header work.
+func readUserIDsCSV(r io.Reader) ([]int, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var userIDs []int
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		value := strings.TrimSpace(record[0])
+		if value == "" {
+			continue
+		}
+
+		userID, err := strconv.Atoi(value)
+		if err != nil {
+			if line == 1 {
+				continue
+			}
+			return nil, fmt.Errorf("line %d: %q is not a user id", line, value)
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	return userIDs, nil
+}
+
+// GetPromoIdUsers implements generated.ServerInterface.
+func (s *Server) GetPromoIdUsers(ctx echo.Context, id int, params generated.GetPromoIdUsersParams) error {
+	dto := dto.GetPromoUsersInput{
+		PromoID: uint(id),
+		Page:    1,
+		PerPage: 10,
+	}
+	if params.Page != nil && *params.Page > 0 {
+		dto.Page = *params.Page
+	}
+	if params.PerPage != nil && *params.PerPage > 0 {
+		dto.PerPage = *params.PerPage
+	}
+
+	users, total, err := s.promoUsecase.GetPromoUsers(ctx.Request().Context(), dto)
+	if err != nil {
+		return ResponseError(ctx, err)
+	}
+
+	userIDs := make([]uint, len(users))
+	for i, user := range users {
+		userIDs[i] = user.UserID
+	}
+
+	meta := utils.BuildMeta(dto.Page, dto.PerPage, int(total))
+
+	return ctx.JSON(http.StatusOK, utils.NewResponse("promo users", userIDs, meta))
+}
+
+// PostPromoIdUsers implements generated.ServerInterface.
+func (s *Server) PostPromoIdUsers(ctx echo.Context, id int) error {
+	req := generated.PromoUsersRequest{}
+	if err := ctx.Bind(&req); err != nil {
+		customError := utils.NewCustomError(err.Error(), nil, http.StatusBadRequest)
+		return ResponseError(ctx, customError)
+	}
+
+	dto, err := validationPromoUsersRequest(&req)
+	if err != nil {
+		customErr := utils.NewCustomError("validation error", err, http.StatusBadRequest)
+		return ResponseError(ctx, customErr)
+	}
+
+	dto.PromoID = uint(id)
+
+	return s.changePromoUsers(ctx, dto, generated.Add)
+}
+
+// PostPromoIdUsersRemove implements generated.ServerInterface.
+func (s *Server) PostPromoIdUsersRemove(ctx echo.Context, id int) error {
+	req := generated.PromoUsersRequest{}
+	if err := ctx.Bind(&req); err != nil {
+		customError := utils.NewCustomError(err.Error(), nil, http.StatusBadRequest)
+		return ResponseError(ctx, customError)
+	}
+
+	dto, err := validationPromoUsersRequest(&req)
+	if err != nil {
+		customErr := utils.NewCustomError("validation error", err, http.StatusBadRequest)
+		return ResponseError(ctx, customErr)
+	}
+
+	dto.PromoID = uint(id)
+
+	return s.changePromoUsers(ctx, dto, generated.Remove)
+}
+
+// PostPromoIdUsersCsv implements generated.ServerInterface.
+func (s *Server) PostPromoIdUsersCsv(ctx echo.Context, id int, params generated.PostPromoIdUsersCsvParams) error {
+	if params.Action != generated.Add && params.Action != generated.Remove {
+		customErr := utils.NewCustomError("validation error", map[string]string{"action": "must be a valid value"}, http.StatusBadRequest)
+		return ResponseError(ctx, customErr)
+	}
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		customErr := utils.NewCustomError("validation error", map[string]string{"file": "cannot be blank"}, http.StatusBadRequest)
+		return ResponseError(ctx, customErr)
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return ResponseError(ctx, utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError))
+	}
+	defer file.Close()
+
+	userIDs, err := readUserIDsCSV(file)
+	if err != nil {
+		customErr := utils.NewCustomError("validation error", map[string]string{"file": err.Error()}, http.StatusBadRequest)
+		return ResponseError(ctx, customErr)
+	}
+
+	dto, err := validationPromoUsersRequest(&generated.PromoUsersRequest{UserIds: userIDs})
+	if err != nil {
+		customErr := utils.NewCustomError("validation error", err, http.StatusBadRequest)
+		return ResponseError(ctx, customErr)
+	}
+
+	dto.PromoID = uint(id)
+
+	return s.changePromoUsers(ctx, dto, params.Action)
+}
+
+func (s *Server) changePromoUsers(ctx echo.Context, dto dto.PromoUsersInput, action generated.PostPromoIdUsersCsvParamsAction) error {
+	change, message := s.promoUsecase.AddPromoUsers, "promo users added"
+	if action == generated.Remove {
+		change, message = s.promoUsecase.RemovePromoUsers, "promo users removed"
+	}
+
+	changed, err := change(actorContext(ctx), dto)
+	if err != nil {
+		return ResponseError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, utils.NewResponse(message, map[string]interface{}{
+		"requested": len(dto.UserIds),
+		"changed":   changed,
+	}, nil))
+}
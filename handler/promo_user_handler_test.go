@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"hangry/constants"
+	"hangry/domain/models"
+	"hangry/generated"
+	repo_mock "hangry/repository/mocks"
+	"hangry/usecase"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+func Test_validationPromoUsersRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    []uint
+		wantErr bool
+	}{
+		{
+			name: "duplicates dropped",
+			body: `{"userIds": [3, 5, 3]}`,
+			want: []uint{3, 5},
+		},
+		{
+			name:    "empty",
+			body:    `{"userIds": []}`,
+			wantErr: true,
+		},
+		{
+			name:    "zero user id",
+			body:    `{"userIds": [3, 0]}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := generated.PromoUsersRequest{}
+			if err := json.Unmarshal([]byte(tt.body), &req); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := validationPromoUsersRequest(&req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validationPromoUsersRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got.UserIds, tt.want) {
+				t.Errorf("validationPromoUsersRequest() = %v, want %v", got.UserIds, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_PostPromoIdUsers_Actor(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		action    string
+		post      func(s *Server, ctx echo.Context) error
+		promoRepo func(r *repo_mock.MockPromoRepository)
+	}{
+		{
+			name:   "add users",
+			path:   "/promo/1/users",
+			action: constants.PROMOAUDITACTIONADDUSERS,
+			post: func(s *Server, ctx echo.Context) error {
+				return s.PostPromoIdUsers(ctx, 1)
+			},
+			promoRepo: func(r *repo_mock.MockPromoRepository) {
+				gomock.InOrder(
+					r.EXPECT().FindUsers(gomock.Any(), nil, uint(1), []uint{2}).Return([]models.PromoUser{}, nil),
+					r.EXPECT().SaveUsers(gomock.Any(), nil, uint(1), []uint{2}).Return(int64(1), nil),
+					r.EXPECT().FindUsers(gomock.Any(), nil, uint(1), []uint{2}).Return([]models.PromoUser{{ID: 1, PromoID: 1, UserID: 2}}, nil),
+				)
+			},
+		},
+		{
+			name:   "remove users",
+			path:   "/promo/1/users/remove",
+			action: constants.PROMOAUDITACTIONREMOVEUSERS,
+			post: func(s *Server, ctx echo.Context) error {
+				return s.PostPromoIdUsersRemove(ctx, 1)
+			},
+			promoRepo: func(r *repo_mock.MockPromoRepository) {
+				gomock.InOrder(
+					r.EXPECT().FindUsers(gomock.Any(), nil, uint(1), []uint{2}).Return([]models.PromoUser{{ID: 1, PromoID: 1, UserID: 2}}, nil),
+					r.EXPECT().RemoveUsers(gomock.Any(), nil, uint(1), []uint{2}).Return(int64(1), nil),
+					r.EXPECT().FindUsers(gomock.Any(), nil, uint(1), []uint{2}).Return([]models.PromoUser{}, nil),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			transactionRepo := repo_mock.NewMockTransactionRepository(ctrl)
+			transactionRepo.EXPECT().Execute(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+					return fn(nil)
+				})
+
+			promoRepo := repo_mock.NewMockPromoRepository(ctrl)
+			promoRepo.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).
+				Return(models.Promo{ID: 1, Segmentation: constants.PROMOSEGMENTATIONUSERLIST}, nil)
+			tt.promoRepo(promoRepo)
+			promoRepo.EXPECT().SaveAuditLog(gomock.Any(), nil, gomock.Any()).
+				DoAndReturn(func(ctx context.Context, tx *gorm.DB, log *models.PromoAuditLog) error {
+					if log.Action != tt.action || log.Actor == nil || *log.Actor != "jane@hangry.id" {
+						t.Errorf("audit log = %+v, want %s by jane@hangry.id", log, tt.action)
+					}
+					return nil
+				})
+
+			s := &Server{promoUsecase: usecase.NewPromoUsecase(promoRepo, transactionRepo, nil, nil, nil, 0)}
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(`{"userIds": [2]}`))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set("X-Actor", "jane@hangry.id")
+			rec := httptest.NewRecorder()
+
+			if err := tt.post(s, echo.New().NewContext(req, rec)); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != http.StatusOK {
+				t.Errorf("status = %v, want %v, body %s", rec.Code, http.StatusOK, rec.Body.String())
+			}
+		})
+	}
+}
@@ -8,9 +8,10 @@ import (
 )
 
 type Server struct {
-	cartUsecase  usecase.CartUsecase
-	promoUsecase usecase.PromoUsecase
-	orderUsecase usecase.OrderUsecase
+	cartUsecase    usecase.CartUsecase
+	promoUsecase   usecase.PromoUsecase
+	orderUsecase   usecase.OrderUsecase
+	voucherUsecase usecase.VoucherUsecase
 }
 
 // GetHealth implements generated.ServerInterface.
@@ -22,10 +23,12 @@ func NewServer(
 	cartUsecase usecase.CartUsecase,
 	promoUsecase usecase.PromoUsecase,
 	orderUsecase usecase.OrderUsecase,
+	voucherUsecase usecase.VoucherUsecase,
 ) generated.ServerInterface {
 	return &Server{
 		cartUsecase,
 		promoUsecase,
 		orderUsecase,
+		voucherUsecase,
 	}
 }
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"encoding/csv"
+	"fmt"
+	"hangry/domain/dto"
+	"hangry/generated"
+	"hangry/utils"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/labstack/echo/v4"
+)
+
+func validationGenerateVoucherRequest(req *generated.GenerateVoucherRequest) (dto.GenerateVoucherInput, error) {
+	// codes are matched case-insensitively, so they are stored uppercase
+	prefix := ""
+	if req.Prefix != nil {
+		prefix = strings.ToUpper(strings.TrimSpace(*req.Prefix))
+		req.Prefix = &prefix
+	}
+
+	err := validation.ValidateStruct(
+		req,
+		// count required and between 1 and 10000
+		validation.Field(&req.Count, validation.Required, validation.Min(1), validation.Max(10000)),
+		// prefix leaves room for the random part of the code
+		validation.Field(&req.Prefix, validation.When(req.Prefix != nil, validation.Length(0, 20), validation.Match(promoCodePattern))),
+	)
+
+	if err != nil {
+		return dto.GenerateVoucherInput{}, err
+	}
+
+	return dto.GenerateVoucherInput{
+		Count:  req.Count,
+		Prefix: prefix,
+	}, nil
+}
+
+// PostPromoIdVouchers implements generated.ServerInterface.
+func (s *Server) PostPromoIdVouchers(ctx echo.Context, id int) error {
+	req := generated.GenerateVoucherRequest{}
+	if err := ctx.Bind(&req); err != nil {
+		customError := utils.NewCustomError(err.Error(), nil, http.StatusBadRequest)
+		return ResponseError(ctx, customError)
+	}
+
+	dto, err := validationGenerateVoucherRequest(&req)
+	if err != nil {
+		customErr := utils.NewCustomError("validation error", err, http.StatusBadRequest)
+		return ResponseError(ctx, customErr)
+	}
+
+	dto.PromoId = uint(id)
+
+	count, err := s.voucherUsecase.GenerateVouchers(ctx.Request().Context(), dto)
+	if err != nil {
+		return ResponseError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, utils.NewResponse("vouchers generated", echo.Map{"promoId": id, "count": count}, nil))
+}
+
+// GetPromoIdVouchersExport implements generated.ServerInterface.
+func (s *Server) GetPromoIdVouchersExport(ctx echo.Context, id int) error {
+	vouchers, err := s.voucherUsecase.ExportVouchers(ctx.Request().Context(), uint(id))
+	if err != nil {
+		return ResponseError(ctx, err)
+	}
+
+	res := ctx.Response()
+	res.Header().Set(echo.HeaderContentType, "text/csv")
+	res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=promo-%d-vouchers.csv", id))
+	res.WriteHeader(http.StatusOK)
+
+	w := csv.NewWriter(res)
+	if err := w.Write([]string{"code", "redeemed_by_user_id", "redeemed_order_id", "redeemed_at"}); err != nil {
+		return err
+	}
+
+	for _, voucher := range vouchers {
+		row := []string{voucher.Code, "", "", ""}
+		if voucher.RedeemedByUserID != nil {
+			row[1] = strconv.FormatUint(uint64(*voucher.RedeemedByUserID), 10)
+		}
+		if voucher.RedeemedOrderID != nil {
+			row[2] = strconv.FormatUint(uint64(*voucher.RedeemedOrderID), 10)
+		}
+		if voucher.RedeemedAt != nil {
+			row[3] = voucher.RedeemedAt.Format(time.RFC3339)
+		}
+
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,305 @@
+package pricing
+
+import (
+	"hangry/constants"
+	"hangry/domain/models"
+	"math"
+)
+
+// Line is a single cart line to be priced.
+type Line struct {
+	ProductID uint
+	Price     float64
+	Quantity  int
+}
+
+// Profile is the customer the price is calculated for.
+type Profile struct {
+	UserID uint
+}
+
+type Input struct {
+	Lines   []Line
+	Profile Profile
+	// Promos are applied in the given order, each percentage and fixed amount
+	// discount is calculated from the total left by the promos before it.
+	Promos []models.Promo
+}
+
+type LineBreakdown struct {
+	ProductID uint
+	Price     float64
+	Quantity  int
+	Total     float64
+}
+
+type PromoBreakdown struct {
+	PromoID        uint
+	Name           string
+	Type           string
+	DiscountAmount float64
+	FreeProductID  *uint
+	FreeProductQty int
+	// ProductIDs are the cart products a product scoped discount was taken
+	// from, empty when the discount applies to the whole cart.
+	ProductIDs []uint
+	// TierID is the tier a tiered discount was applied with, nil when the cart
+	// reached none of them.
+	TierID *uint
+	// VariantID is the variant the customer is assigned to, nil when the promo
+	// has no variants.
+	VariantID *uint
+	// Cost is what the promo gives away, the discount plus the retail value of
+	// the free items. It is counted against the promo budget.
+	Cost float64
+}
+
+type Breakdown struct {
+	Lines         []LineBreakdown
+	Subtotal      float64
+	Promos        []PromoBreakdown
+	DiscountTotal float64
+	Total         float64
+}
+
+// LinesFromCart converts cart items into pricing lines. The items must have
+// their Product relation loaded.
+func LinesFromCart(cart models.Cart) []Line {
+	lines := make([]Line, 0, len(cart.CartItems))
+	for _, cartItem := range cart.CartItems {
+		lines = append(lines, Line{
+			ProductID: cartItem.ProductID,
+			Price:     cartItem.Product.Price,
+			Quantity:  cartItem.Quantity,
+		})
+	}
+
+	return lines
+}
+
+// Calculate prices the lines and applies the promos. It does not check
+// whether the promos are eligible, that is up to the caller.
+func Calculate(input Input) Breakdown {
+	breakdown := Breakdown{
+		Lines:  make([]LineBreakdown, 0, len(input.Lines)),
+		Promos: make([]PromoBreakdown, 0, len(input.Promos)),
+	}
+
+	for _, line := range input.Lines {
+		lineTotal := line.Price * float64(line.Quantity)
+		breakdown.Lines = append(breakdown.Lines, LineBreakdown{
+			ProductID: line.ProductID,
+			Price:     line.Price,
+			Quantity:  line.Quantity,
+			Total:     lineTotal,
+		})
+		breakdown.Subtotal += lineTotal
+	}
+
+	breakdown.Total = breakdown.Subtotal
+
+	for _, promo := range input.Promos {
+		// the customer gets the discount of their variant
+		promo = ApplyVariant(promo, input.Profile.UserID)
+
+		promoBreakdown := PromoBreakdown{
+			PromoID: promo.ID,
+			Name:    promo.Name,
+			Type:    promo.Type,
+		}
+		if variant, ok := AssignVariant(promo, input.Profile.UserID); ok {
+			variantID := variant.ID
+			promoBreakdown.VariantID = &variantID
+		}
+
+		switch promo.Type {
+		case constants.PROMOTYPEBUYXGETY:
+			promoBreakdown.FreeProductID = promo.FreeProductID
+			promoBreakdown.FreeProductQty = promo.FreeProductQty * rewardTimes(input.Lines, promo)
+		case constants.PROMOTYPEPERCENTAGE:
+			base := breakdown.Total
+			if len(promo.PromoProducts) > 0 {
+				var targetTotal float64
+				targetTotal, promoBreakdown.ProductIDs = TargetLines(input.Lines, promo)
+				// earlier discounts may have left less than the matching lines
+				if targetTotal < base {
+					base = targetTotal
+				}
+			}
+			promoBreakdown.DiscountAmount = percentageDiscount(base, promo)
+		case constants.PROMOTYPEFIXEDAMOUNT:
+			promoBreakdown.DiscountAmount = fixedAmountDiscount(breakdown.Total, promo)
+		case constants.PROMOTYPETIERED:
+			// the tier is picked by what the customer spends, the discount is
+			// taken from what is left after the promos before it
+			if tier, ok := SelectTier(promo.PromoTiers, breakdown.Subtotal); ok {
+				tierID := tier.ID
+				promoBreakdown.TierID = &tierID
+				promoBreakdown.DiscountAmount = tierDiscount(breakdown.Total, tier)
+			}
+		}
+
+		// the order that uses up the budget only gets what is left of it
+		if remaining, ok := remainingBudget(promo); ok {
+			limitToBudget(&promoBreakdown, promo, remaining)
+		}
+		promoBreakdown.Cost = roundSen(promoBreakdown.DiscountAmount + float64(promoBreakdown.FreeProductQty)*freeProductPrice(promo))
+
+		breakdown.Total -= promoBreakdown.DiscountAmount
+		breakdown.DiscountTotal += promoBreakdown.DiscountAmount
+		breakdown.Promos = append(breakdown.Promos, promoBreakdown)
+	}
+
+	return breakdown
+}
+
+// TargetLines returns the total and the product IDs of the lines a product
+// scoped promo applies to. A promo without products applies to every line.
+func TargetLines(lines []Line, promo models.Promo) (float64, []uint) {
+	targets := make(map[uint]bool, len(promo.PromoProducts))
+	for _, promoProduct := range promo.PromoProducts {
+		targets[promoProduct.ProductID] = true
+	}
+
+	total := 0.0
+	productIDs := []uint{}
+	for _, line := range lines {
+		if len(targets) > 0 && !targets[line.ProductID] {
+			continue
+		}
+		total += line.Price * float64(line.Quantity)
+		productIDs = append(productIDs, line.ProductID)
+	}
+
+	return total, productIDs
+}
+
+func percentageDiscount(total float64, promo models.Promo) float64 {
+	discount := total * promo.DiscountValue / 100
+	if discount > promo.MaxDiscountAmount {
+		discount = promo.MaxDiscountAmount
+	}
+
+	return discount
+}
+
+// rewardTimes is how many times a buy x get y reward is granted. A repeatable
+// promo rewards every full multiple of the buy quantity, up to its max repeat.
+func rewardTimes(lines []Line, promo models.Promo) int {
+	if !promo.IsRepeatable || promo.BuyProductID == nil || promo.BuyProductQty <= 0 {
+		return 1
+	}
+
+	quantity := 0
+	for _, line := range lines {
+		if line.ProductID == *promo.BuyProductID {
+			quantity += line.Quantity
+		}
+	}
+
+	times := quantity / promo.BuyProductQty
+	if promo.MaxRepeat != nil && times > *promo.MaxRepeat {
+		times = *promo.MaxRepeat
+	}
+
+	return times
+}
+
+// SelectTier returns the highest tier whose min amount is reached.
+func SelectTier(tiers []models.PromoTier, amount float64) (models.PromoTier, bool) {
+	var selected models.PromoTier
+	found := false
+	for _, tier := range tiers {
+		if amount >= tier.MinAmount && (!found || tier.MinAmount > selected.MinAmount) {
+			selected = tier
+			found = true
+		}
+	}
+
+	return selected, found
+}
+
+// tierDiscount is the discount of a tier, never more than what is left of the
+// total.
+func tierDiscount(total float64, tier models.PromoTier) float64 {
+	discount := tier.DiscountValue
+	if tier.DiscountType == constants.PROMOTIERPERCENTAGE {
+		discount = total * tier.DiscountValue / 100
+		if tier.MaxDiscountAmount != nil && discount > *tier.MaxDiscountAmount {
+			discount = *tier.MaxDiscountAmount
+		}
+	}
+
+	if discount > total {
+		return total
+	}
+
+	return discount
+}
+
+// fixedAmountDiscount takes the promo amount off, but never more than what is
+// left of the total.
+func fixedAmountDiscount(total float64, promo models.Promo) float64 {
+	if promo.DiscountValue > total {
+		return total
+	}
+
+	return promo.DiscountValue
+}
+
+// remainingBudget returns what is left of the promo budget, false when the
+// promo has none.
+func remainingBudget(promo models.Promo) (float64, bool) {
+	if promo.MaxBudgetAmount == nil {
+		return 0, false
+	}
+
+	// the subtraction of floats leaves crumbs below a sen, which the
+	// numeric columns of the reservation would not fit
+	remaining := roundSen(*promo.MaxBudgetAmount - promo.SpentAmount)
+	if remaining < 0 {
+		return 0, true
+	}
+
+	return remaining, true
+}
+
+// limitToBudget cuts the discount and the free items down to the remaining
+// budget. Free items can not be split, so only the ones that fit are kept.
+func limitToBudget(promoBreakdown *PromoBreakdown, promo models.Promo, remaining float64) {
+	if promoBreakdown.DiscountAmount > remaining {
+		promoBreakdown.DiscountAmount = remaining
+	}
+
+	price := freeProductPrice(promo)
+	if price > 0 && float64(promoBreakdown.FreeProductQty)*price > remaining {
+		promoBreakdown.FreeProductQty = int(remaining / price)
+	}
+}
+
+// BudgetExhausted reports whether the promo budget can not pay for anything
+// more. A budget left below the free product price is used up as well, the
+// promo could only be redeemed without giving its free item.
+func BudgetExhausted(promo models.Promo) bool {
+	remaining, ok := remainingBudget(promo)
+	if !ok {
+		return false
+	}
+
+	return remaining <= 0 || remaining < freeProductPrice(promo)
+}
+
+// roundSen rounds an amount to whole sen.
+func roundSen(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// freeProductPrice is the retail price of the promo free product, zero when
+// the FreeProduct relation is not loaded.
+func freeProductPrice(promo models.Promo) float64 {
+	if promo.FreeProduct == nil {
+		return 0
+	}
+
+	return promo.FreeProduct.Price
+}
@@ -0,0 +1,504 @@
+package pricing
+
+import (
+	"hangry/constants"
+	"hangry/domain/models"
+	"reflect"
+	"testing"
+)
+
+func TestCalculate(t *testing.T) {
+	buyProductId := uint(1)
+	freeProductId := uint(2)
+
+	promoBuyXGetY := models.Promo{
+		ID:             1,
+		Name:           "buy x get y",
+		Type:           constants.PROMOTYPEBUYXGETY,
+		BuyProductID:   &buyProductId,
+		FreeProductID:  &freeProductId,
+		BuyProductQty:  2,
+		FreeProductQty: 1,
+	}
+	promoDiscount := models.Promo{
+		ID:                2,
+		Name:              "discount",
+		Type:              constants.PROMOTYPEPERCENTAGE,
+		DiscountValue:     10,
+		MaxDiscountAmount: 5000,
+	}
+	variantID := uint(7)
+	variantMaxDiscount := float64(10000)
+	promoDiscountVariant := promoDiscount
+	promoDiscountVariant.PromoVariants = []models.PromoVariant{
+		{ID: variantID, PromoID: 2, Name: "B", Weight: 1, DiscountValue: 20, MaxDiscountAmount: &variantMaxDiscount},
+	}
+	promoDiscountCapped := models.Promo{
+		ID:                3,
+		Name:              "discount capped",
+		Type:              constants.PROMOTYPEPERCENTAGE,
+		DiscountValue:     50,
+		MaxDiscountAmount: 1000,
+	}
+	maxRepeat := 2
+	promoRepeatable := promoBuyXGetY
+	promoRepeatable.IsRepeatable = true
+	promoRepeatable.BuyProductQty = 2
+	promoRepeatableCapped := promoRepeatable
+	promoRepeatableCapped.MaxRepeat = &maxRepeat
+
+	repeatLines := []Line{
+		{ProductID: 1, Price: 10000, Quantity: 7},
+	}
+	repeatLineBreakdowns := []LineBreakdown{
+		{ProductID: 1, Price: 10000, Quantity: 7, Total: 70000},
+	}
+
+	promoFixedAmount := models.Promo{
+		ID:            4,
+		Name:          "fixed amount",
+		Type:          constants.PROMOTYPEFIXEDAMOUNT,
+		DiscountValue: 10000,
+	}
+	promoFixedAmountLarge := models.Promo{
+		ID:            5,
+		Name:          "fixed amount large",
+		Type:          constants.PROMOTYPEFIXEDAMOUNT,
+		DiscountValue: 50000,
+	}
+
+	promoScoped := models.Promo{
+		ID:                6,
+		Name:              "drinks",
+		Type:              constants.PROMOTYPEPERCENTAGE,
+		DiscountValue:     20,
+		MaxDiscountAmount: 50000,
+		PromoProducts:     []models.PromoProduct{{ProductID: 3}, {ProductID: 4}},
+	}
+	promoScopedMissing := promoScoped
+	promoScopedMissing.PromoProducts = []models.PromoProduct{{ProductID: 4}}
+
+	tierCap := 1500.0
+	promoTiered := models.Promo{
+		ID:   7,
+		Name: "tiered",
+		Type: constants.PROMOTYPETIERED,
+		PromoTiers: []models.PromoTier{
+			{ID: 1, MinAmount: 10000, DiscountType: constants.PROMOTIERFIXED, DiscountValue: 1000},
+			{ID: 3, MinAmount: 50000, DiscountType: constants.PROMOTIERPERCENTAGE, DiscountValue: 15},
+			{ID: 2, MinAmount: 20000, DiscountType: constants.PROMOTIERPERCENTAGE, DiscountValue: 10, MaxDiscountAmount: &tierCap},
+		},
+	}
+	budget := 30000.0
+	crumbsBudget := 100.0
+	promoBudget := promoFixedAmount
+	promoBudget.MaxBudgetAmount = &budget
+	promoBudget.SpentAmount = 24000
+	promoBuyXGetYBudget := promoRepeatable
+	promoBuyXGetYBudget.FreeProduct = &models.Product{ID: 2, Price: 2500}
+	promoBuyXGetYBudget.MaxBudgetAmount = &budget
+	promoBuyXGetYBudget.SpentAmount = 24000
+	promoBudgetCrumbs := promoFixedAmount
+	promoBudgetCrumbs.MaxBudgetAmount = &crumbsBudget
+	promoBudgetCrumbs.SpentAmount = 99.99
+
+	tierOne := uint(1)
+	tierTwo := uint(2)
+	tierThree := uint(3)
+
+	lines := []Line{
+		{ProductID: 1, Price: 10000, Quantity: 2},
+		{ProductID: 3, Price: 5000, Quantity: 1},
+	}
+	lineBreakdowns := []LineBreakdown{
+		{ProductID: 1, Price: 10000, Quantity: 2, Total: 20000},
+		{ProductID: 3, Price: 5000, Quantity: 1, Total: 5000},
+	}
+
+	tests := []struct {
+		name  string
+		input Input
+		want  Breakdown
+	}{
+		{
+			name:  "empty cart",
+			input: Input{},
+			want: Breakdown{
+				Lines:  []LineBreakdown{},
+				Promos: []PromoBreakdown{},
+			},
+		},
+		{
+			name: "without promo",
+			input: Input{
+				Lines: lines,
+			},
+			want: Breakdown{
+				Lines:    lineBreakdowns,
+				Subtotal: 25000,
+				Promos:   []PromoBreakdown{},
+				Total:    25000,
+			},
+		},
+		{
+			name: "buy x get y",
+			input: Input{
+				Lines:  lines,
+				Promos: []models.Promo{promoBuyXGetY},
+			},
+			want: Breakdown{
+				Lines:    lineBreakdowns,
+				Subtotal: 25000,
+				Promos: []PromoBreakdown{
+					{PromoID: 1, Name: "buy x get y", Type: constants.PROMOTYPEBUYXGETY, FreeProductID: &freeProductId, FreeProductQty: 1},
+				},
+				Total: 25000,
+			},
+		},
+		{
+			name: "percentage discount",
+			input: Input{
+				Lines:  lines,
+				Promos: []models.Promo{promoDiscount},
+			},
+			want: Breakdown{
+				Lines:    lineBreakdowns,
+				Subtotal: 25000,
+				Promos: []PromoBreakdown{
+					{PromoID: 2, Name: "discount", Type: constants.PROMOTYPEPERCENTAGE, DiscountAmount: 2500, Cost: 2500},
+				},
+				DiscountTotal: 2500,
+				Total:         22500,
+			},
+		},
+		{
+			name: "percentage discount capped by max discount amount",
+			input: Input{
+				Lines:  lines,
+				Promos: []models.Promo{promoDiscountCapped},
+			},
+			want: Breakdown{
+				Lines:    lineBreakdowns,
+				Subtotal: 25000,
+				Promos: []PromoBreakdown{
+					{PromoID: 3, Name: "discount capped", Type: constants.PROMOTYPEPERCENTAGE, DiscountAmount: 1000, Cost: 1000},
+				},
+				DiscountTotal: 1000,
+				Total:         24000,
+			},
+		},
+		{
+			name: "percentage discounts apply on the remaining total",
+			input: Input{
+				Lines:  lines,
+				Promos: []models.Promo{promoDiscountCapped, promoDiscount, promoBuyXGetY},
+			},
+			want: Breakdown{
+				Lines:    lineBreakdowns,
+				Subtotal: 25000,
+				Promos: []PromoBreakdown{
+					{PromoID: 3, Name: "discount capped", Type: constants.PROMOTYPEPERCENTAGE, DiscountAmount: 1000, Cost: 1000},
+					{PromoID: 2, Name: "discount", Type: constants.PROMOTYPEPERCENTAGE, DiscountAmount: 2400, Cost: 2400},
+					{PromoID: 1, Name: "buy x get y", Type: constants.PROMOTYPEBUYXGETY, FreeProductID: &freeProductId, FreeProductQty: 1},
+				},
+				DiscountTotal: 3400,
+				Total:         21600,
+			},
+		},
+		{
+			name: "buy x get y is granted once when not repeatable",
+			input: Input{
+				Lines:  repeatLines,
+				Promos: []models.Promo{promoBuyXGetY},
+			},
+			want: Breakdown{
+				Lines:    repeatLineBreakdowns,
+				Subtotal: 70000,
+				Promos: []PromoBreakdown{
+					{PromoID: 1, Name: "buy x get y", Type: constants.PROMOTYPEBUYXGETY, FreeProductID: &freeProductId, FreeProductQty: 1},
+				},
+				Total: 70000,
+			},
+		},
+		{
+			name: "repeatable buy x get y rewards every multiple",
+			input: Input{
+				Lines:  repeatLines,
+				Promos: []models.Promo{promoRepeatable},
+			},
+			want: Breakdown{
+				Lines:    repeatLineBreakdowns,
+				Subtotal: 70000,
+				Promos: []PromoBreakdown{
+					{PromoID: 1, Name: "buy x get y", Type: constants.PROMOTYPEBUYXGETY, FreeProductID: &freeProductId, FreeProductQty: 3},
+				},
+				Total: 70000,
+			},
+		},
+		{
+			name: "repeatable buy x get y capped by max repeat",
+			input: Input{
+				Lines:  repeatLines,
+				Promos: []models.Promo{promoRepeatableCapped},
+			},
+			want: Breakdown{
+				Lines:    repeatLineBreakdowns,
+				Subtotal: 70000,
+				Promos: []PromoBreakdown{
+					{PromoID: 1, Name: "buy x get y", Type: constants.PROMOTYPEBUYXGETY, FreeProductID: &freeProductId, FreeProductQty: 2},
+				},
+				Total: 70000,
+			},
+		},
+		{
+			name: "product scoped percentage discount",
+			input: Input{
+				Lines:  lines,
+				Promos: []models.Promo{promoScoped},
+			},
+			want: Breakdown{
+				Lines:    lineBreakdowns,
+				Subtotal: 25000,
+				Promos: []PromoBreakdown{
+					{PromoID: 6, Name: "drinks", Type: constants.PROMOTYPEPERCENTAGE, DiscountAmount: 1000, Cost: 1000, ProductIDs: []uint{3}},
+				},
+				DiscountTotal: 1000,
+				Total:         24000,
+			},
+		},
+		{
+			name: "product scoped percentage discount never exceeds the remaining total",
+			input: Input{
+				Lines:  lines,
+				Promos: []models.Promo{promoFixedAmountLarge, promoScoped},
+			},
+			want: Breakdown{
+				Lines:    lineBreakdowns,
+				Subtotal: 25000,
+				Promos: []PromoBreakdown{
+					{PromoID: 5, Name: "fixed amount large", Type: constants.PROMOTYPEFIXEDAMOUNT, DiscountAmount: 25000, Cost: 25000},
+					{PromoID: 6, Name: "drinks", Type: constants.PROMOTYPEPERCENTAGE, DiscountAmount: 0, ProductIDs: []uint{3}},
+				},
+				DiscountTotal: 25000,
+				Total:         0,
+			},
+		},
+		{
+			name: "product scoped percentage discount without matching lines",
+			input: Input{
+				Lines:  lines,
+				Promos: []models.Promo{promoScopedMissing},
+			},
+			want: Breakdown{
+				Lines:    lineBreakdowns,
+				Subtotal: 25000,
+				Promos: []PromoBreakdown{
+					{PromoID: 6, Name: "drinks", Type: constants.PROMOTYPEPERCENTAGE, DiscountAmount: 0, ProductIDs: []uint{}},
+				},
+				Total: 25000,
+			},
+		},
+		{
+			name: "tiered discount applies the highest tier reached",
+			input: Input{
+				Lines:  lines,
+				Promos: []models.Promo{promoTiered},
+			},
+			want: Breakdown{
+				Lines:    lineBreakdowns,
+				Subtotal: 25000,
+				Promos: []PromoBreakdown{
+					{PromoID: 7, Name: "tiered", Type: constants.PROMOTYPETIERED, DiscountAmount: 1500, Cost: 1500, TierID: &tierTwo},
+				},
+				DiscountTotal: 1500,
+				Total:         23500,
+			},
+		},
+		{
+			name: "tiered discount with a fixed tier",
+			input: Input{
+				Lines:  []Line{{ProductID: 1, Price: 15000, Quantity: 1}},
+				Promos: []models.Promo{promoTiered},
+			},
+			want: Breakdown{
+				Lines:    []LineBreakdown{{ProductID: 1, Price: 15000, Quantity: 1, Total: 15000}},
+				Subtotal: 15000,
+				Promos: []PromoBreakdown{
+					{PromoID: 7, Name: "tiered", Type: constants.PROMOTYPETIERED, DiscountAmount: 1000, Cost: 1000, TierID: &tierOne},
+				},
+				DiscountTotal: 1000,
+				Total:         14000,
+			},
+		},
+		{
+			name: "tiered discount is picked by the subtotal and taken from the remaining total",
+			input: Input{
+				Lines:  repeatLines,
+				Promos: []models.Promo{promoFixedAmountLarge, promoTiered},
+			},
+			want: Breakdown{
+				Lines:    repeatLineBreakdowns,
+				Subtotal: 70000,
+				Promos: []PromoBreakdown{
+					{PromoID: 5, Name: "fixed amount large", Type: constants.PROMOTYPEFIXEDAMOUNT, DiscountAmount: 50000, Cost: 50000},
+					{PromoID: 7, Name: "tiered", Type: constants.PROMOTYPETIERED, DiscountAmount: 3000, Cost: 3000, TierID: &tierThree},
+				},
+				DiscountTotal: 53000,
+				Total:         17000,
+			},
+		},
+		{
+			name: "tiered discount below the lowest tier",
+			input: Input{
+				Lines:  []Line{{ProductID: 1, Price: 5000, Quantity: 1}},
+				Promos: []models.Promo{promoTiered},
+			},
+			want: Breakdown{
+				Lines:    []LineBreakdown{{ProductID: 1, Price: 5000, Quantity: 1, Total: 5000}},
+				Subtotal: 5000,
+				Promos: []PromoBreakdown{
+					{PromoID: 7, Name: "tiered", Type: constants.PROMOTYPETIERED},
+				},
+				Total: 5000,
+			},
+		},
+		{
+			name: "buy x get y costs the retail value of the free items",
+			input: Input{
+				Lines:  lines,
+				Promos: []models.Promo{{ID: 1, Name: "buy x get y", Type: constants.PROMOTYPEBUYXGETY, BuyProductID: &buyProductId, FreeProductID: &freeProductId, BuyProductQty: 2, FreeProductQty: 1, FreeProduct: &models.Product{ID: 2, Price: 2500}}},
+			},
+			want: Breakdown{
+				Lines:    lineBreakdowns,
+				Subtotal: 25000,
+				Promos: []PromoBreakdown{
+					{PromoID: 1, Name: "buy x get y", Type: constants.PROMOTYPEBUYXGETY, FreeProductID: &freeProductId, FreeProductQty: 1, Cost: 2500},
+				},
+				Total: 25000,
+			},
+		},
+		{
+			name: "discount limited to the remaining budget",
+			input: Input{
+				Lines:  lines,
+				Promos: []models.Promo{promoBudget},
+			},
+			want: Breakdown{
+				Lines:    lineBreakdowns,
+				Subtotal: 25000,
+				Promos: []PromoBreakdown{
+					{PromoID: 4, Name: "fixed amount", Type: constants.PROMOTYPEFIXEDAMOUNT, DiscountAmount: 6000, Cost: 6000},
+				},
+				DiscountTotal: 6000,
+				Total:         19000,
+			},
+		},
+		{
+			name: "free items limited to the ones the remaining budget pays for",
+			input: Input{
+				Lines:  repeatLines,
+				Promos: []models.Promo{promoBuyXGetYBudget},
+			},
+			want: Breakdown{
+				Lines:    repeatLineBreakdowns,
+				Subtotal: 70000,
+				Promos: []PromoBreakdown{
+					{PromoID: 1, Name: "buy x get y", Type: constants.PROMOTYPEBUYXGETY, FreeProductID: &freeProductId, FreeProductQty: 2, Cost: 5000},
+				},
+				Total: 70000,
+			},
+		},
+		{
+			name: "remaining budget rounded to whole sen",
+			input: Input{
+				Lines:  lines,
+				Promos: []models.Promo{promoBudgetCrumbs},
+			},
+			want: Breakdown{
+				Lines:    lineBreakdowns,
+				Subtotal: 25000,
+				Promos: []PromoBreakdown{
+					{PromoID: 4, Name: "fixed amount", Type: constants.PROMOTYPEFIXEDAMOUNT, DiscountAmount: 0.01, Cost: 0.01},
+				},
+				DiscountTotal: 0.01,
+				Total:         24999.99,
+			},
+		},
+		{
+			name: "fixed amount discount",
+			input: Input{
+				Lines:  lines,
+				Promos: []models.Promo{promoFixedAmount},
+			},
+			want: Breakdown{
+				Lines:    lineBreakdowns,
+				Subtotal: 25000,
+				Promos: []PromoBreakdown{
+					{PromoID: 4, Name: "fixed amount", Type: constants.PROMOTYPEFIXEDAMOUNT, DiscountAmount: 10000, Cost: 10000},
+				},
+				DiscountTotal: 10000,
+				Total:         15000,
+			},
+		},
+		{
+			name: "fixed amount discount never goes below zero",
+			input: Input{
+				Lines:  lines,
+				Promos: []models.Promo{promoFixedAmount, promoFixedAmountLarge},
+			},
+			want: Breakdown{
+				Lines:    lineBreakdowns,
+				Subtotal: 25000,
+				Promos: []PromoBreakdown{
+					{PromoID: 4, Name: "fixed amount", Type: constants.PROMOTYPEFIXEDAMOUNT, DiscountAmount: 10000, Cost: 10000},
+					{PromoID: 5, Name: "fixed amount large", Type: constants.PROMOTYPEFIXEDAMOUNT, DiscountAmount: 15000, Cost: 15000},
+				},
+				DiscountTotal: 25000,
+				Total:         0,
+			},
+		},
+		{
+			name: "percentage discount of the customer's variant",
+			input: Input{
+				Lines:   lines,
+				Profile: Profile{UserID: 1},
+				Promos:  []models.Promo{promoDiscountVariant},
+			},
+			want: Breakdown{
+				Lines:    lineBreakdowns,
+				Subtotal: 25000,
+				Promos: []PromoBreakdown{
+					{PromoID: 2, Name: "discount", Type: constants.PROMOTYPEPERCENTAGE, DiscountAmount: 5000, VariantID: &variantID, Cost: 5000},
+				},
+				DiscountTotal: 5000,
+				Total:         20000,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Calculate(tt.input); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Calculate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinesFromCart(t *testing.T) {
+	cart := models.Cart{
+		ID: 1,
+		CartItems: []models.CartItem{
+			{ID: 1, ProductID: 1, Quantity: 2, Product: &models.Product{ID: 1, Price: 25000}},
+			{ID: 2, ProductID: 3, Quantity: 1, Product: &models.Product{ID: 3, Price: 5000}},
+		},
+	}
+
+	want := []Line{
+		{ProductID: 1, Price: 25000, Quantity: 2},
+		{ProductID: 3, Price: 5000, Quantity: 1},
+	}
+
+	if got := LinesFromCart(cart); !reflect.DeepEqual(got, want) {
+		t.Errorf("LinesFromCart() = %+v, want %+v", got, want)
+	}
+}
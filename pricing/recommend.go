@@ -0,0 +1,71 @@
+package pricing
+
+import "hangry/domain/models"
+
+// maxRecommendCandidates bounds the promos tried by Recommend, every extra
+// candidate doubles the combinations to price.
+const maxRecommendCandidates = 12
+
+type Recommendation struct {
+	Breakdown      Breakdown
+	FreeItemsValue float64
+	// Saving is the discount total plus the value of the free items.
+	Saving float64
+}
+
+// givenAway sums the costs of the promos of the breakdown, returning the value
+// of the free items and the total saving.
+func givenAway(breakdown Breakdown) (float64, float64) {
+	freeItemsValue, total := 0.0, 0.0
+	for _, promo := range breakdown.Promos {
+		freeItemsValue += promo.Cost - promo.DiscountAmount
+		total += promo.Cost
+	}
+
+	return roundSen(freeItemsValue), roundSen(total)
+}
+
+// Recommend prices every combination of the input promos that passes the
+// stacking rules and returns the one with the highest saving. On equal saving
+// the combination with fewer promos wins. When there are more candidates than
+// maxRecommendCandidates only the highest priority ones are tried. Free items
+// are valued at the price of the free product the promos are loaded with.
+func Recommend(input Input, maxPromos int) Recommendation {
+	candidates := sortByPriority(input.Promos)
+	if len(candidates) > maxRecommendCandidates {
+		candidates = candidates[:maxRecommendCandidates]
+	}
+
+	best := Recommendation{
+		Breakdown: Calculate(Input{Lines: input.Lines, Profile: input.Profile}),
+	}
+	bestCount := 0
+
+	for mask := 1; mask < 1<<len(candidates); mask++ {
+		combination := make([]models.Promo, 0, len(candidates))
+		for i, promo := range candidates {
+			if mask&(1<<i) != 0 {
+				combination = append(combination, promo)
+			}
+		}
+
+		stacked, err := Stack(combination, maxPromos)
+		if err != nil {
+			continue
+		}
+
+		breakdown := Calculate(Input{Lines: input.Lines, Profile: input.Profile, Promos: stacked})
+		freeItemsValue, saving := givenAway(breakdown)
+
+		if saving > best.Saving || (saving == best.Saving && saving > 0 && len(stacked) < bestCount) {
+			best = Recommendation{
+				Breakdown:      breakdown,
+				FreeItemsValue: freeItemsValue,
+				Saving:         saving,
+			}
+			bestCount = len(stacked)
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,110 @@
+package pricing
+
+import (
+	"hangry/constants"
+	"hangry/domain/models"
+	"reflect"
+	"testing"
+)
+
+func TestRecommend(t *testing.T) {
+	buyProductId := uint(1)
+	freeProductId := uint(2)
+
+	promoBuyXGetY := models.Promo{
+		ID:             1,
+		Type:           constants.PROMOTYPEBUYXGETY,
+		BuyProductID:   &buyProductId,
+		FreeProductID:  &freeProductId,
+		BuyProductQty:  2,
+		FreeProductQty: 1,
+		IsExclusive:    true,
+	}
+	// the free product is worth what the promo is loaded with
+	withFreeProductPrice := func(promo models.Promo, price float64) models.Promo {
+		promo.FreeProduct = &models.Product{ID: freeProductId, Price: price}
+		return promo
+	}
+	promoDiscount := models.Promo{
+		ID:                2,
+		Type:              constants.PROMOTYPEPERCENTAGE,
+		DiscountValue:     10,
+		MaxDiscountAmount: 5000,
+	}
+	promoFixedAmount := models.Promo{
+		ID:            3,
+		Type:          constants.PROMOTYPEFIXEDAMOUNT,
+		DiscountValue: 3000,
+	}
+	promoNoDiscount := models.Promo{
+		ID:                4,
+		Type:              constants.PROMOTYPEPERCENTAGE,
+		DiscountValue:     0,
+		MaxDiscountAmount: 5000,
+	}
+
+	lines := []Line{
+		{ProductID: 1, Price: 10000, Quantity: 2},
+	}
+
+	tests := []struct {
+		name          string
+		promos        []models.Promo
+		maxPromos     int
+		wantPromoIDs  []uint
+		wantFreeValue float64
+		wantSaving    float64
+	}{
+		{
+			name:         "no promos",
+			promos:       []models.Promo{},
+			wantPromoIDs: []uint{},
+		},
+		{
+			name:          "free product worth more than stacked discounts",
+			promos:        []models.Promo{withFreeProductPrice(promoBuyXGetY, 8000), promoDiscount, promoFixedAmount},
+			wantPromoIDs:  []uint{1},
+			wantFreeValue: 8000,
+			wantSaving:    8000,
+		},
+		{
+			name:         "stacked discounts worth more than free product",
+			promos:       []models.Promo{promoFixedAmount, promoDiscount, withFreeProductPrice(promoBuyXGetY, 4000)},
+			wantPromoIDs: []uint{2, 3},
+			wantSaving:   5000,
+		},
+		{
+			name:          "max promos per order",
+			promos:        []models.Promo{withFreeProductPrice(promoBuyXGetY, 4000), promoDiscount, promoFixedAmount},
+			maxPromos:     1,
+			wantPromoIDs:  []uint{1},
+			wantFreeValue: 4000,
+			wantSaving:    4000,
+		},
+		{
+			name:         "fewer promos on equal saving",
+			promos:       []models.Promo{promoNoDiscount, promoFixedAmount},
+			wantPromoIDs: []uint{3},
+			wantSaving:   3000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Recommend(Input{Lines: lines, Promos: tt.promos}, tt.maxPromos)
+
+			gotPromoIDs := []uint{}
+			for _, promo := range got.Breakdown.Promos {
+				gotPromoIDs = append(gotPromoIDs, promo.PromoID)
+			}
+			if !reflect.DeepEqual(gotPromoIDs, tt.wantPromoIDs) {
+				t.Errorf("Recommend() promos = %v, want %v", gotPromoIDs, tt.wantPromoIDs)
+			}
+			if got.FreeItemsValue != tt.wantFreeValue {
+				t.Errorf("Recommend() free items value = %v, want %v", got.FreeItemsValue, tt.wantFreeValue)
+			}
+			if got.Saving != tt.wantSaving {
+				t.Errorf("Recommend() saving = %v, want %v", got.Saving, tt.wantSaving)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package pricing
+
+import (
+	"hangry/domain/models"
+	"sort"
+)
+
+// StackError explains why a set of promos can not be applied to one order.
+type StackError struct {
+	Message  string
+	PromoIDs []uint
+}
+
+func (e *StackError) Error() string {
+	return e.Message
+}
+
+// Stack puts the promos in the order they are applied, highest priority first
+// and by ID when the priority is equal, and rejects combinations that break
+// the stacking rules. A maxPromos of zero or less means no limit.
+func Stack(promos []models.Promo, maxPromos int) ([]models.Promo, error) {
+	stacked := sortByPriority(promos)
+
+	if maxPromos > 0 && len(stacked) > maxPromos {
+		return nil, &StackError{
+			Message:  "too many promos for one order",
+			PromoIDs: promoIDs(stacked),
+		}
+	}
+
+	groups := map[string]uint{}
+	for _, promo := range stacked {
+		if promo.IsExclusive && len(stacked) > 1 {
+			return nil, &StackError{
+				Message:  "exclusive promo can not be combined with other promos",
+				PromoIDs: []uint{promo.ID},
+			}
+		}
+
+		if promo.StackGroup == nil {
+			continue
+		}
+
+		if other, ok := groups[*promo.StackGroup]; ok {
+			return nil, &StackError{
+				Message:  "only one promo per stack group can be applied",
+				PromoIDs: []uint{other, promo.ID},
+			}
+		}
+		groups[*promo.StackGroup] = promo.ID
+	}
+
+	return stacked, nil
+}
+
+// sortByPriority returns a copy of the promos, highest priority first and by
+// ID when the priority is equal.
+func sortByPriority(promos []models.Promo) []models.Promo {
+	sorted := make([]models.Promo, len(promos))
+	copy(sorted, promos)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Priority != sorted[j].Priority {
+			return sorted[i].Priority > sorted[j].Priority
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	return sorted
+}
+
+func promoIDs(promos []models.Promo) []uint {
+	ids := make([]uint, 0, len(promos))
+	for _, promo := range promos {
+		ids = append(ids, promo.ID)
+	}
+
+	return ids
+}
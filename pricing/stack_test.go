@@ -0,0 +1,103 @@
+package pricing
+
+import (
+	"hangry/domain/models"
+	"reflect"
+	"testing"
+)
+
+func TestStack(t *testing.T) {
+	payday := "payday"
+
+	tests := []struct {
+		name      string
+		promos    []models.Promo
+		maxPromos int
+		want      []uint
+		wantErr   *StackError
+	}{
+		{
+			name:   "no promos",
+			promos: []models.Promo{},
+			want:   []uint{},
+		},
+		{
+			name: "higher priority first then lower id",
+			promos: []models.Promo{
+				{ID: 3, Priority: 0},
+				{ID: 2, Priority: 5},
+				{ID: 1, Priority: 0},
+			},
+			want: []uint{2, 1, 3},
+		},
+		{
+			name: "exclusive promo alone",
+			promos: []models.Promo{
+				{ID: 1, IsExclusive: true},
+			},
+			want: []uint{1},
+		},
+		{
+			name: "exclusive promo with others",
+			promos: []models.Promo{
+				{ID: 1},
+				{ID: 2, IsExclusive: true},
+			},
+			wantErr: &StackError{
+				Message:  "exclusive promo can not be combined with other promos",
+				PromoIDs: []uint{2},
+			},
+		},
+		{
+			name: "same stack group",
+			promos: []models.Promo{
+				{ID: 2, StackGroup: &payday},
+				{ID: 1, StackGroup: &payday, Priority: 1},
+			},
+			wantErr: &StackError{
+				Message:  "only one promo per stack group can be applied",
+				PromoIDs: []uint{1, 2},
+			},
+		},
+		{
+			name: "over max promos",
+			promos: []models.Promo{
+				{ID: 1},
+				{ID: 2},
+				{ID: 3},
+			},
+			maxPromos: 2,
+			wantErr: &StackError{
+				Message:  "too many promos for one order",
+				PromoIDs: []uint{1, 2, 3},
+			},
+		},
+		{
+			name: "within max promos",
+			promos: []models.Promo{
+				{ID: 2},
+				{ID: 1},
+			},
+			maxPromos: 2,
+			want:      []uint{1, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Stack(tt.promos, tt.maxPromos)
+			if tt.wantErr != nil {
+				if !reflect.DeepEqual(err, tt.wantErr) {
+					t.Errorf("Stack() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Stack() unexpected error = %v", err)
+			}
+
+			if gotIDs := promoIDs(got); !reflect.DeepEqual(gotIDs, tt.want) {
+				t.Errorf("Stack() = %v, want %v", gotIDs, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package pricing
+
+import (
+	"fmt"
+	"hangry/domain/models"
+	"hangry/utils"
+)
+
+// AssignVariant returns the variant of the promo the user is assigned to,
+// false when the promo has no variants. A user keeps the same variant as long
+// as the variants of the promo do not change.
+func AssignVariant(promo models.Promo, userID uint) (models.PromoVariant, bool) {
+	totalWeight := 0
+	for _, variant := range promo.PromoVariants {
+		totalWeight += variantWeight(variant)
+	}
+
+	if totalWeight == 0 {
+		return models.PromoVariant{}, false
+	}
+
+	bucket := utils.Bucket(fmt.Sprintf("variant:%d:%d", promo.ID, userID), totalWeight)
+	for _, variant := range promo.PromoVariants {
+		bucket -= variantWeight(variant)
+		if bucket < 0 {
+			return variant, true
+		}
+	}
+
+	return models.PromoVariant{}, false
+}
+
+// ApplyVariant returns the promo as the user gets it: with the discount of the
+// user's variant and PromoVariants narrowed down to that variant. Applying it
+// again for the same user changes nothing.
+func ApplyVariant(promo models.Promo, userID uint) models.Promo {
+	variant, ok := AssignVariant(promo, userID)
+	if !ok {
+		return promo
+	}
+
+	promo.DiscountValue = variant.DiscountValue
+	if variant.MaxDiscountAmount != nil {
+		promo.MaxDiscountAmount = *variant.MaxDiscountAmount
+	}
+	promo.PromoVariants = []models.PromoVariant{variant}
+
+	return promo
+}
+
+func variantWeight(variant models.PromoVariant) int {
+	if variant.Weight < 1 {
+		return 0
+	}
+
+	return variant.Weight
+}
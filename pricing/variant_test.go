@@ -0,0 +1,69 @@
+package pricing
+
+import (
+	"hangry/domain/models"
+	"reflect"
+	"testing"
+)
+
+func TestAssignVariant(t *testing.T) {
+	promo := models.Promo{
+		ID: 1,
+		PromoVariants: []models.PromoVariant{
+			{ID: 1, PromoID: 1, Name: "A", Weight: 1, DiscountValue: 15},
+			{ID: 2, PromoID: 1, Name: "B", Weight: 1, DiscountValue: 20},
+		},
+	}
+
+	if _, ok := AssignVariant(models.Promo{ID: 1}, 1); ok {
+		t.Errorf("AssignVariant() without variants ok = true, want false")
+	}
+
+	counts := map[string]int{}
+	for userID := uint(1); userID <= 1000; userID++ {
+		variant, ok := AssignVariant(promo, userID)
+		if !ok {
+			t.Fatalf("AssignVariant() user %d ok = false, want true", userID)
+		}
+		if again, _ := AssignVariant(promo, userID); again.ID != variant.ID {
+			t.Fatalf("AssignVariant() user %d = %s then %s, want the same variant", userID, variant.Name, again.Name)
+		}
+		counts[variant.Name]++
+	}
+
+	// equal weights split the users about evenly
+	for _, name := range []string{"A", "B"} {
+		if counts[name] < 400 || counts[name] > 600 {
+			t.Errorf("AssignVariant() assigned %d of 1000 users to %s, want about half", counts[name], name)
+		}
+	}
+}
+
+func TestApplyVariant(t *testing.T) {
+	maxDiscountAmount := float64(30000)
+	promo := models.Promo{
+		ID:                1,
+		DiscountValue:     15,
+		MaxDiscountAmount: 20000,
+		PromoVariants: []models.PromoVariant{
+			{ID: 2, PromoID: 1, Name: "B", Weight: 1, DiscountValue: 20, MaxDiscountAmount: &maxDiscountAmount},
+		},
+	}
+
+	want := promo
+	want.DiscountValue = 20
+	want.MaxDiscountAmount = 30000
+
+	got := ApplyVariant(promo, 1)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ApplyVariant() = %+v, want %+v", got, want)
+	}
+	if again := ApplyVariant(got, 1); !reflect.DeepEqual(again, want) {
+		t.Errorf("ApplyVariant() applied twice = %+v, want %+v", again, want)
+	}
+
+	withoutVariants := models.Promo{ID: 1, DiscountValue: 15}
+	if got := ApplyVariant(withoutVariants, 1); !reflect.DeepEqual(got, withoutVariants) {
+		t.Errorf("ApplyVariant() without variants = %+v, want %+v", got, withoutVariants)
+	}
+}
@@ -64,3 +64,17 @@ func (mr *MockOrderRepositoryMockRecorder) MakeOrder(ctx, tx, order interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MakeOrder", reflect.TypeOf((*MockOrderRepository)(nil).MakeOrder), ctx, tx, order)
 }
+
+// SaveOrderPromoItems mocks base method.
+func (m *MockOrderRepository) SaveOrderPromoItems(ctx context.Context, tx *gorm.DB, items []models.OrderPromoItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveOrderPromoItems", ctx, tx, items)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveOrderPromoItems indicates an expected call of SaveOrderPromoItems.
+func (mr *MockOrderRepositoryMockRecorder) SaveOrderPromoItems(ctx, tx, items interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOrderPromoItems", reflect.TypeOf((*MockOrderRepository)(nil).SaveOrderPromoItems), ctx, tx, items)
+}
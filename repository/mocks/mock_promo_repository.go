@@ -37,6 +37,82 @@ func (m *MockPromoRepository) EXPECT() *MockPromoRepositoryMockRecorder {
 	return m.recorder
 }
 
+// FindUsers mocks base method.
+func (m *MockPromoRepository) FindUsers(ctx context.Context, tx *gorm.DB, promoID uint, userIDs []uint) ([]models.PromoUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUsers", ctx, tx, promoID, userIDs)
+	ret0, _ := ret[0].([]models.PromoUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUsers indicates an expected call of FindUsers.
+func (mr *MockPromoRepositoryMockRecorder) FindUsers(ctx, tx, promoID, userIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUsers", reflect.TypeOf((*MockPromoRepository)(nil).FindUsers), ctx, tx, promoID, userIDs)
+}
+
+// GetAuditLogs mocks base method.
+func (m *MockPromoRepository) GetAuditLogs(ctx context.Context, tx *gorm.DB, promoID uint, page, perPage int) ([]models.PromoAuditLog, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLogs", ctx, tx, promoID, page, perPage)
+	ret0, _ := ret[0].([]models.PromoAuditLog)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAuditLogs indicates an expected call of GetAuditLogs.
+func (mr *MockPromoRepositoryMockRecorder) GetAuditLogs(ctx, tx, promoID, page, perPage interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLogs", reflect.TypeOf((*MockPromoRepository)(nil).GetAuditLogs), ctx, tx, promoID, page, perPage)
+}
+
+// GetCities mocks base method.
+func (m *MockPromoRepository) GetCities(ctx context.Context, tx *gorm.DB, promoID uint) ([]models.PromoCity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCities", ctx, tx, promoID)
+	ret0, _ := ret[0].([]models.PromoCity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCities indicates an expected call of GetCities.
+func (mr *MockPromoRepositoryMockRecorder) GetCities(ctx, tx, promoID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCities", reflect.TypeOf((*MockPromoRepository)(nil).GetCities), ctx, tx, promoID)
+}
+
+// GetProducts mocks base method.
+func (m *MockPromoRepository) GetProducts(ctx context.Context, tx *gorm.DB, promoID uint) ([]models.PromoProduct, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProducts", ctx, tx, promoID)
+	ret0, _ := ret[0].([]models.PromoProduct)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProducts indicates an expected call of GetProducts.
+func (mr *MockPromoRepositoryMockRecorder) GetProducts(ctx, tx, promoID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProducts", reflect.TypeOf((*MockPromoRepository)(nil).GetProducts), ctx, tx, promoID)
+}
+
+// GetPromoByCode mocks base method.
+func (m *MockPromoRepository) GetPromoByCode(ctx context.Context, tx *gorm.DB, code string) (models.Promo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPromoByCode", ctx, tx, code)
+	ret0, _ := ret[0].(models.Promo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPromoByCode indicates an expected call of GetPromoByCode.
+func (mr *MockPromoRepositoryMockRecorder) GetPromoByCode(ctx, tx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPromoByCode", reflect.TypeOf((*MockPromoRepository)(nil).GetPromoByCode), ctx, tx, code)
+}
+
 // GetPromoByPromoID mocks base method.
 func (m *MockPromoRepository) GetPromoByPromoID(ctx context.Context, tx *gorm.DB, promoID uint) (models.Promo, error) {
 	m.ctrl.T.Helper()
@@ -53,13 +129,12 @@ func (mr *MockPromoRepositoryMockRecorder) GetPromoByPromoID(ctx, tx, promoID in
 }
 
 // GetPromoByUserCart mocks base method.
-func (m *MockPromoRepository) GetPromoByUserCart(ctx context.Context, tx *gorm.DB, input repository.GetPromoByUserCartInput) ([]models.Promo, int64, error) {
+func (m *MockPromoRepository) GetPromoByUserCart(ctx context.Context, tx *gorm.DB, input repository.GetPromoByUserCartInput) ([]models.Promo, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetPromoByUserCart", ctx, tx, input)
 	ret0, _ := ret[0].([]models.Promo)
-	ret1, _ := ret[1].(int64)
-	ret2, _ := ret[2].(error)
-	return ret0, ret1, ret2
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // GetPromoByUserCart indicates an expected call of GetPromoByUserCart.
@@ -68,6 +143,158 @@ func (mr *MockPromoRepositoryMockRecorder) GetPromoByUserCart(ctx, tx, input int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPromoByUserCart", reflect.TypeOf((*MockPromoRepository)(nil).GetPromoByUserCart), ctx, tx, input)
 }
 
+// GetPromoDetail mocks base method.
+func (m *MockPromoRepository) GetPromoDetail(ctx context.Context, tx *gorm.DB, promoID uint) (models.Promo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPromoDetail", ctx, tx, promoID)
+	ret0, _ := ret[0].(models.Promo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPromoDetail indicates an expected call of GetPromoDetail.
+func (mr *MockPromoRepositoryMockRecorder) GetPromoDetail(ctx, tx, promoID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPromoDetail", reflect.TypeOf((*MockPromoRepository)(nil).GetPromoDetail), ctx, tx, promoID)
+}
+
+// GetPromoForUpdate mocks base method.
+func (m *MockPromoRepository) GetPromoForUpdate(ctx context.Context, tx *gorm.DB, promoID uint) (models.Promo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPromoForUpdate", ctx, tx, promoID)
+	ret0, _ := ret[0].(models.Promo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPromoForUpdate indicates an expected call of GetPromoForUpdate.
+func (mr *MockPromoRepositoryMockRecorder) GetPromoForUpdate(ctx, tx, promoID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPromoForUpdate", reflect.TypeOf((*MockPromoRepository)(nil).GetPromoForUpdate), ctx, tx, promoID)
+}
+
+// GetSchedules mocks base method.
+func (m *MockPromoRepository) GetSchedules(ctx context.Context, tx *gorm.DB, promoID uint) ([]models.PromoSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSchedules", ctx, tx, promoID)
+	ret0, _ := ret[0].([]models.PromoSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSchedules indicates an expected call of GetSchedules.
+func (mr *MockPromoRepositoryMockRecorder) GetSchedules(ctx, tx, promoID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSchedules", reflect.TypeOf((*MockPromoRepository)(nil).GetSchedules), ctx, tx, promoID)
+}
+
+// GetTiers mocks base method.
+func (m *MockPromoRepository) GetTiers(ctx context.Context, tx *gorm.DB, promoID uint) ([]models.PromoTier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTiers", ctx, tx, promoID)
+	ret0, _ := ret[0].([]models.PromoTier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTiers indicates an expected call of GetTiers.
+func (mr *MockPromoRepositoryMockRecorder) GetTiers(ctx, tx, promoID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTiers", reflect.TypeOf((*MockPromoRepository)(nil).GetTiers), ctx, tx, promoID)
+}
+
+// GetUserUsageCount mocks base method.
+func (m *MockPromoRepository) GetUserUsageCount(ctx context.Context, tx *gorm.DB, userID, promoID uint) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserUsageCount", ctx, tx, userID, promoID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserUsageCount indicates an expected call of GetUserUsageCount.
+func (mr *MockPromoRepositoryMockRecorder) GetUserUsageCount(ctx, tx, userID, promoID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserUsageCount", reflect.TypeOf((*MockPromoRepository)(nil).GetUserUsageCount), ctx, tx, userID, promoID)
+}
+
+// GetUsers mocks base method.
+func (m *MockPromoRepository) GetUsers(ctx context.Context, tx *gorm.DB, promoID uint, page, perPage int) ([]models.PromoUser, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsers", ctx, tx, promoID, page, perPage)
+	ret0, _ := ret[0].([]models.PromoUser)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetUsers indicates an expected call of GetUsers.
+func (mr *MockPromoRepositoryMockRecorder) GetUsers(ctx, tx, promoID, page, perPage interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockPromoRepository)(nil).GetUsers), ctx, tx, promoID, page, perPage)
+}
+
+// IncrementUsage mocks base method.
+func (m *MockPromoRepository) IncrementUsage(ctx context.Context, tx *gorm.DB, promoID uint, amount float64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementUsage", ctx, tx, promoID, amount)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementUsage indicates an expected call of IncrementUsage.
+func (mr *MockPromoRepositoryMockRecorder) IncrementUsage(ctx, tx, promoID, amount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementUsage", reflect.TypeOf((*MockPromoRepository)(nil).IncrementUsage), ctx, tx, promoID, amount)
+}
+
+// ListPromos mocks base method.
+func (m *MockPromoRepository) ListPromos(ctx context.Context, tx *gorm.DB, input repository.ListPromosInput) ([]models.Promo, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPromos", ctx, tx, input)
+	ret0, _ := ret[0].([]models.Promo)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListPromos indicates an expected call of ListPromos.
+func (mr *MockPromoRepositoryMockRecorder) ListPromos(ctx, tx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPromos", reflect.TypeOf((*MockPromoRepository)(nil).ListPromos), ctx, tx, input)
+}
+
+// RemoveCity mocks base method.
+func (m *MockPromoRepository) RemoveCity(ctx context.Context, tx *gorm.DB, promoID uint, city string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveCity", ctx, tx, promoID, city)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveCity indicates an expected call of RemoveCity.
+func (mr *MockPromoRepositoryMockRecorder) RemoveCity(ctx, tx, promoID, city interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCity", reflect.TypeOf((*MockPromoRepository)(nil).RemoveCity), ctx, tx, promoID, city)
+}
+
+// RemoveUsers mocks base method.
+func (m *MockPromoRepository) RemoveUsers(ctx context.Context, tx *gorm.DB, promoID uint, userIDs []uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveUsers", ctx, tx, promoID, userIDs)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveUsers indicates an expected call of RemoveUsers.
+func (mr *MockPromoRepositoryMockRecorder) RemoveUsers(ctx, tx, promoID, userIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveUsers", reflect.TypeOf((*MockPromoRepository)(nil).RemoveUsers), ctx, tx, promoID, userIDs)
+}
+
 // Save mocks base method.
 func (m *MockPromoRepository) Save(ctx context.Context, tx *gorm.DB, promo *models.Promo) error {
 	m.ctrl.T.Helper()
@@ -82,6 +309,20 @@ func (mr *MockPromoRepositoryMockRecorder) Save(ctx, tx, promo interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockPromoRepository)(nil).Save), ctx, tx, promo)
 }
 
+// SaveAuditLog mocks base method.
+func (m *MockPromoRepository) SaveAuditLog(ctx context.Context, tx *gorm.DB, log *models.PromoAuditLog) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveAuditLog", ctx, tx, log)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveAuditLog indicates an expected call of SaveAuditLog.
+func (mr *MockPromoRepositoryMockRecorder) SaveAuditLog(ctx, tx, log interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAuditLog", reflect.TypeOf((*MockPromoRepository)(nil).SaveAuditLog), ctx, tx, log)
+}
+
 // SaveCities mocks base method.
 func (m *MockPromoRepository) SaveCities(ctx context.Context, tx *gorm.DB, promoID uint, cities []string) error {
 	m.ctrl.T.Helper()
@@ -95,3 +336,74 @@ func (mr *MockPromoRepositoryMockRecorder) SaveCities(ctx, tx, promoID, cities i
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCities", reflect.TypeOf((*MockPromoRepository)(nil).SaveCities), ctx, tx, promoID, cities)
 }
+
+// SaveProducts mocks base method.
+func (m *MockPromoRepository) SaveProducts(ctx context.Context, tx *gorm.DB, promoID uint, productIDs []uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveProducts", ctx, tx, promoID, productIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveProducts indicates an expected call of SaveProducts.
+func (mr *MockPromoRepositoryMockRecorder) SaveProducts(ctx, tx, promoID, productIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveProducts", reflect.TypeOf((*MockPromoRepository)(nil).SaveProducts), ctx, tx, promoID, productIDs)
+}
+
+// SaveSchedules mocks base method.
+func (m *MockPromoRepository) SaveSchedules(ctx context.Context, tx *gorm.DB, schedules []models.PromoSchedule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSchedules", ctx, tx, schedules)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSchedules indicates an expected call of SaveSchedules.
+func (mr *MockPromoRepositoryMockRecorder) SaveSchedules(ctx, tx, schedules interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSchedules", reflect.TypeOf((*MockPromoRepository)(nil).SaveSchedules), ctx, tx, schedules)
+}
+
+// SaveTiers mocks base method.
+func (m *MockPromoRepository) SaveTiers(ctx context.Context, tx *gorm.DB, tiers []models.PromoTier) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveTiers", ctx, tx, tiers)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveTiers indicates an expected call of SaveTiers.
+func (mr *MockPromoRepositoryMockRecorder) SaveTiers(ctx, tx, tiers interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTiers", reflect.TypeOf((*MockPromoRepository)(nil).SaveTiers), ctx, tx, tiers)
+}
+
+// SaveUsers mocks base method.
+func (m *MockPromoRepository) SaveUsers(ctx context.Context, tx *gorm.DB, promoID uint, userIDs []uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveUsers", ctx, tx, promoID, userIDs)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveUsers indicates an expected call of SaveUsers.
+func (mr *MockPromoRepositoryMockRecorder) SaveUsers(ctx, tx, promoID, userIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUsers", reflect.TypeOf((*MockPromoRepository)(nil).SaveUsers), ctx, tx, promoID, userIDs)
+}
+
+// SaveVariants mocks base method.
+func (m *MockPromoRepository) SaveVariants(ctx context.Context, tx *gorm.DB, variants []models.PromoVariant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveVariants", ctx, tx, variants)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveVariants indicates an expected call of SaveVariants.
+func (mr *MockPromoRepositoryMockRecorder) SaveVariants(ctx, tx, variants interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveVariants", reflect.TypeOf((*MockPromoRepository)(nil).SaveVariants), ctx, tx, variants)
+}
@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	models "hangry/domain/models"
+	repository "hangry/repository"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -51,6 +52,21 @@ func (mr *MockUserRepositoryMockRecorder) Get(ctx, tx, userID interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockUserRepository)(nil).Get), ctx, tx, userID)
 }
 
+// GetOrderHistory mocks base method.
+func (m *MockUserRepository) GetOrderHistory(ctx context.Context, tx *gorm.DB, userID uint) (repository.UserOrderHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderHistory", ctx, tx, userID)
+	ret0, _ := ret[0].(repository.UserOrderHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderHistory indicates an expected call of GetOrderHistory.
+func (mr *MockUserRepositoryMockRecorder) GetOrderHistory(ctx, tx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderHistory", reflect.TypeOf((*MockUserRepository)(nil).GetOrderHistory), ctx, tx, userID)
+}
+
 // Save mocks base method.
 func (m *MockUserRepository) Save(ctx context.Context, tx *gorm.DB, user *models.User) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,97 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./voucher_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	models "hangry/domain/models"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	gorm "gorm.io/gorm"
+)
+
+// MockVoucherRepository is a mock of VoucherRepository interface.
+type MockVoucherRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockVoucherRepositoryMockRecorder
+}
+
+// MockVoucherRepositoryMockRecorder is the mock recorder for MockVoucherRepository.
+type MockVoucherRepositoryMockRecorder struct {
+	mock *MockVoucherRepository
+}
+
+// NewMockVoucherRepository creates a new mock instance.
+func NewMockVoucherRepository(ctrl *gomock.Controller) *MockVoucherRepository {
+	mock := &MockVoucherRepository{ctrl: ctrl}
+	mock.recorder = &MockVoucherRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVoucherRepository) EXPECT() *MockVoucherRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateVouchers mocks base method.
+func (m *MockVoucherRepository) CreateVouchers(ctx context.Context, tx *gorm.DB, vouchers []models.PromoVoucher) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVouchers", ctx, tx, vouchers)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVouchers indicates an expected call of CreateVouchers.
+func (mr *MockVoucherRepositoryMockRecorder) CreateVouchers(ctx, tx, vouchers interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVouchers", reflect.TypeOf((*MockVoucherRepository)(nil).CreateVouchers), ctx, tx, vouchers)
+}
+
+// GetVoucherByCode mocks base method.
+func (m *MockVoucherRepository) GetVoucherByCode(ctx context.Context, tx *gorm.DB, code string) (models.PromoVoucher, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVoucherByCode", ctx, tx, code)
+	ret0, _ := ret[0].(models.PromoVoucher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVoucherByCode indicates an expected call of GetVoucherByCode.
+func (mr *MockVoucherRepositoryMockRecorder) GetVoucherByCode(ctx, tx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVoucherByCode", reflect.TypeOf((*MockVoucherRepository)(nil).GetVoucherByCode), ctx, tx, code)
+}
+
+// GetVouchersByPromoID mocks base method.
+func (m *MockVoucherRepository) GetVouchersByPromoID(ctx context.Context, tx *gorm.DB, promoID uint) ([]models.PromoVoucher, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVouchersByPromoID", ctx, tx, promoID)
+	ret0, _ := ret[0].([]models.PromoVoucher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVouchersByPromoID indicates an expected call of GetVouchersByPromoID.
+func (mr *MockVoucherRepositoryMockRecorder) GetVouchersByPromoID(ctx, tx, promoID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVouchersByPromoID", reflect.TypeOf((*MockVoucherRepository)(nil).GetVouchersByPromoID), ctx, tx, promoID)
+}
+
+// Redeem mocks base method.
+func (m *MockVoucherRepository) Redeem(ctx context.Context, tx *gorm.DB, voucherID, userID, orderID uint) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeem", ctx, tx, voucherID, userID, orderID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Redeem indicates an expected call of Redeem.
+func (mr *MockVoucherRepositoryMockRecorder) Redeem(ctx, tx, voucherID, userID, orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeem", reflect.TypeOf((*MockVoucherRepository)(nil).Redeem), ctx, tx, voucherID, userID, orderID)
+}
@@ -10,5 +10,6 @@ import (
 //go:generate mockgen -source=./order_repository.go -destination=./mocks/mock_order_repository.go -package=mocks
 type OrderRepository interface {
 	MakeOrder(ctx context.Context, tx *gorm.DB, order *models.Order) error
+	SaveOrderPromoItems(ctx context.Context, tx *gorm.DB, items []models.OrderPromoItem) error
 	GetUserOrderCount(ctx context.Context, tx *gorm.DB, userID uint) (int, error)
 }
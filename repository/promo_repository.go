@@ -3,22 +3,96 @@ package repository
 import (
 	"context"
 	"hangry/domain/models"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type GetPromoByUserCartInput struct {
-	Cart        models.Cart
-	PromoIds    []uint
-	IsAvailable *bool
-	Page        *int
-	PerPage     *int
+	Cart models.Cart
+	// PromoIds and PromoCodes never select promos that have vouchers.
+	PromoIds []uint
+	// PromoCodes selects promos by their uppercase code. Code-only promos can
+	// only be selected this way.
+	PromoCodes []string
+	// VoucherPromoIds selects the promos of redeemed voucher codes, including
+	// code-only ones. It is the only way to select a promo that has vouchers.
+	VoucherPromoIds []uint
+	IsAvailable     *bool
+	// WithinUserLimit leaves out promos the cart owner has already used up to
+	// their max usage per user.
+	WithinUserLimit *bool
+	// ForUpdate locks the selected promos until the transaction ends, so their
+	// usage and spent amount can not change between pricing and reserving.
+	// IsAvailable then leaves out the usage limit and the budget, the caller
+	// checks them on the locked promos.
+	ForUpdate bool
+}
+
+type ListPromosInput struct {
+	// Status lists every promo but the archived ones when empty.
+	Status       string
+	Type         string
+	Segmentation string
+	// StartDate and EndDate keep the promos running at some point between
+	// them, a zero time leaves that side open.
+	StartDate time.Time
+	EndDate   time.Time
+	Page      int
+	PerPage   int
 }
 
 //go:generate mockgen -source=./promo_repository.go -destination=./mocks/mock_promo_repository.go -package=mocks
 type PromoRepository interface {
 	GetPromoByPromoID(ctx context.Context, tx *gorm.DB, promoID uint) (models.Promo, error)
+	// GetPromoForUpdate returns the promo and locks its row until the
+	// transaction of tx ends.
+	GetPromoForUpdate(ctx context.Context, tx *gorm.DB, promoID uint) (models.Promo, error)
+	// GetPromoDetail returns the promo with its PromoCities, PromoProducts,
+	// PromoSchedules, PromoTiers and PromoVariants loaded.
+	GetPromoDetail(ctx context.Context, tx *gorm.DB, promoID uint) (models.Promo, error)
+	// ListPromos returns one page of the promos matching the filters and how
+	// many match in total.
+	ListPromos(ctx context.Context, tx *gorm.DB, input ListPromosInput) ([]models.Promo, int64, error)
+	GetPromoByCode(ctx context.Context, tx *gorm.DB, code string) (models.Promo, error)
+	// Save writes the promo but its current_usage_count and spent_amount,
+	// which only IncrementUsage moves.
 	Save(ctx context.Context, tx *gorm.DB, promo *models.Promo) error
 	SaveCities(ctx context.Context, tx *gorm.DB, promoID uint, cities []string) error
-	GetPromoByUserCart(ctx context.Context, tx *gorm.DB, input GetPromoByUserCartInput) ([]models.Promo, int64, error)
+	GetCities(ctx context.Context, tx *gorm.DB, promoID uint) ([]models.PromoCity, error)
+	// RemoveCity deletes the promo city matching city ignoring case. It
+	// returns false when the promo has no such city.
+	RemoveCity(ctx context.Context, tx *gorm.DB, promoID uint, city string) (bool, error)
+	// SaveUsers lists the users for the promo, skipping unknown users and the
+	// ones already listed. It returns how many were added.
+	SaveUsers(ctx context.Context, tx *gorm.DB, promoID uint, userIDs []uint) (int64, error)
+	// RemoveUsers unlists the users and returns how many were listed.
+	RemoveUsers(ctx context.Context, tx *gorm.DB, promoID uint, userIDs []uint) (int64, error)
+	// GetUsers returns one page of the listed users and how many there are.
+	GetUsers(ctx context.Context, tx *gorm.DB, promoID uint, page int, perPage int) ([]models.PromoUser, int64, error)
+	// FindUsers returns the rows of the given users that are listed.
+	FindUsers(ctx context.Context, tx *gorm.DB, promoID uint, userIDs []uint) ([]models.PromoUser, error)
+	SaveSchedules(ctx context.Context, tx *gorm.DB, schedules []models.PromoSchedule) error
+	GetSchedules(ctx context.Context, tx *gorm.DB, promoID uint) ([]models.PromoSchedule, error)
+	SaveProducts(ctx context.Context, tx *gorm.DB, promoID uint, productIDs []uint) error
+	GetProducts(ctx context.Context, tx *gorm.DB, promoID uint) ([]models.PromoProduct, error)
+	SaveTiers(ctx context.Context, tx *gorm.DB, tiers []models.PromoTier) error
+	GetTiers(ctx context.Context, tx *gorm.DB, promoID uint) ([]models.PromoTier, error)
+	SaveVariants(ctx context.Context, tx *gorm.DB, variants []models.PromoVariant) error
+	// SaveAuditLog appends the entry to the promo's history.
+	SaveAuditLog(ctx context.Context, tx *gorm.DB, log *models.PromoAuditLog) error
+	// GetAuditLogs returns one page of the promo's history, newest first, and
+	// how many entries there are.
+	GetAuditLogs(ctx context.Context, tx *gorm.DB, promoID uint, page int, perPage int) ([]models.PromoAuditLog, int64, error)
+	// GetPromoByUserCart returns the candidate promos for the cart owner with
+	// their FreeProduct, PromoCities, PromoSchedules, PromoProducts,
+	// PromoTiers and PromoVariants loaded, and the owner's PromoUsers row.
+	// USER_LIST promos the owner is not listed for are left out, otherwise
+	// whether the owner and the cart qualify is left to the eligibility rules.
+	GetPromoByUserCart(ctx context.Context, tx *gorm.DB, input GetPromoByUserCartInput) ([]models.Promo, error)
+	// IncrementUsage reserves one redemption of the promo and adds amount to
+	// what it has spent. It returns false when the promo has already reached
+	// its usage limit or the amount does not fit in its budget.
+	IncrementUsage(ctx context.Context, tx *gorm.DB, promoID uint, amount float64) (bool, error)
+	GetUserUsageCount(ctx context.Context, tx *gorm.DB, userID uint, promoID uint) (int, error)
 }
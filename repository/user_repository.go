@@ -3,12 +3,22 @@ package repository
 import (
 	"context"
 	"hangry/domain/models"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+type UserOrderHistory struct {
+	OrderCount int
+	// LastOrderAt is nil when the user has no order.
+	LastOrderAt *time.Time
+}
+
 //go:generate mockgen -source=./user_repository.go -destination=./mocks/mock_user_repository.go -package=mocks
 type UserRepository interface {
 	Save(ctx context.Context, tx *gorm.DB, user *models.User) error
 	Get(ctx context.Context, tx *gorm.DB, userID uint) (*models.User, error)
+	// GetOrderHistory returns how many orders the user has placed and when
+	// the latest one was placed.
+	GetOrderHistory(ctx context.Context, tx *gorm.DB, userID uint) (UserOrderHistory, error)
 }
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"hangry/domain/models"
+
+	"gorm.io/gorm"
+)
+
+//go:generate mockgen -source=./voucher_repository.go -destination=./mocks/mock_voucher_repository.go -package=mocks
+type VoucherRepository interface {
+	// CreateVouchers inserts the vouchers, skipping codes that already exist. It
+	// returns how many were inserted.
+	CreateVouchers(ctx context.Context, tx *gorm.DB, vouchers []models.PromoVoucher) (int64, error)
+	GetVouchersByPromoID(ctx context.Context, tx *gorm.DB, promoID uint) ([]models.PromoVoucher, error)
+	GetVoucherByCode(ctx context.Context, tx *gorm.DB, code string) (models.PromoVoucher, error)
+	// Redeem marks the voucher as consumed by the order. It returns false when
+	// the voucher was already redeemed.
+	Redeem(ctx context.Context, tx *gorm.DB, voucherID uint, userID uint, orderID uint) (bool, error)
+}
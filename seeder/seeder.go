@@ -152,9 +152,29 @@ func SeedPromos(db *gorm.DB) error {
 			MaxUsageLimit:     &limit,
 			CurrentUsageCount: 0,
 		},
+		// Promo Fixed Amount Discount
+		{
+			Name:              "Test Promo Fixed Amount Discount",
+			Description:       "Get Rp10.000 off orders over Rp50.000",
+			Segmentation:      constants.PROMOSEGMENTATIONALL,
+			Type:              constants.PROMOTYPEFIXEDAMOUNT,
+			MinOrderAmount:    50000,
+			DiscountValue:     10000, // Rp10.000 off
+			MaxDiscountAmount: 0,
+			BuyProductID:      nil,
+			FreeProductID:     nil,
+			BuyProductQty:     0,
+			FreeProductQty:    0,
+			StartDate:         time.Now(),
+			EndDate:           time.Now().AddDate(0, 1, 0),
+			MaxUsageLimit:     &limit,
+			CurrentUsageCount: 0,
+		},
 	}
 
 	for _, promo := range promos {
+		// the sample promos skip the approval so they can be redeemed right away
+		promo.Status = constants.PROMOSTATUSAPPROVED
 		if err := db.Session(&gorm.Session{FullSaveAssociations: true}).Create(&promo).Error; err != nil {
 			return err
 		}
@@ -15,6 +15,8 @@ import (
 type CartUsecase interface {
 	AddToCart(ctx context.Context, dto dto.AddToCartInput) error
 	RemoveFromCart(ctx context.Context, dto dto.RemoveFromCartInput) error
+	GetCart(ctx context.Context, dto dto.GetCartInput) (dto.CartDetail, error)
+	UpdateCartItem(ctx context.Context, dto dto.UpdateCartItemInput) error
 }
 
 type cartUsecase struct {
@@ -23,6 +25,77 @@ type cartUsecase struct {
 	productRepository repository.ProductRepository
 }
 
+// UpdateCartItem implements CartUsecase.
+func (c *cartUsecase) UpdateCartItem(ctx context.Context, dto dto.UpdateCartItemInput) error {
+	return c.transaction.Execute(ctx, func(tx *gorm.DB) error {
+		// check item exist in cart
+		item, err := c.cartRepository.CheckItem(ctx, tx, repository.CheckItemInput{
+			UserId:    &dto.UserId,
+			ProductId: dto.ProductId,
+		})
+		if err != nil {
+			return utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+		}
+
+		if item.ID == 0 {
+			return utils.NewCustomError("item not found", nil, http.StatusNotFound)
+		}
+
+		// zero quantity means the line is no longer wanted
+		if dto.Quantity == 0 {
+			err = c.cartRepository.RemoveCartItem(ctx, tx, []uint{item.ID})
+			if err != nil {
+				return utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+			}
+
+			return nil
+		}
+
+		item.Quantity = dto.Quantity
+
+		err = c.cartRepository.AddToCart(ctx, tx, &item)
+		if err != nil {
+			return utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+		}
+
+		return nil
+	})
+}
+
+// GetCart implements CartUsecase.
+func (c *cartUsecase) GetCart(ctx context.Context, input dto.GetCartInput) (dto.CartDetail, error) {
+	cart, err := c.cartRepository.GetUserCart(ctx, nil, repository.GetUserCartInput{
+		UserId:    input.UserId,
+		Relations: []string{"CartItems", "CartItems.Product"},
+	})
+	if err != nil {
+		return dto.CartDetail{}, utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+	}
+
+	detail := dto.CartDetail{
+		CartId: cart.ID,
+		UserId: input.UserId,
+		Items:  []dto.CartItemDetail{},
+	}
+
+	for _, cartItem := range cart.CartItems {
+		item := dto.CartItemDetail{
+			ProductId: cartItem.ProductID,
+			Quantity:  cartItem.Quantity,
+		}
+		if cartItem.Product != nil {
+			item.ProductName = cartItem.Product.Name
+			item.UnitPrice = cartItem.Product.Price
+		}
+		item.LineTotal = item.UnitPrice * float64(item.Quantity)
+
+		detail.Items = append(detail.Items, item)
+		detail.Subtotal += item.LineTotal
+	}
+
+	return detail, nil
+}
+
 // RemoveFromCart implements CartUsecase.
 func (c *cartUsecase) RemoveFromCart(ctx context.Context, dto dto.RemoveFromCartInput) error {
 	// check item exist in cart
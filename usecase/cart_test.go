@@ -7,6 +7,7 @@ import (
 	"hangry/domain/models"
 	"hangry/repository"
 	repo_mock "hangry/repository/mocks"
+	"reflect"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -407,3 +408,296 @@ func Test_cartUsecase_RemoveFromCart(t *testing.T) {
 		})
 	}
 }
+
+func Test_cartUsecase_GetCart(t *testing.T) {
+
+	type args struct {
+		ctx context.Context
+		dto dto.GetCartInput
+	}
+	tests := []struct {
+		name         string
+		args         args
+		want         dto.CartDetail
+		wantErr      bool
+		cartRepoMock func(*repo_mock.MockCartRepository)
+	}{
+		{
+			name: "failed get user cart",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.GetCartInput{
+					UserId: 1,
+				},
+			},
+			want:    dto.CartDetail{},
+			wantErr: true,
+			cartRepoMock: func(mockCartRepo *repo_mock.MockCartRepository) {
+				mockCartRepo.EXPECT().GetUserCart(gomock.Any(), nil, repository.GetUserCartInput{
+					UserId:    1,
+					Relations: []string{"CartItems", "CartItems.Product"},
+				}).Return(models.Cart{}, errors.New("failed get user cart"))
+			},
+		},
+		{
+			name: "cart not found",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.GetCartInput{
+					UserId: 1,
+				},
+			},
+			want: dto.CartDetail{
+				UserId: 1,
+				Items:  []dto.CartItemDetail{},
+			},
+			wantErr: false,
+			cartRepoMock: func(mockCartRepo *repo_mock.MockCartRepository) {
+				mockCartRepo.EXPECT().GetUserCart(gomock.Any(), nil, repository.GetUserCartInput{
+					UserId:    1,
+					Relations: []string{"CartItems", "CartItems.Product"},
+				}).Return(models.Cart{}, nil)
+			},
+		},
+		{
+			name: "success",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.GetCartInput{
+					UserId: 1,
+				},
+			},
+			want: dto.CartDetail{
+				CartId: 1,
+				UserId: 1,
+				Items: []dto.CartItemDetail{
+					{
+						ProductId:   1,
+						ProductName: "Nasi Goreng",
+						Quantity:    2,
+						UnitPrice:   25000,
+						LineTotal:   50000,
+					},
+					{
+						ProductId:   3,
+						ProductName: "Es Teh Manis",
+						Quantity:    3,
+						UnitPrice:   5000,
+						LineTotal:   15000,
+					},
+				},
+				Subtotal: 65000,
+			},
+			wantErr: false,
+			cartRepoMock: func(mockCartRepo *repo_mock.MockCartRepository) {
+				mockCartRepo.EXPECT().GetUserCart(gomock.Any(), nil, repository.GetUserCartInput{
+					UserId:    1,
+					Relations: []string{"CartItems", "CartItems.Product"},
+				}).Return(models.Cart{
+					ID:     1,
+					UserID: 1,
+					CartItems: []models.CartItem{
+						{
+							ID:        1,
+							CartID:    1,
+							ProductID: 1,
+							Quantity:  2,
+							Product: &models.Product{
+								ID:    1,
+								Name:  "Nasi Goreng",
+								Price: 25000,
+							},
+						},
+						{
+							ID:        2,
+							CartID:    1,
+							ProductID: 3,
+							Quantity:  3,
+							Product: &models.Product{
+								ID:    3,
+								Name:  "Es Teh Manis",
+								Price: 5000,
+							},
+						},
+					},
+				}, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			cartRepo := repo_mock.NewMockCartRepository(ctrl)
+			tt.cartRepoMock(cartRepo)
+
+			usecase := NewCartUsecase(nil, cartRepo, nil)
+
+			got, err := usecase.GetCart(tt.args.ctx, tt.args.dto)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("cartUsecase.GetCart() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("cartUsecase.GetCart() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_cartUsecase_UpdateCartItem(t *testing.T) {
+
+	type args struct {
+		ctx context.Context
+		dto dto.UpdateCartItemInput
+	}
+	userId := uint(1)
+	tests := []struct {
+		name         string
+		args         args
+		wantErr      bool
+		cartRepoMock func(*repo_mock.MockCartRepository)
+	}{
+		{
+			name: "failed check item",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.UpdateCartItemInput{
+					ProductId: 1,
+					UserId:    1,
+					Quantity:  2,
+				},
+			},
+			wantErr: true,
+			cartRepoMock: func(mockCartRepo *repo_mock.MockCartRepository) {
+				mockCartRepo.EXPECT().CheckItem(gomock.Any(), nil, repository.CheckItemInput{
+					UserId:    &userId,
+					ProductId: 1,
+				}).Return(models.CartItem{}, errors.New("failed check item"))
+			},
+		},
+		{
+			name: "item not found",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.UpdateCartItemInput{
+					ProductId: 1,
+					UserId:    1,
+					Quantity:  2,
+				},
+			},
+			wantErr: true,
+			cartRepoMock: func(mockCartRepo *repo_mock.MockCartRepository) {
+				mockCartRepo.EXPECT().CheckItem(gomock.Any(), nil, gomock.Any()).Return(models.CartItem{}, nil)
+			},
+		},
+		{
+			name: "zero quantity removes item",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.UpdateCartItemInput{
+					ProductId: 1,
+					UserId:    1,
+					Quantity:  0,
+				},
+			},
+			wantErr: false,
+			cartRepoMock: func(mockCartRepo *repo_mock.MockCartRepository) {
+				mockCartRepo.EXPECT().CheckItem(gomock.Any(), nil, gomock.Any()).Return(models.CartItem{
+					ID:        1,
+					ProductID: 1,
+					Quantity:  3,
+				}, nil)
+				mockCartRepo.EXPECT().RemoveCartItem(gomock.Any(), nil, []uint{1}).Return(nil)
+			},
+		},
+		{
+			name: "failed remove cart item",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.UpdateCartItemInput{
+					ProductId: 1,
+					UserId:    1,
+					Quantity:  0,
+				},
+			},
+			wantErr: true,
+			cartRepoMock: func(mockCartRepo *repo_mock.MockCartRepository) {
+				mockCartRepo.EXPECT().CheckItem(gomock.Any(), nil, gomock.Any()).Return(models.CartItem{
+					ID:        1,
+					ProductID: 1,
+					Quantity:  3,
+				}, nil)
+				mockCartRepo.EXPECT().RemoveCartItem(gomock.Any(), nil, []uint{1}).Return(errors.New("failed remove cart item"))
+			},
+		},
+		{
+			name: "failed add to cart",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.UpdateCartItemInput{
+					ProductId: 1,
+					UserId:    1,
+					Quantity:  2,
+				},
+			},
+			wantErr: true,
+			cartRepoMock: func(mockCartRepo *repo_mock.MockCartRepository) {
+				mockCartRepo.EXPECT().CheckItem(gomock.Any(), nil, gomock.Any()).Return(models.CartItem{
+					ID:        1,
+					ProductID: 1,
+					Quantity:  3,
+				}, nil)
+				mockCartRepo.EXPECT().AddToCart(gomock.Any(), nil, gomock.Any()).Return(errors.New("failed add to cart"))
+			},
+		},
+		{
+			name: "success decrement",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.UpdateCartItemInput{
+					ProductId: 1,
+					UserId:    1,
+					Quantity:  2,
+				},
+			},
+			wantErr: false,
+			cartRepoMock: func(mockCartRepo *repo_mock.MockCartRepository) {
+				mockCartRepo.EXPECT().CheckItem(gomock.Any(), nil, gomock.Any()).Return(models.CartItem{
+					ID:        1,
+					ProductID: 1,
+					Quantity:  3,
+				}, nil)
+				mockCartRepo.EXPECT().AddToCart(gomock.Any(), nil, &models.CartItem{
+					ID:        1,
+					ProductID: 1,
+					Quantity:  2,
+				}).Return(nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			transactionRepo := repo_mock.NewMockTransactionRepository(ctrl)
+			transactionRepo.EXPECT().Execute(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+					return fn(nil)
+				})
+
+			cartRepo := repo_mock.NewMockCartRepository(ctrl)
+			tt.cartRepoMock(cartRepo)
+
+			usecase := NewCartUsecase(transactionRepo, cartRepo, nil)
+
+			if err := usecase.UpdateCartItem(tt.args.ctx, tt.args.dto); (err != nil) != tt.wantErr {
+				t.Errorf("cartUsecase.UpdateCartItem() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
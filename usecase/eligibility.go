@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"hangry/domain/models"
+	"hangry/eligibility"
+	"hangry/pricing"
+	"hangry/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// getEligiblePromos loads the candidate promos for the cart and keeps the ones
+// the cart owner and the cart qualify for. The cart items must have their
+// Product loaded.
+func getEligiblePromos(ctx context.Context, tx *gorm.DB, promoRepository repository.PromoRepository, userRepository repository.UserRepository, input repository.GetPromoByUserCartInput) ([]models.Promo, error) {
+	promos, err := promoRepository.GetPromoByUserCart(ctx, tx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(promos) == 0 {
+		return promos, nil
+	}
+
+	user, err := userRepository.Get(ctx, tx, input.Cart.UserID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	subject := eligibility.Subject{
+		Now:   time.Now(),
+		Lines: pricing.LinesFromCart(input.Cart),
+	}
+	// an unknown user only qualifies for promos open to everyone
+	if user != nil {
+		subject.User = *user
+		if eligibility.NeedsOrderHistory(promos...) {
+			if err := loadOrderHistory(ctx, tx, userRepository, &subject); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return eligibility.Filter(promos, subject), nil
+}
+
+// loadOrderHistory fills the subject's order history from the orders of
+// subject.User.
+func loadOrderHistory(ctx context.Context, tx *gorm.DB, userRepository repository.UserRepository, subject *eligibility.Subject) error {
+	history, err := userRepository.GetOrderHistory(ctx, tx, subject.User.ID)
+	if err != nil {
+		return err
+	}
+
+	subject.OrderCount = history.OrderCount
+	if history.LastOrderAt != nil {
+		subject.LastOrderAt = *history.LastOrderAt
+	}
+
+	return nil
+}
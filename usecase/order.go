@@ -2,9 +2,9 @@ package usecase
 
 import (
 	"context"
-	"hangry/constants"
 	"hangry/domain/dto"
 	"hangry/domain/models"
+	"hangry/pricing"
 	"hangry/repository"
 	"hangry/utils"
 	"net/http"
@@ -15,6 +15,7 @@ import (
 //go:generate mockgen -source=./order.go -destination=./mocks/mock_order.go -package=mocks
 type OrderUsecase interface {
 	CreateOrder(ctx context.Context, dto dto.OrderInput) error
+	QuoteOrder(ctx context.Context, dto dto.OrderInput) (dto.OrderQuote, error)
 }
 
 type orderUsecase struct {
@@ -23,89 +24,335 @@ type orderUsecase struct {
 	userRepository        repository.UserRepository
 	cartRepository        repository.CartRepository
 	promoRepository       repository.PromoRepository
+	voucherRepository     repository.VoucherRepository
+	// maxPromosPerOrder limits how many promos one order can use, zero means no
+	// limit
+	maxPromosPerOrder int
 }
 
-// CreateOrder implements OrderUsecase.
-func (o *orderUsecase) CreateOrder(ctx context.Context, dto dto.OrderInput) error {
-	return o.transactionRepository.Execute(ctx, func(tx *gorm.DB) error {
-		// get user cart
-		cart, err := o.cartRepository.GetUserCart(ctx, tx, repository.GetUserCartInput{
-			UserId:    dto.UserId,
-			Relations: []string{"CartItems", "CartItems.Product"},
+// resolveVouchers splits the typed codes into single-use vouchers and regular
+// promo codes.
+func (o *orderUsecase) resolveVouchers(ctx context.Context, tx *gorm.DB, codes []string) ([]models.PromoVoucher, []string, error) {
+	var vouchers []models.PromoVoucher
+	var promoCodes []string
+
+	for _, code := range codes {
+		voucher, err := o.voucherRepository.GetVoucherByCode(ctx, tx, code)
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return nil, nil, utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+		}
+
+		if voucher.ID == 0 {
+			promoCodes = append(promoCodes, code)
+			continue
+		}
+
+		if voucher.RedeemedAt != nil {
+			return nil, nil, utils.NewCustomError("voucher already redeemed", map[string]interface{}{"code": code}, http.StatusConflict)
+		}
+
+		for _, v := range vouchers {
+			if v.PromoID == voucher.PromoID {
+				return nil, nil, utils.NewCustomError("only one voucher per promo can be used", map[string]interface{}{"code": code}, http.StatusBadRequest)
+			}
+		}
+
+		vouchers = append(vouchers, voucher)
+	}
+
+	return vouchers, promoCodes, nil
+}
+
+// getCartAndPromos loads the user's cart and the requested promos that are still
+// available for it, along with the vouchers used to select them. It is shared
+// by order placement and quoting, placement locks the promos with forUpdate.
+func (o *orderUsecase) getCartAndPromos(ctx context.Context, tx *gorm.DB, dto dto.OrderInput, forUpdate bool) (models.Cart, []models.Promo, []models.PromoVoucher, error) {
+	// get user cart
+	cart, err := o.cartRepository.GetUserCart(ctx, tx, repository.GetUserCartInput{
+		UserId:    dto.UserId,
+		Relations: []string{"CartItems", "CartItems.Product"},
+	})
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return models.Cart{}, nil, nil, utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+	}
+
+	if cart.ID == 0 || len(cart.CartItems) == 0 {
+		return models.Cart{}, nil, nil, utils.NewCustomError("cart not found", nil, http.StatusNotFound)
+	}
+
+	// get promo
+	var promos []models.Promo
+	vouchers, promoCodes, err := o.resolveVouchers(ctx, tx, dto.PromoCodes)
+	if err != nil {
+		return models.Cart{}, nil, nil, err
+	}
+
+	var voucherPromoIds []uint
+	for _, voucher := range vouchers {
+		voucherPromoIds = append(voucherPromoIds, voucher.PromoID)
+	}
+
+	if len(dto.PromoIds) > 0 || len(dto.PromoCodes) > 0 {
+		// check if promo still valid
+		isAvailable := true
+		promos, err = getEligiblePromos(ctx, tx, o.promoRepository, o.userRepository, repository.GetPromoByUserCartInput{
+			Cart:            cart,
+			IsAvailable:     &isAvailable,
+			PromoIds:        dto.PromoIds,
+			PromoCodes:      promoCodes,
+			VoucherPromoIds: voucherPromoIds,
+			ForUpdate:       forUpdate,
 		})
 		if err != nil && err != gorm.ErrRecordNotFound {
+			return models.Cart{}, nil, nil, utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+		}
+		if len(promos) == 0 {
+			return models.Cart{}, nil, nil, utils.NewCustomError("promo not found", nil, http.StatusNotFound)
+		}
+
+		// a requested promo or typed code that does not resolve is reported
+		// instead of being silently dropped from the order
+		for _, code := range promoCodes {
+			if !hasPromoCode(promos, code) {
+				return models.Cart{}, nil, nil, utils.NewCustomError("promo code not found", map[string]interface{}{"code": code}, http.StatusNotFound)
+			}
+		}
+		for _, promoID := range dto.PromoIds {
+			if !hasPromo(promos, promoID) {
+				return models.Cart{}, nil, nil, utils.NewCustomError("promo not found", map[string]interface{}{"promoId": promoID}, http.StatusNotFound)
+			}
+		}
+		for _, voucher := range vouchers {
+			if !hasPromo(promos, voucher.PromoID) {
+				return models.Cart{}, nil, nil, utils.NewCustomError("promo code not found", map[string]interface{}{"code": voucher.Code}, http.StatusNotFound)
+			}
+		}
+
+		// the stacking rules also decide the order the promos are applied in
+		promos, err = pricing.Stack(promos, o.maxPromosPerOrder)
+		if stackErr, ok := err.(*pricing.StackError); ok {
+			return models.Cart{}, nil, nil, utils.NewCustomError(stackErr.Message, map[string]interface{}{"promoIds": stackErr.PromoIDs}, http.StatusUnprocessableEntity)
+		}
+	}
+
+	return cart, promos, vouchers, nil
+}
+
+func hasPromo(promos []models.Promo, promoID uint) bool {
+	for _, promo := range promos {
+		if promo.ID == promoID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasPromoCode(promos []models.Promo, code string) bool {
+	for _, promo := range promos {
+		if promo.Code != nil && *promo.Code == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkUserUsage rejects promos the user has already redeemed up to the promo's
+// max usage per user.
+func (o *orderUsecase) checkUserUsage(ctx context.Context, tx *gorm.DB, userID uint, promos []models.Promo) error {
+	for _, promo := range promos {
+		if promo.MaxUsagePerUser == nil {
+			continue
+		}
+
+		usageCount, err := o.promoRepository.GetUserUsageCount(ctx, tx, userID, promo.ID)
+		if err != nil {
 			return utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
 		}
 
-		if cart.ID == 0 || len(cart.CartItems) == 0 {
-			return utils.NewCustomError("cart not found", nil, http.StatusNotFound)
+		if usageCount >= *promo.MaxUsagePerUser {
+			return utils.NewCustomError("promo usage limit per user reached", map[string]interface{}{"promoId": promo.ID}, http.StatusConflict)
 		}
+	}
 
-		// get promo
-		var promos []models.Promo
-		if len(dto.PromoIds) > 0 {
-			// check if promo still valid
-			isAvailable := true
-			promos, _, err = o.promoRepository.GetPromoByUserCart(ctx, tx, repository.GetPromoByUserCartInput{
-				Cart:        cart,
-				IsAvailable: &isAvailable,
-				PromoIds:    dto.PromoIds,
-			})
-			if err != nil && err != gorm.ErrRecordNotFound {
-				return utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+	return nil
+}
+
+// priceCart runs the cart and promos through the pricing engine. Both
+// CreateOrder and QuoteOrder go through here so a quote always matches the
+// placed order.
+func priceCart(cart models.Cart, promos []models.Promo) pricing.Breakdown {
+	return pricing.Calculate(pricing.Input{
+		Lines:   pricing.LinesFromCart(cart),
+		Profile: pricing.Profile{UserID: cart.UserID},
+		Promos:  promos,
+	})
+}
+
+// newOrder builds the order to be stored from a price breakdown.
+func newOrder(userID uint, breakdown pricing.Breakdown) models.Order {
+	order := models.Order{
+		UserID:      userID,
+		TotalAmount: breakdown.Total,
+	}
+
+	for _, line := range breakdown.Lines {
+		order.OrderItems = append(order.OrderItems, models.OrderItem{
+			ProductID:   line.ProductID,
+			Quantity:    line.Quantity,
+			Price:       line.Price,
+			TotalAmount: line.Total,
+		})
+	}
+
+	for _, promo := range breakdown.Promos {
+		order.OrderPromos = append(order.OrderPromos, models.OrderPromo{
+			PromoID:        promo.PromoID,
+			DiscountAmount: promo.DiscountAmount,
+			FreeProductID:  promo.FreeProductID,
+			FreeProductQty: promo.FreeProductQty,
+			PromoTierID:    promo.TierID,
+			PromoVariantID: promo.VariantID,
+		})
+	}
+
+	return order
+}
+
+// newOrderPromoItems links the product scoped discounts of a stored order to
+// the order items they were taken from. newOrder keeps the promos in the order
+// of the breakdown, so order promos are matched by index.
+func newOrderPromoItems(order models.Order, breakdown pricing.Breakdown) []models.OrderPromoItem {
+	items := []models.OrderPromoItem{}
+	for i, promo := range breakdown.Promos {
+		for _, productID := range promo.ProductIDs {
+			for _, orderItem := range order.OrderItems {
+				if orderItem.ProductID == productID {
+					items = append(items, models.OrderPromoItem{
+						OrderPromoID: order.OrderPromos[i].ID,
+						OrderItemID:  orderItem.ID,
+					})
+				}
 			}
-			if len(promos) == 0 {
-				return utils.NewCustomError("promo not found", nil, http.StatusNotFound)
+		}
+	}
+
+	return items
+}
+
+// QuoteOrder implements OrderUsecase.
+func (o *orderUsecase) QuoteOrder(ctx context.Context, input dto.OrderInput) (dto.OrderQuote, error) {
+	cart, promos, _, err := o.getCartAndPromos(ctx, nil, input, false)
+	if err != nil {
+		return dto.OrderQuote{}, err
+	}
+
+	if err := o.checkUserUsage(ctx, nil, cart.UserID, promos); err != nil {
+		return dto.OrderQuote{}, err
+	}
+
+	return newOrderQuote(priceCart(cart, promos)), nil
+}
+
+// newOrderQuote maps a price breakdown to the quote returned to the customer.
+func newOrderQuote(breakdown pricing.Breakdown) dto.OrderQuote {
+	quote := dto.OrderQuote{
+		Subtotal:      breakdown.Subtotal,
+		Promos:        []dto.OrderQuotePromo{},
+		FreeItems:     []dto.OrderQuoteFreeItem{},
+		DiscountTotal: breakdown.DiscountTotal,
+		Total:         breakdown.Total,
+	}
+
+	for _, promo := range breakdown.Promos {
+		quote.Promos = append(quote.Promos, dto.OrderQuotePromo{
+			PromoId:        promo.PromoID,
+			Name:           promo.Name,
+			Type:           promo.Type,
+			DiscountAmount: promo.DiscountAmount,
+			FreeProductId:  promo.FreeProductID,
+			FreeProductQty: promo.FreeProductQty,
+			TierId:         promo.TierID,
+			VariantId:      promo.VariantID,
+		})
+
+		if promo.FreeProductID != nil && promo.FreeProductQty > 0 {
+			quote.FreeItems = append(quote.FreeItems, dto.OrderQuoteFreeItem{
+				ProductId: *promo.FreeProductID,
+				Quantity:  promo.FreeProductQty,
+				PromoId:   promo.PromoID,
+			})
+		}
+	}
+
+	return quote
+}
+
+// CreateOrder implements OrderUsecase.
+func (o *orderUsecase) CreateOrder(ctx context.Context, dto dto.OrderInput) error {
+	return o.transactionRepository.Execute(ctx, func(tx *gorm.DB) error {
+		cart, promos, vouchers, err := o.getCartAndPromos(ctx, tx, dto, true)
+		if err != nil {
+			return err
+		}
+
+		// the locked promos were loaded without their usage limit and budget,
+		// one used up by a concurrent order is refused like a reservation
+		for _, promo := range promos {
+			if (promo.MaxUsageLimit != nil && promo.CurrentUsageCount >= *promo.MaxUsageLimit) || pricing.BudgetExhausted(promo) {
+				return utils.NewCustomError("promo usage limit or budget reached", map[string]interface{}{"promoId": promo.ID}, http.StatusConflict)
 			}
 		}
 
 		// create order
-		order := models.Order{
-			UserID:      cart.UserID,
-			TotalAmount: 0,
-		}
+		breakdown := priceCart(cart, promos)
+		order := newOrder(cart.UserID, breakdown)
 
 		cartItemIds := make([]uint, 0)
-
 		for _, cartItem := range cart.CartItems {
-			order.TotalAmount += cartItem.Product.Price * float64(cartItem.Quantity)
-			orderItems := models.OrderItem{
-				ProductID:   cartItem.ProductID,
-				Quantity:    cartItem.Quantity,
-				Price:       cartItem.Product.Price,
-				TotalAmount: cartItem.Product.Price * float64(cartItem.Quantity),
-			}
-			order.OrderItems = append(order.OrderItems, orderItems)
 			cartItemIds = append(cartItemIds, cartItem.ID)
 		}
 
+		// reserve promo usage and budget before writing the order, the
+		// breakdown keeps the promos in the same order
 		for i, promo := range promos {
-			orderPromo := models.OrderPromo{
-				PromoID:        promo.ID,
-				DiscountAmount: 0,
+			reserved, err := o.promoRepository.IncrementUsage(ctx, tx, promo.ID, breakdown.Promos[i].Cost)
+			if err != nil {
+				return utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
 			}
-			if promo.Type == constants.PROMOTYPEBUYXGETY {
-				productId := promo.FreeProductID
-				orderPromo.FreeProductID = productId
-				orderPromo.FreeProductQty = promo.FreeProductQty
-			} else if promo.Type == constants.PROMOTYPEPERCENTAGE {
-				total := order.TotalAmount
-				discount := promo.DiscountValue
-				orderPromo.DiscountAmount = total * discount / 100
-				if orderPromo.DiscountAmount > promo.MaxDiscountAmount {
-					orderPromo.DiscountAmount = promo.MaxDiscountAmount
-				}
-				order.TotalAmount -= orderPromo.DiscountAmount
+			if !reserved {
+				return utils.NewCustomError("promo usage limit or budget reached", map[string]interface{}{"promoId": promo.ID}, http.StatusConflict)
 			}
+		}
 
-			order.OrderPromos = append(order.OrderPromos, orderPromo)
-			promos[i].CurrentUsageCount++
+		// the promo rows are locked since they were loaded, so concurrent orders
+		// of the same user are counted one after another
+		if err := o.checkUserUsage(ctx, tx, cart.UserID, promos); err != nil {
+			return err
 		}
 
 		if err := o.orderRepository.MakeOrder(ctx, tx, &order); err != nil {
 			return utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
 		}
 
+		if items := newOrderPromoItems(order, breakdown); len(items) > 0 {
+			if err := o.orderRepository.SaveOrderPromoItems(ctx, tx, items); err != nil {
+				return utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+			}
+		}
+
+		// consume the vouchers in the same transaction that wrote the order promos
+		for _, voucher := range vouchers {
+			redeemed, err := o.voucherRepository.Redeem(ctx, tx, voucher.ID, order.UserID, order.ID)
+			if err != nil {
+				return utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+			}
+			if !redeemed {
+				return utils.NewCustomError("voucher already redeemed", map[string]interface{}{"code": voucher.Code}, http.StatusConflict)
+			}
+		}
+
 		// check if user has ordered more than 3 times
 		totalOrder, err := o.orderRepository.GetUserOrderCount(ctx, tx, order.UserID)
 		if err != nil {
@@ -128,13 +375,6 @@ func (o *orderUsecase) CreateOrder(ctx context.Context, dto dto.OrderInput) erro
 			}
 		}
 
-		// update promo
-		for _, promo := range promos {
-			if err := o.promoRepository.Save(ctx, tx, &promo); err != nil {
-				return utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
-			}
-		}
-
 		// delete cart items
 		if err := o.cartRepository.RemoveCartItem(ctx, tx, cartItemIds); err != nil {
 			return utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
@@ -151,6 +391,8 @@ func NewOrderUsecase(
 	userRepository repository.UserRepository,
 	cartRepository repository.CartRepository,
 	promoRepository repository.PromoRepository,
+	voucherRepository repository.VoucherRepository,
+	maxPromosPerOrder int,
 ) OrderUsecase {
 	return &orderUsecase{
 		transactionRepository: transactionRepository,
@@ -158,5 +400,7 @@ func NewOrderUsecase(
 		userRepository:        userRepository,
 		cartRepository:        cartRepository,
 		promoRepository:       promoRepository,
+		voucherRepository:     voucherRepository,
+		maxPromosPerOrder:     maxPromosPerOrder,
 	}
 }
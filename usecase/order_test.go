@@ -8,7 +8,12 @@ import (
 	"hangry/domain/models"
 	"hangry/repository"
 	repo_mock "hangry/repository/mocks"
+	"hangry/utils"
+	"net/http"
+	"reflect"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"gorm.io/gorm"
@@ -47,6 +52,11 @@ func Test_orderUsecase_CreateOrder(t *testing.T) {
 		FreeProductID:  &freeProductId,
 		BuyProductQty:  10,
 		FreeProductQty: 1,
+		PromoCities:    []models.PromoCity{{City: "Jakarta"}},
+	}
+	cartOwner := models.User{
+		ID:   cartData.UserID,
+		City: "Jakarta",
 	}
 	promoDiscount := models.Promo{
 		ID:                2,
@@ -58,6 +68,14 @@ func Test_orderUsecase_CreateOrder(t *testing.T) {
 		PromoCities:       []models.PromoCity{},
 		OrderPromos:       []models.OrderPromo{},
 	}
+	maxUsagePerUser := 1
+	promoDiscountPerUser := promoDiscount
+	promoDiscountPerUser.MaxUsagePerUser = &maxUsagePerUser
+	voucherData := models.PromoVoucher{
+		ID:      1,
+		PromoID: 2,
+		Code:    "PARTNERABCD2345",
+	}
 	orderData := models.Order{
 		UserID:      0,
 		TotalAmount: 99000,
@@ -83,16 +101,68 @@ func Test_orderUsecase_CreateOrder(t *testing.T) {
 		},
 	}
 
+	promoScoped := promoDiscount
+	promoScoped.PromoProducts = []models.PromoProduct{{PromoID: 2, ProductID: buyProductId}}
+	scopedOrderData := models.Order{
+		TotalAmount: 99000,
+		OrderItems: []models.OrderItem{
+			{
+				ProductID:   buyProductId,
+				Price:       10000,
+				Quantity:    10,
+				TotalAmount: 100000,
+			},
+		},
+		OrderPromos: []models.OrderPromo{
+			{
+				PromoID:        2,
+				DiscountAmount: 1000,
+			},
+		},
+	}
+
+	maxUsageLimit := 5
+	promoDiscountUsedUp := promoDiscount
+	promoDiscountUsedUp.MaxUsageLimit = &maxUsageLimit
+	promoDiscountUsedUp.CurrentUsageCount = 5
+
+	freeProductBudget := float64(15000)
+	promoBuyXGetYBudget := promoBuyXGetY
+	promoBuyXGetYBudget.FreeProduct = &models.Product{ID: freeProductId, Price: 5000}
+	promoBuyXGetYBudget.MaxBudgetAmount = &freeProductBudget
+	promoBuyXGetYBudget.SpentAmount = 10000
+	discountBudget := float64(10000)
+	promoDiscountBudget := promoDiscount
+	promoDiscountBudget.MaxBudgetAmount = &discountBudget
+	promoDiscountBudget.SpentAmount = 9600
+	budgetOrderData := models.Order{
+		TotalAmount: 99600,
+		OrderItems:  orderData.OrderItems,
+		OrderPromos: []models.OrderPromo{
+			{
+				PromoID:        1,
+				FreeProductID:  &freeProductId,
+				FreeProductQty: 1,
+			},
+			{
+				PromoID:        2,
+				DiscountAmount: 400,
+			},
+		},
+	}
+
 	tests := []struct {
-		name     string
-		args     args
-		wantErr  bool
-		mockRepo func(
+		name       string
+		args       args
+		wantErr    bool
+		wantStatus int
+		mockRepo   func(
 			transaction *repo_mock.MockTransactionRepository,
 			cart *repo_mock.MockCartRepository,
 			user *repo_mock.MockUserRepository,
 			promo *repo_mock.MockPromoRepository,
 			order *repo_mock.MockOrderRepository,
+			voucher *repo_mock.MockVoucherRepository,
 		)
 	}{
 		{
@@ -111,6 +181,7 @@ func Test_orderUsecase_CreateOrder(t *testing.T) {
 				user *repo_mock.MockUserRepository,
 				promo *repo_mock.MockPromoRepository,
 				order *repo_mock.MockOrderRepository,
+				voucher *repo_mock.MockVoucherRepository,
 			) {
 				transaction.EXPECT().Execute(gomock.Any(), gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
@@ -139,6 +210,7 @@ func Test_orderUsecase_CreateOrder(t *testing.T) {
 				user *repo_mock.MockUserRepository,
 				promo *repo_mock.MockPromoRepository,
 				order *repo_mock.MockOrderRepository,
+				voucher *repo_mock.MockVoucherRepository,
 			) {
 				transaction.EXPECT().Execute(gomock.Any(), gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
@@ -167,6 +239,7 @@ func Test_orderUsecase_CreateOrder(t *testing.T) {
 				user *repo_mock.MockUserRepository,
 				promo *repo_mock.MockPromoRepository,
 				order *repo_mock.MockOrderRepository,
+				voucher *repo_mock.MockVoucherRepository,
 			) {
 				transaction.EXPECT().Execute(gomock.Any(), gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
@@ -183,7 +256,8 @@ func Test_orderUsecase_CreateOrder(t *testing.T) {
 					Cart:        cartData,
 					IsAvailable: &isAvailable,
 					PromoIds:    []uint{1},
-				}).Return([]models.Promo{}, int64(0), errors.New("error"))
+					ForUpdate:   true,
+				}).Return([]models.Promo{}, errors.New("error"))
 			},
 		},
 		{
@@ -202,6 +276,7 @@ func Test_orderUsecase_CreateOrder(t *testing.T) {
 				user *repo_mock.MockUserRepository,
 				promo *repo_mock.MockPromoRepository,
 				order *repo_mock.MockOrderRepository,
+				voucher *repo_mock.MockVoucherRepository,
 			) {
 				transaction.EXPECT().Execute(gomock.Any(), gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
@@ -218,7 +293,8 @@ func Test_orderUsecase_CreateOrder(t *testing.T) {
 					Cart:        cartData,
 					IsAvailable: &isAvailable,
 					PromoIds:    []uint{1},
-				}).Return([]models.Promo{}, int64(0), nil)
+					ForUpdate:   true,
+				}).Return([]models.Promo{}, nil)
 			},
 		},
 		{
@@ -237,6 +313,7 @@ func Test_orderUsecase_CreateOrder(t *testing.T) {
 				user *repo_mock.MockUserRepository,
 				promo *repo_mock.MockPromoRepository,
 				order *repo_mock.MockOrderRepository,
+				voucher *repo_mock.MockVoucherRepository,
 			) {
 				transaction.EXPECT().Execute(gomock.Any(), gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
@@ -253,11 +330,263 @@ func Test_orderUsecase_CreateOrder(t *testing.T) {
 					Cart:        cartData,
 					IsAvailable: &isAvailable,
 					PromoIds:    []uint{1},
-				}).Return([]models.Promo{promoBuyXGetY, promoDiscount}, int64(2), nil)
+					ForUpdate:   true,
+				}).Return([]models.Promo{promoBuyXGetY, promoDiscount}, nil)
+				user.EXPECT().Get(gomock.Any(), nil, cartData.UserID).Return(&cartOwner, nil)
 
+				promo.EXPECT().IncrementUsage(gomock.Any(), nil, uint(1), float64(0)).Return(true, nil)
+				promo.EXPECT().IncrementUsage(gomock.Any(), nil, uint(2), float64(1000)).Return(true, nil)
 				order.EXPECT().MakeOrder(gomock.Any(), nil, &orderData).Return(errors.New("error"))
 			},
 		},
+		{
+			name: "err redeem voucher",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.OrderInput{
+					UserId:     1,
+					PromoIds:   []uint{1},
+					PromoCodes: []string{"PARTNERABCD2345"},
+				},
+			},
+			wantErr: true,
+			mockRepo: func(
+				transaction *repo_mock.MockTransactionRepository,
+				cart *repo_mock.MockCartRepository,
+				user *repo_mock.MockUserRepository,
+				promo *repo_mock.MockPromoRepository,
+				order *repo_mock.MockOrderRepository,
+				voucher *repo_mock.MockVoucherRepository,
+			) {
+				transaction.EXPECT().Execute(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+						return fn(nil)
+					})
+
+				cart.EXPECT().GetUserCart(gomock.Any(), nil, repository.GetUserCartInput{
+					UserId:    1,
+					Relations: []string{"CartItems", "CartItems.Product"},
+				}).Return(cartData, nil)
+
+				voucher.EXPECT().GetVoucherByCode(gomock.Any(), nil, "PARTNERABCD2345").Return(voucherData, nil)
+
+				isAvailable := true
+				promo.EXPECT().GetPromoByUserCart(gomock.Any(), nil, repository.GetPromoByUserCartInput{
+					Cart:            cartData,
+					IsAvailable:     &isAvailable,
+					PromoIds:        []uint{1},
+					VoucherPromoIds: []uint{2},
+					ForUpdate:       true,
+				}).Return([]models.Promo{promoBuyXGetY, promoDiscount}, nil)
+				user.EXPECT().Get(gomock.Any(), nil, cartData.UserID).Return(&cartOwner, nil)
+
+				promo.EXPECT().IncrementUsage(gomock.Any(), nil, uint(1), float64(0)).Return(true, nil)
+				promo.EXPECT().IncrementUsage(gomock.Any(), nil, uint(2), float64(1000)).Return(true, nil)
+				order.EXPECT().MakeOrder(gomock.Any(), nil, &orderData).DoAndReturn(func(ctx context.Context, tx *gorm.DB, o *models.Order) error {
+					o.ID = 10
+					return nil
+				})
+				voucher.EXPECT().Redeem(gomock.Any(), nil, uint(1), orderData.UserID, uint(10)).Return(false, errors.New("error"))
+			},
+		},
+		{
+			name: "voucher already redeemed",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.OrderInput{
+					UserId:     1,
+					PromoIds:   []uint{1},
+					PromoCodes: []string{"PARTNERABCD2345"},
+				},
+			},
+			wantErr: true,
+			mockRepo: func(
+				transaction *repo_mock.MockTransactionRepository,
+				cart *repo_mock.MockCartRepository,
+				user *repo_mock.MockUserRepository,
+				promo *repo_mock.MockPromoRepository,
+				order *repo_mock.MockOrderRepository,
+				voucher *repo_mock.MockVoucherRepository,
+			) {
+				transaction.EXPECT().Execute(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+						return fn(nil)
+					})
+
+				cart.EXPECT().GetUserCart(gomock.Any(), nil, repository.GetUserCartInput{
+					UserId:    1,
+					Relations: []string{"CartItems", "CartItems.Product"},
+				}).Return(cartData, nil)
+
+				voucher.EXPECT().GetVoucherByCode(gomock.Any(), nil, "PARTNERABCD2345").Return(voucherData, nil)
+
+				isAvailable := true
+				promo.EXPECT().GetPromoByUserCart(gomock.Any(), nil, repository.GetPromoByUserCartInput{
+					Cart:            cartData,
+					IsAvailable:     &isAvailable,
+					PromoIds:        []uint{1},
+					VoucherPromoIds: []uint{2},
+					ForUpdate:       true,
+				}).Return([]models.Promo{promoBuyXGetY, promoDiscount}, nil)
+				user.EXPECT().Get(gomock.Any(), nil, cartData.UserID).Return(&cartOwner, nil)
+
+				promo.EXPECT().IncrementUsage(gomock.Any(), nil, uint(1), float64(0)).Return(true, nil)
+				promo.EXPECT().IncrementUsage(gomock.Any(), nil, uint(2), float64(1000)).Return(true, nil)
+				order.EXPECT().MakeOrder(gomock.Any(), nil, &orderData).DoAndReturn(func(ctx context.Context, tx *gorm.DB, o *models.Order) error {
+					o.ID = 10
+					return nil
+				})
+				voucher.EXPECT().Redeem(gomock.Any(), nil, uint(1), orderData.UserID, uint(10)).Return(false, nil)
+			},
+		},
+		{
+			name: "success with voucher",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.OrderInput{
+					UserId:     1,
+					PromoIds:   []uint{1},
+					PromoCodes: []string{"PARTNERABCD2345"},
+				},
+			},
+			wantErr: false,
+			mockRepo: func(
+				transaction *repo_mock.MockTransactionRepository,
+				cart *repo_mock.MockCartRepository,
+				user *repo_mock.MockUserRepository,
+				promo *repo_mock.MockPromoRepository,
+				order *repo_mock.MockOrderRepository,
+				voucher *repo_mock.MockVoucherRepository,
+			) {
+				transaction.EXPECT().Execute(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+						return fn(nil)
+					})
+
+				cart.EXPECT().GetUserCart(gomock.Any(), nil, repository.GetUserCartInput{
+					UserId:    1,
+					Relations: []string{"CartItems", "CartItems.Product"},
+				}).Return(cartData, nil)
+
+				voucher.EXPECT().GetVoucherByCode(gomock.Any(), nil, "PARTNERABCD2345").Return(voucherData, nil)
+
+				isAvailable := true
+				promo.EXPECT().GetPromoByUserCart(gomock.Any(), nil, repository.GetPromoByUserCartInput{
+					Cart:            cartData,
+					IsAvailable:     &isAvailable,
+					PromoIds:        []uint{1},
+					VoucherPromoIds: []uint{2},
+					ForUpdate:       true,
+				}).Return([]models.Promo{promoBuyXGetY, promoDiscount}, nil)
+				user.EXPECT().Get(gomock.Any(), nil, cartData.UserID).Return(&cartOwner, nil)
+
+				promo.EXPECT().IncrementUsage(gomock.Any(), nil, uint(1), float64(0)).Return(true, nil)
+				promo.EXPECT().IncrementUsage(gomock.Any(), nil, uint(2), float64(1000)).Return(true, nil)
+				order.EXPECT().MakeOrder(gomock.Any(), nil, &orderData).DoAndReturn(func(ctx context.Context, tx *gorm.DB, o *models.Order) error {
+					o.ID = 10
+					return nil
+				})
+				voucher.EXPECT().Redeem(gomock.Any(), nil, uint(1), orderData.UserID, uint(10)).Return(true, nil)
+				order.EXPECT().GetUserOrderCount(gomock.Any(), nil, orderData.UserID).Return(1, nil)
+				cart.EXPECT().RemoveCartItem(gomock.Any(), nil, []uint{1}).Return(nil)
+			},
+		},
+		{
+			name: "err save order promo items",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.OrderInput{
+					UserId:   1,
+					PromoIds: []uint{2},
+				},
+			},
+			wantErr: true,
+			mockRepo: func(
+				transaction *repo_mock.MockTransactionRepository,
+				cart *repo_mock.MockCartRepository,
+				user *repo_mock.MockUserRepository,
+				promo *repo_mock.MockPromoRepository,
+				order *repo_mock.MockOrderRepository,
+				voucher *repo_mock.MockVoucherRepository,
+			) {
+				transaction.EXPECT().Execute(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+						return fn(nil)
+					})
+
+				cart.EXPECT().GetUserCart(gomock.Any(), nil, repository.GetUserCartInput{
+					UserId:    1,
+					Relations: []string{"CartItems", "CartItems.Product"},
+				}).Return(cartData, nil)
+
+				isAvailable := true
+				promo.EXPECT().GetPromoByUserCart(gomock.Any(), nil, repository.GetPromoByUserCartInput{
+					Cart:        cartData,
+					IsAvailable: &isAvailable,
+					PromoIds:    []uint{2},
+					ForUpdate:   true,
+				}).Return([]models.Promo{promoScoped}, nil)
+				user.EXPECT().Get(gomock.Any(), nil, cartData.UserID).Return(&cartOwner, nil)
+
+				promo.EXPECT().IncrementUsage(gomock.Any(), nil, uint(2), float64(1000)).Return(true, nil)
+				order.EXPECT().MakeOrder(gomock.Any(), nil, &scopedOrderData).DoAndReturn(func(ctx context.Context, tx *gorm.DB, o *models.Order) error {
+					o.ID = 10
+					o.OrderItems[0].ID = 5
+					o.OrderPromos[0].ID = 7
+					return nil
+				})
+				order.EXPECT().SaveOrderPromoItems(gomock.Any(), nil, []models.OrderPromoItem{{OrderPromoID: 7, OrderItemID: 5}}).Return(errors.New("error"))
+			},
+		},
+		{
+			name: "success with product scoped discount",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.OrderInput{
+					UserId:   1,
+					PromoIds: []uint{2},
+				},
+			},
+			wantErr: false,
+			mockRepo: func(
+				transaction *repo_mock.MockTransactionRepository,
+				cart *repo_mock.MockCartRepository,
+				user *repo_mock.MockUserRepository,
+				promo *repo_mock.MockPromoRepository,
+				order *repo_mock.MockOrderRepository,
+				voucher *repo_mock.MockVoucherRepository,
+			) {
+				transaction.EXPECT().Execute(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+						return fn(nil)
+					})
+
+				cart.EXPECT().GetUserCart(gomock.Any(), nil, repository.GetUserCartInput{
+					UserId:    1,
+					Relations: []string{"CartItems", "CartItems.Product"},
+				}).Return(cartData, nil)
+
+				isAvailable := true
+				promo.EXPECT().GetPromoByUserCart(gomock.Any(), nil, repository.GetPromoByUserCartInput{
+					Cart:        cartData,
+					IsAvailable: &isAvailable,
+					PromoIds:    []uint{2},
+					ForUpdate:   true,
+				}).Return([]models.Promo{promoScoped}, nil)
+				user.EXPECT().Get(gomock.Any(), nil, cartData.UserID).Return(&cartOwner, nil)
+
+				promo.EXPECT().IncrementUsage(gomock.Any(), nil, uint(2), float64(1000)).Return(true, nil)
+				order.EXPECT().MakeOrder(gomock.Any(), nil, &scopedOrderData).DoAndReturn(func(ctx context.Context, tx *gorm.DB, o *models.Order) error {
+					o.ID = 10
+					o.OrderItems[0].ID = 5
+					o.OrderPromos[0].ID = 7
+					return nil
+				})
+				order.EXPECT().SaveOrderPromoItems(gomock.Any(), nil, []models.OrderPromoItem{{OrderPromoID: 7, OrderItemID: 5}}).Return(nil)
+				order.EXPECT().GetUserOrderCount(gomock.Any(), nil, orderData.UserID).Return(1, nil)
+				cart.EXPECT().RemoveCartItem(gomock.Any(), nil, []uint{1}).Return(nil)
+			},
+		},
 		{
 			name: "err get user order count",
 			args: args{
@@ -274,6 +603,7 @@ func Test_orderUsecase_CreateOrder(t *testing.T) {
 				user *repo_mock.MockUserRepository,
 				promo *repo_mock.MockPromoRepository,
 				order *repo_mock.MockOrderRepository,
+				voucher *repo_mock.MockVoucherRepository,
 			) {
 				transaction.EXPECT().Execute(gomock.Any(), gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
@@ -290,8 +620,12 @@ func Test_orderUsecase_CreateOrder(t *testing.T) {
 					Cart:        cartData,
 					IsAvailable: &isAvailable,
 					PromoIds:    []uint{1},
-				}).Return([]models.Promo{promoBuyXGetY, promoDiscount}, int64(2), nil)
+					ForUpdate:   true,
+				}).Return([]models.Promo{promoBuyXGetY, promoDiscount}, nil)
+				user.EXPECT().Get(gomock.Any(), nil, cartData.UserID).Return(&cartOwner, nil)
 
+				promo.EXPECT().IncrementUsage(gomock.Any(), nil, uint(1), float64(0)).Return(true, nil)
+				promo.EXPECT().IncrementUsage(gomock.Any(), nil, uint(2), float64(1000)).Return(true, nil)
 				order.EXPECT().MakeOrder(gomock.Any(), nil, &orderData).Return(nil)
 				order.EXPECT().GetUserOrderCount(gomock.Any(), nil, orderData.UserID).Return((0), errors.New("error"))
 			},
@@ -312,6 +646,7 @@ func Test_orderUsecase_CreateOrder(t *testing.T) {
 				user *repo_mock.MockUserRepository,
 				promo *repo_mock.MockPromoRepository,
 				order *repo_mock.MockOrderRepository,
+				voucher *repo_mock.MockVoucherRepository,
 			) {
 				transaction.EXPECT().Execute(gomock.Any(), gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
@@ -328,8 +663,12 @@ func Test_orderUsecase_CreateOrder(t *testing.T) {
 					Cart:        cartData,
 					IsAvailable: &isAvailable,
 					PromoIds:    []uint{1},
-				}).Return([]models.Promo{promoBuyXGetY, promoDiscount}, int64(2), nil)
+					ForUpdate:   true,
+				}).Return([]models.Promo{promoBuyXGetY, promoDiscount}, nil)
+				user.EXPECT().Get(gomock.Any(), nil, cartData.UserID).Return(&cartOwner, nil)
 
+				promo.EXPECT().IncrementUsage(gomock.Any(), nil, uint(1), float64(0)).Return(true, nil)
+				promo.EXPECT().IncrementUsage(gomock.Any(), nil, uint(2), float64(1000)).Return(true, nil)
 				order.EXPECT().MakeOrder(gomock.Any(), nil, &orderData).Return(nil)
 				order.EXPECT().GetUserOrderCount(gomock.Any(), nil, orderData.UserID).Return(4, nil)
 				user.EXPECT().Get(gomock.Any(), nil, orderData.UserID).Return(&models.User{}, errors.New("error"))
@@ -351,6 +690,7 @@ func Test_orderUsecase_CreateOrder(t *testing.T) {
 				user *repo_mock.MockUserRepository,
 				promo *repo_mock.MockPromoRepository,
 				order *repo_mock.MockOrderRepository,
+				voucher *repo_mock.MockVoucherRepository,
 			) {
 				transaction.EXPECT().Execute(gomock.Any(), gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
@@ -367,8 +707,12 @@ func Test_orderUsecase_CreateOrder(t *testing.T) {
 					Cart:        cartData,
 					IsAvailable: &isAvailable,
 					PromoIds:    []uint{1},
-				}).Return([]models.Promo{promoBuyXGetY, promoDiscount}, int64(2), nil)
+					ForUpdate:   true,
+				}).Return([]models.Promo{promoBuyXGetY, promoDiscount}, nil)
+				user.EXPECT().Get(gomock.Any(), nil, cartData.UserID).Return(&cartOwner, nil)
 
+				promo.EXPECT().IncrementUsage(gomock.Any(), nil, uint(1), float64(0)).Return(true, nil)
+				promo.EXPECT().IncrementUsage(gomock.Any(), nil, uint(2), float64(1000)).Return(true, nil)
 				order.EXPECT().MakeOrder(gomock.Any(), nil, &orderData).Return(nil)
 				order.EXPECT().GetUserOrderCount(gomock.Any(), nil, orderData.UserID).Return(4, nil)
 				user.EXPECT().Get(gomock.Any(), nil, orderData.UserID).Return(&models.User{}, nil)
@@ -390,6 +734,7 @@ func Test_orderUsecase_CreateOrder(t *testing.T) {
 				user *repo_mock.MockUserRepository,
 				promo *repo_mock.MockPromoRepository,
 				order *repo_mock.MockOrderRepository,
+				voucher *repo_mock.MockVoucherRepository,
 			) {
 				transaction.EXPECT().Execute(gomock.Any(), gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
@@ -406,8 +751,12 @@ func Test_orderUsecase_CreateOrder(t *testing.T) {
 					Cart:        cartData,
 					IsAvailable: &isAvailable,
 					PromoIds:    []uint{1},
-				}).Return([]models.Promo{promoBuyXGetY, promoDiscount}, int64(2), nil)
+					ForUpdate:   true,
+				}).Return([]models.Promo{promoBuyXGetY, promoDiscount}, nil)
+				user.EXPECT().Get(gomock.Any(), nil, cartData.UserID).Return(&cartOwner, nil)
 
+				promo.EXPECT().IncrementUsage(gomock.Any(), nil, uint(1), float64(0)).Return(true, nil)
+				promo.EXPECT().IncrementUsage(gomock.Any(), nil, uint(2), float64(1000)).Return(true, nil)
 				order.EXPECT().MakeOrder(gomock.Any(), nil, &orderData).Return(nil)
 				order.EXPECT().GetUserOrderCount(gomock.Any(), nil, orderData.UserID).Return(4, nil)
 				userData := models.User{
@@ -419,7 +768,7 @@ func Test_orderUsecase_CreateOrder(t *testing.T) {
 			},
 		},
 		{
-			name: "err save promo",
+			name: "err increment promo usage",
 			args: args{
 				ctx: context.Background(),
 				dto: dto.OrderInput{
@@ -434,6 +783,7 @@ func Test_orderUsecase_CreateOrder(t *testing.T) {
 				user *repo_mock.MockUserRepository,
 				promo *repo_mock.MockPromoRepository,
 				order *repo_mock.MockOrderRepository,
+				voucher *repo_mock.MockVoucherRepository,
 			) {
 				transaction.EXPECT().Execute(gomock.Any(), gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
@@ -451,25 +801,15 @@ func Test_orderUsecase_CreateOrder(t *testing.T) {
 					Cart:        cartData,
 					IsAvailable: &isAvailable,
 					PromoIds:    []uint{1},
-				}).Return(promoDatas, int64(2), nil)
+					ForUpdate:   true,
+				}).Return(promoDatas, nil)
+				user.EXPECT().Get(gomock.Any(), nil, cartData.UserID).Return(&cartOwner, nil)
 
-				order.EXPECT().MakeOrder(gomock.Any(), nil, &orderData).Return(nil)
-				order.EXPECT().GetUserOrderCount(gomock.Any(), nil, orderData.UserID).Return(4, nil)
-				userData := models.User{
-					ID: 1,
-				}
-				user.EXPECT().Get(gomock.Any(), nil, orderData.UserID).Return(&userData, nil)
-				userData.IsLoyal = true
-				user.EXPECT().Save(gomock.Any(), nil, &userData).Return(nil)
-				for _, promoData := range promoDatas {
-					promoData.CurrentUsageCount++
-					promo.EXPECT().Save(gomock.Any(), nil, &promoData).Return(errors.New("error"))
-					break
-				}
+				promo.EXPECT().IncrementUsage(gomock.Any(), nil, uint(1), float64(0)).Return(false, errors.New("error"))
 			},
 		},
 		{
-			name: "err remove cart item",
+			name: "promo usage limit reached",
 			args: args{
 				ctx: context.Background(),
 				dto: dto.OrderInput{
@@ -484,6 +824,7 @@ func Test_orderUsecase_CreateOrder(t *testing.T) {
 				user *repo_mock.MockUserRepository,
 				promo *repo_mock.MockPromoRepository,
 				order *repo_mock.MockOrderRepository,
+				voucher *repo_mock.MockVoucherRepository,
 			) {
 				transaction.EXPECT().Execute(gomock.Any(), gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
@@ -501,43 +842,34 @@ func Test_orderUsecase_CreateOrder(t *testing.T) {
 					Cart:        cartData,
 					IsAvailable: &isAvailable,
 					PromoIds:    []uint{1},
-				}).Return(promoDatas, int64(2), nil)
+					ForUpdate:   true,
+				}).Return(promoDatas, nil)
+				user.EXPECT().Get(gomock.Any(), nil, cartData.UserID).Return(&cartOwner, nil)
 
-				order.EXPECT().MakeOrder(gomock.Any(), nil, &orderData).Return(nil)
-				order.EXPECT().GetUserOrderCount(gomock.Any(), nil, orderData.UserID).Return(4, nil)
-				userData := models.User{
-					ID: 1,
-				}
-				user.EXPECT().Get(gomock.Any(), nil, orderData.UserID).Return(&userData, nil)
-				userData.IsLoyal = true
-				user.EXPECT().Save(gomock.Any(), nil, &userData).Return(nil)
-				for _, promoData := range promoDatas {
-					promoData.CurrentUsageCount++
-					promo.EXPECT().Save(gomock.Any(), nil, &promoData).Return(nil)
-				}
-				cartItemIds := []uint{}
-				for _, cartItem := range cartData.CartItems {
-					cartItemIds = append(cartItemIds, cartItem.ID)
-				}
-				cart.EXPECT().RemoveCartItem(gomock.Any(), nil, cartItemIds).Return(errors.New("error"))
+				promo.EXPECT().IncrementUsage(gomock.Any(), nil, uint(1), float64(0)).Return(true, nil)
+				promo.EXPECT().IncrementUsage(gomock.Any(), nil, uint(2), float64(1000)).Return(false, nil)
 			},
 		},
 		{
-			name: "success",
+			// the other order took the last redemption while this one waited
+			// for the promo lock
+			name: "promo used up by a concurrent order",
 			args: args{
 				ctx: context.Background(),
 				dto: dto.OrderInput{
 					UserId:   1,
-					PromoIds: []uint{1},
+					PromoIds: []uint{1, 2},
 				},
 			},
-			wantErr: false,
+			wantErr:    true,
+			wantStatus: http.StatusConflict,
 			mockRepo: func(
 				transaction *repo_mock.MockTransactionRepository,
 				cart *repo_mock.MockCartRepository,
 				user *repo_mock.MockUserRepository,
 				promo *repo_mock.MockPromoRepository,
 				order *repo_mock.MockOrderRepository,
+				voucher *repo_mock.MockVoucherRepository,
 			) {
 				transaction.EXPECT().Execute(gomock.Any(), gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
@@ -550,51 +882,977 @@ func Test_orderUsecase_CreateOrder(t *testing.T) {
 				}).Return(cartData, nil)
 
 				isAvailable := true
-				promoDatas := []models.Promo{promoBuyXGetY, promoDiscount}
 				promo.EXPECT().GetPromoByUserCart(gomock.Any(), nil, repository.GetPromoByUserCartInput{
 					Cart:        cartData,
 					IsAvailable: &isAvailable,
-					PromoIds:    []uint{1},
-				}).Return(promoDatas, int64(2), nil)
-
-				order.EXPECT().MakeOrder(gomock.Any(), nil, &orderData).Return(nil)
-				order.EXPECT().GetUserOrderCount(gomock.Any(), nil, orderData.UserID).Return(4, nil)
-				userData := models.User{
-					ID: 1,
-				}
-				user.EXPECT().Get(gomock.Any(), nil, orderData.UserID).Return(&userData, nil)
-				userData.IsLoyal = true
-				user.EXPECT().Save(gomock.Any(), nil, &userData).Return(nil)
-				for _, promoData := range promoDatas {
-					promoData.CurrentUsageCount++
-					promo.EXPECT().Save(gomock.Any(), nil, &promoData).Return(nil)
-				}
-				cartItemIds := []uint{}
-				for _, cartItem := range cartData.CartItems {
-					cartItemIds = append(cartItemIds, cartItem.ID)
-				}
-				cart.EXPECT().RemoveCartItem(gomock.Any(), nil, cartItemIds).Return(nil)
+					PromoIds:    []uint{1, 2},
+					ForUpdate:   true,
+				}).Return([]models.Promo{promoBuyXGetY, promoDiscountUsedUp}, nil)
+				user.EXPECT().Get(gomock.Any(), nil, cartData.UserID).Return(&cartOwner, nil)
 			},
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-
-			ctrl := gomock.NewController(t)
-			defer ctrl.Finish()
+		{
+			name: "requested promo no longer available",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.OrderInput{
+					UserId:   1,
+					PromoIds: []uint{1, 2},
+				},
+			},
+			wantErr:    true,
+			wantStatus: http.StatusNotFound,
+			mockRepo: func(
+				transaction *repo_mock.MockTransactionRepository,
+				cart *repo_mock.MockCartRepository,
+				user *repo_mock.MockUserRepository,
+				promo *repo_mock.MockPromoRepository,
+				order *repo_mock.MockOrderRepository,
+				voucher *repo_mock.MockVoucherRepository,
+			) {
+				transaction.EXPECT().Execute(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+						return fn(nil)
+					})
 
-			transactionRepo := repo_mock.NewMockTransactionRepository(ctrl)
-			cartRepo := repo_mock.NewMockCartRepository(ctrl)
+				cart.EXPECT().GetUserCart(gomock.Any(), nil, repository.GetUserCartInput{
+					UserId:    1,
+					Relations: []string{"CartItems", "CartItems.Product"},
+				}).Return(cartData, nil)
+
+				isAvailable := true
+				promo.EXPECT().GetPromoByUserCart(gomock.Any(), nil, repository.GetPromoByUserCartInput{
+					Cart:        cartData,
+					IsAvailable: &isAvailable,
+					PromoIds:    []uint{1, 2},
+					ForUpdate:   true,
+				}).Return([]models.Promo{promoBuyXGetY}, nil)
+				user.EXPECT().Get(gomock.Any(), nil, cartData.UserID).Return(&cartOwner, nil)
+			},
+		},
+		{
+			name: "err get user usage count",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.OrderInput{
+					UserId:   1,
+					PromoIds: []uint{1},
+				},
+			},
+			wantErr: true,
+			mockRepo: func(
+				transaction *repo_mock.MockTransactionRepository,
+				cart *repo_mock.MockCartRepository,
+				user *repo_mock.MockUserRepository,
+				promo *repo_mock.MockPromoRepository,
+				order *repo_mock.MockOrderRepository,
+				voucher *repo_mock.MockVoucherRepository,
+			) {
+				transaction.EXPECT().Execute(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+						return fn(nil)
+					})
+
+				cart.EXPECT().GetUserCart(gomock.Any(), nil, repository.GetUserCartInput{
+					UserId:    1,
+					Relations: []string{"CartItems", "CartItems.Product"},
+				}).Return(cartData, nil)
+
+				isAvailable := true
+				promoDatas := []models.Promo{promoBuyXGetY, promoDiscountPerUser}
+				promo.EXPECT().GetPromoByUserCart(gomock.Any(), nil, repository.GetPromoByUserCartInput{
+					Cart:        cartData,
+					IsAvailable: &isAvailable,
+					PromoIds:    []uint{1},
+					ForUpdate:   true,
+				}).Return(promoDatas, nil)
+				user.EXPECT().Get(gomock.Any(), nil, cartData.UserID).Return(&cartOwner, nil)
+
+				promo.EXPECT().IncrementUsage(gomock.Any(), nil, uint(1), float64(0)).Return(true, nil)
+				promo.EXPECT().IncrementUsage(gomock.Any(), nil, uint(2), float64(1000)).Return(true, nil)
+				promo.EXPECT().GetUserUsageCount(gomock.Any(), nil, cartData.UserID, uint(2)).Return(0, errors.New("error"))
+			},
+		},
+		{
+			name: "promo usage limit per user reached",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.OrderInput{
+					UserId:   1,
+					PromoIds: []uint{1},
+				},
+			},
+			wantErr: true,
+			mockRepo: func(
+				transaction *repo_mock.MockTransactionRepository,
+				cart *repo_mock.MockCartRepository,
+				user *repo_mock.MockUserRepository,
+				promo *repo_mock.MockPromoRepository,
+				order *repo_mock.MockOrderRepository,
+				voucher *repo_mock.MockVoucherRepository,
+			) {
+				transaction.EXPECT().Execute(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+						return fn(nil)
+					})
+
+				cart.EXPECT().GetUserCart(gomock.Any(), nil, repository.GetUserCartInput{
+					UserId:    1,
+					Relations: []string{"CartItems", "CartItems.Product"},
+				}).Return(cartData, nil)
+
+				isAvailable := true
+				promoDatas := []models.Promo{promoBuyXGetY, promoDiscountPerUser}
+				promo.EXPECT().GetPromoByUserCart(gomock.Any(), nil, repository.GetPromoByUserCartInput{
+					Cart:        cartData,
+					IsAvailable: &isAvailable,
+					PromoIds:    []uint{1},
+					ForUpdate:   true,
+				}).Return(promoDatas, nil)
+				user.EXPECT().Get(gomock.Any(), nil, cartData.UserID).Return(&cartOwner, nil)
+
+				promo.EXPECT().IncrementUsage(gomock.Any(), nil, uint(1), float64(0)).Return(true, nil)
+				promo.EXPECT().IncrementUsage(gomock.Any(), nil, uint(2), float64(1000)).Return(true, nil)
+				promo.EXPECT().GetUserUsageCount(gomock.Any(), nil, cartData.UserID, uint(2)).Return(1, nil)
+			},
+		},
+		{
+			name: "err remove cart item",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.OrderInput{
+					UserId:   1,
+					PromoIds: []uint{1},
+				},
+			},
+			wantErr: true,
+			mockRepo: func(
+				transaction *repo_mock.MockTransactionRepository,
+				cart *repo_mock.MockCartRepository,
+				user *repo_mock.MockUserRepository,
+				promo *repo_mock.MockPromoRepository,
+				order *repo_mock.MockOrderRepository,
+				voucher *repo_mock.MockVoucherRepository,
+			) {
+				transaction.EXPECT().Execute(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+						return fn(nil)
+					})
+
+				cart.EXPECT().GetUserCart(gomock.Any(), nil, repository.GetUserCartInput{
+					UserId:    1,
+					Relations: []string{"CartItems", "CartItems.Product"},
+				}).Return(cartData, nil)
+
+				isAvailable := true
+				promoDatas := []models.Promo{promoBuyXGetY, promoDiscount}
+				promo.EXPECT().GetPromoByUserCart(gomock.Any(), nil, repository.GetPromoByUserCartInput{
+					Cart:        cartData,
+					IsAvailable: &isAvailable,
+					PromoIds:    []uint{1},
+					ForUpdate:   true,
+				}).Return(promoDatas, nil)
+				user.EXPECT().Get(gomock.Any(), nil, cartData.UserID).Return(&cartOwner, nil)
+
+				promo.EXPECT().IncrementUsage(gomock.Any(), nil, uint(1), float64(0)).Return(true, nil)
+				promo.EXPECT().IncrementUsage(gomock.Any(), nil, uint(2), float64(1000)).Return(true, nil)
+				order.EXPECT().MakeOrder(gomock.Any(), nil, &orderData).Return(nil)
+				order.EXPECT().GetUserOrderCount(gomock.Any(), nil, orderData.UserID).Return(4, nil)
+				userData := models.User{
+					ID: 1,
+				}
+				user.EXPECT().Get(gomock.Any(), nil, orderData.UserID).Return(&userData, nil)
+				userData.IsLoyal = true
+				user.EXPECT().Save(gomock.Any(), nil, &userData).Return(nil)
+				cartItemIds := []uint{}
+				for _, cartItem := range cartData.CartItems {
+					cartItemIds = append(cartItemIds, cartItem.ID)
+				}
+				cart.EXPECT().RemoveCartItem(gomock.Any(), nil, cartItemIds).Return(errors.New("error"))
+			},
+		},
+		{
+			name: "success",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.OrderInput{
+					UserId:   1,
+					PromoIds: []uint{1},
+				},
+			},
+			wantErr: false,
+			mockRepo: func(
+				transaction *repo_mock.MockTransactionRepository,
+				cart *repo_mock.MockCartRepository,
+				user *repo_mock.MockUserRepository,
+				promo *repo_mock.MockPromoRepository,
+				order *repo_mock.MockOrderRepository,
+				voucher *repo_mock.MockVoucherRepository,
+			) {
+				transaction.EXPECT().Execute(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+						return fn(nil)
+					})
+
+				cart.EXPECT().GetUserCart(gomock.Any(), nil, repository.GetUserCartInput{
+					UserId:    1,
+					Relations: []string{"CartItems", "CartItems.Product"},
+				}).Return(cartData, nil)
+
+				isAvailable := true
+				promoDatas := []models.Promo{promoBuyXGetY, promoDiscount}
+				promo.EXPECT().GetPromoByUserCart(gomock.Any(), nil, repository.GetPromoByUserCartInput{
+					Cart:        cartData,
+					IsAvailable: &isAvailable,
+					PromoIds:    []uint{1},
+					ForUpdate:   true,
+				}).Return(promoDatas, nil)
+				user.EXPECT().Get(gomock.Any(), nil, cartData.UserID).Return(&cartOwner, nil)
+
+				promo.EXPECT().IncrementUsage(gomock.Any(), nil, uint(1), float64(0)).Return(true, nil)
+				promo.EXPECT().IncrementUsage(gomock.Any(), nil, uint(2), float64(1000)).Return(true, nil)
+				order.EXPECT().MakeOrder(gomock.Any(), nil, &orderData).Return(nil)
+				order.EXPECT().GetUserOrderCount(gomock.Any(), nil, orderData.UserID).Return(4, nil)
+				userData := models.User{
+					ID: 1,
+				}
+				user.EXPECT().Get(gomock.Any(), nil, orderData.UserID).Return(&userData, nil)
+				userData.IsLoyal = true
+				user.EXPECT().Save(gomock.Any(), nil, &userData).Return(nil)
+				cartItemIds := []uint{}
+				for _, cartItem := range cartData.CartItems {
+					cartItemIds = append(cartItemIds, cartItem.ID)
+				}
+				cart.EXPECT().RemoveCartItem(gomock.Any(), nil, cartItemIds).Return(nil)
+			},
+		},
+		{
+			name: "success with budget left for part of the discount",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.OrderInput{
+					UserId:   1,
+					PromoIds: []uint{1},
+				},
+			},
+			wantErr: false,
+			mockRepo: func(
+				transaction *repo_mock.MockTransactionRepository,
+				cart *repo_mock.MockCartRepository,
+				user *repo_mock.MockUserRepository,
+				promo *repo_mock.MockPromoRepository,
+				order *repo_mock.MockOrderRepository,
+				voucher *repo_mock.MockVoucherRepository,
+			) {
+				transaction.EXPECT().Execute(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+						return fn(nil)
+					})
+
+				cart.EXPECT().GetUserCart(gomock.Any(), nil, repository.GetUserCartInput{
+					UserId:    1,
+					Relations: []string{"CartItems", "CartItems.Product"},
+				}).Return(cartData, nil)
+
+				isAvailable := true
+				promoDatas := []models.Promo{promoBuyXGetYBudget, promoDiscountBudget}
+				promo.EXPECT().GetPromoByUserCart(gomock.Any(), nil, repository.GetPromoByUserCartInput{
+					Cart:        cartData,
+					IsAvailable: &isAvailable,
+					PromoIds:    []uint{1},
+					ForUpdate:   true,
+				}).Return(promoDatas, nil)
+				user.EXPECT().Get(gomock.Any(), nil, cartData.UserID).Return(&cartOwner, nil)
+
+				promo.EXPECT().IncrementUsage(gomock.Any(), nil, uint(1), float64(5000)).Return(true, nil)
+				promo.EXPECT().IncrementUsage(gomock.Any(), nil, uint(2), float64(400)).Return(true, nil)
+				order.EXPECT().MakeOrder(gomock.Any(), nil, &budgetOrderData).Return(nil)
+				order.EXPECT().GetUserOrderCount(gomock.Any(), nil, budgetOrderData.UserID).Return(1, nil)
+				cart.EXPECT().RemoveCartItem(gomock.Any(), nil, []uint{1}).Return(nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			transactionRepo := repo_mock.NewMockTransactionRepository(ctrl)
+			cartRepo := repo_mock.NewMockCartRepository(ctrl)
 			promoRepo := repo_mock.NewMockPromoRepository(ctrl)
 			orderRepo := repo_mock.NewMockOrderRepository(ctrl)
 			userRepo := repo_mock.NewMockUserRepository(ctrl)
+			voucherRepo := repo_mock.NewMockVoucherRepository(ctrl)
 
-			tt.mockRepo(transactionRepo, cartRepo, userRepo, promoRepo, orderRepo)
+			tt.mockRepo(transactionRepo, cartRepo, userRepo, promoRepo, orderRepo, voucherRepo)
 
-			usecase := NewOrderUsecase(transactionRepo, orderRepo, userRepo, cartRepo, promoRepo)
+			usecase := NewOrderUsecase(transactionRepo, orderRepo, userRepo, cartRepo, promoRepo, voucherRepo, 0)
 
-			if err := usecase.CreateOrder(tt.args.ctx, tt.args.dto); (err != nil) != tt.wantErr {
+			err := usecase.CreateOrder(tt.args.ctx, tt.args.dto)
+			if (err != nil) != tt.wantErr {
 				t.Errorf("orderUsecase.CreateOrder() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if customErr, ok := err.(*utils.CustomError); ok && tt.wantStatus != 0 && customErr.StatusCode != tt.wantStatus {
+				t.Errorf("orderUsecase.CreateOrder() status = %v, want %v", customErr.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// Test_orderUsecase_CreateOrder_ConcurrentUsageLimit places many orders at the
+// same time against a promo with a small usage limit. The promo repository mock
+// refuses the reservations past the limit, so this only shows the orders it
+// refuses get a conflict and are not made. That the database itself holds the
+// limit is tested in db/promo_integration_test.go.
+func Test_orderUsecase_CreateOrder_ConcurrentUsageLimit(t *testing.T) {
+	const (
+		totalOrders   = 20
+		maxUsageLimit = 3
+	)
+
+	cartData := models.Cart{
+		ID:     1,
+		UserID: 1,
+		CartItems: []models.CartItem{
+			{
+				ID:        1,
+				CartID:    1,
+				ProductID: 1,
+				Quantity:  1,
+				Product: &models.Product{
+					ID:    1,
+					Price: 10000,
+				},
+			},
+		},
+	}
+	promoDiscount := models.Promo{
+		ID:                1,
+		Segmentation:      constants.PROMOSEGMENTATIONALL,
+		Type:              constants.PROMOTYPEPERCENTAGE,
+		DiscountValue:     10,
+		MaxDiscountAmount: 1000,
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	transactionRepo := repo_mock.NewMockTransactionRepository(ctrl)
+	cartRepo := repo_mock.NewMockCartRepository(ctrl)
+	promoRepo := repo_mock.NewMockPromoRepository(ctrl)
+	orderRepo := repo_mock.NewMockOrderRepository(ctrl)
+	userRepo := repo_mock.NewMockUserRepository(ctrl)
+	voucherRepo := repo_mock.NewMockVoucherRepository(ctrl)
+
+	transactionRepo.EXPECT().Execute(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+			return fn(nil)
+		}).Times(totalOrders)
+	cartRepo.EXPECT().GetUserCart(gomock.Any(), nil, gomock.Any()).Return(cartData, nil).Times(totalOrders)
+	promoRepo.EXPECT().GetPromoByUserCart(gomock.Any(), nil, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, tx *gorm.DB, input repository.GetPromoByUserCartInput) ([]models.Promo, error) {
+			return []models.Promo{promoDiscount}, nil
+		}).Times(totalOrders)
+	userRepo.EXPECT().Get(gomock.Any(), nil, cartData.UserID).Return(&models.User{ID: cartData.UserID}, nil).Times(totalOrders)
+
+	var mu sync.Mutex
+	usageCount := 0
+	promoRepo.EXPECT().IncrementUsage(gomock.Any(), nil, uint(1), float64(1000)).
+		DoAndReturn(func(ctx context.Context, tx *gorm.DB, promoID uint, amount float64) (bool, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if usageCount >= maxUsageLimit {
+				return false, nil
+			}
+			usageCount++
+			return true, nil
+		}).Times(totalOrders)
+
+	orderRepo.EXPECT().MakeOrder(gomock.Any(), nil, gomock.Any()).Return(nil).Times(maxUsageLimit)
+	orderRepo.EXPECT().GetUserOrderCount(gomock.Any(), nil, uint(1)).Return(1, nil).Times(maxUsageLimit)
+	cartRepo.EXPECT().RemoveCartItem(gomock.Any(), nil, []uint{1}).Return(nil).Times(maxUsageLimit)
+
+	usecase := NewOrderUsecase(transactionRepo, orderRepo, userRepo, cartRepo, promoRepo, voucherRepo, 0)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, totalOrders)
+	for i := 0; i < totalOrders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- usecase.CreateOrder(context.Background(), dto.OrderInput{
+				UserId:   1,
+				PromoIds: []uint{1},
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+
+		customErr, ok := err.(*utils.CustomError)
+		if !ok || customErr.StatusCode != http.StatusConflict {
+			t.Errorf("orderUsecase.CreateOrder() error = %v, want conflict", err)
+		}
+	}
+
+	if succeeded != maxUsageLimit {
+		t.Errorf("orderUsecase.CreateOrder() succeeded %d times, want %d", succeeded, maxUsageLimit)
+	}
+	if usageCount != maxUsageLimit {
+		t.Errorf("promo usage count = %d, want %d", usageCount, maxUsageLimit)
+	}
+}
+
+func Test_orderUsecase_QuoteOrder(t *testing.T) {
+
+	type args struct {
+		ctx context.Context
+		dto dto.OrderInput
+	}
+	cartData := models.Cart{
+		ID:     1,
+		UserID: 1,
+		CartItems: []models.CartItem{
+			{
+				ID:        1,
+				CartID:    1,
+				ProductID: 1,
+				Quantity:  10,
+				Product: &models.Product{
+					ID:    1,
+					Price: 10000,
+				},
+			},
+		},
+	}
+
+	cartOwner := models.User{
+		ID: cartData.UserID,
+	}
+
+	buyProductId := uint(1)
+	freeProductId := uint(2)
+	promoBuyXGetY := models.Promo{
+		ID:             1,
+		Name:           "buy x get y",
+		Segmentation:   constants.PROMOSEGMENTATIONALL,
+		Type:           constants.PROMOTYPEBUYXGETY,
+		BuyProductID:   &buyProductId,
+		FreeProductID:  &freeProductId,
+		BuyProductQty:  10,
+		FreeProductQty: 1,
+	}
+	promoDiscount := models.Promo{
+		ID:                2,
+		Name:              "discount",
+		Segmentation:      constants.PROMOSEGMENTATIONALL,
+		Type:              constants.PROMOTYPEPERCENTAGE,
+		MinOrderAmount:    10000,
+		DiscountValue:     10,
+		MaxDiscountAmount: 1000,
+	}
+	maxUsagePerUser := 1
+	promoDiscountPerUser := promoDiscount
+	promoDiscountPerUser.MaxUsagePerUser = &maxUsagePerUser
+	promoCode := "HEMAT20"
+	promoDiscountCode := promoDiscount
+	promoDiscountCode.Code = &promoCode
+	promoDiscountCode.IsCodeOnly = true
+	promoDiscountExclusive := promoDiscount
+	promoDiscountExclusive.IsExclusive = true
+	promoDiscountPriority := promoDiscount
+	promoDiscountPriority.Priority = 1
+	variantId := uint(5)
+	variantMaxDiscount := float64(3000)
+	promoDiscountVariant := promoDiscount
+	promoDiscountVariant.PromoVariants = []models.PromoVariant{
+		{ID: variantId, PromoID: 2, Name: "B", Weight: 1, DiscountValue: 20, MaxDiscountAmount: &variantMaxDiscount},
+	}
+	tierId := uint(2)
+	promoTiered := models.Promo{
+		ID:           3,
+		Name:         "tiered",
+		Segmentation: constants.PROMOSEGMENTATIONALL,
+		Type:         constants.PROMOTYPETIERED,
+		PromoTiers: []models.PromoTier{
+			{ID: 1, PromoID: 3, MinAmount: 50000, DiscountType: constants.PROMOTIERPERCENTAGE, DiscountValue: 5},
+			{ID: 2, PromoID: 3, MinAmount: 100000, DiscountType: constants.PROMOTIERPERCENTAGE, DiscountValue: 10},
+		},
+	}
+
+	tests := []struct {
+		name              string
+		args              args
+		maxPromosPerOrder int
+		want              dto.OrderQuote
+		wantErr           bool
+		mockRepo          func(
+			cart *repo_mock.MockCartRepository,
+			user *repo_mock.MockUserRepository,
+			promo *repo_mock.MockPromoRepository,
+			voucher *repo_mock.MockVoucherRepository,
+		)
+	}{
+		{
+			name: "err get user cart",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.OrderInput{
+					UserId:   1,
+					PromoIds: []uint{1, 2},
+				},
+			},
+			want:    dto.OrderQuote{},
+			wantErr: true,
+			mockRepo: func(cart *repo_mock.MockCartRepository, user *repo_mock.MockUserRepository, promo *repo_mock.MockPromoRepository, voucher *repo_mock.MockVoucherRepository) {
+				cart.EXPECT().GetUserCart(gomock.Any(), nil, repository.GetUserCartInput{
+					UserId:    1,
+					Relations: []string{"CartItems", "CartItems.Product"},
+				}).Return(models.Cart{}, errors.New("error"))
+			},
+		},
+		{
+			name: "cart not found",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.OrderInput{
+					UserId:   1,
+					PromoIds: []uint{1, 2},
+				},
+			},
+			want:    dto.OrderQuote{},
+			wantErr: true,
+			mockRepo: func(cart *repo_mock.MockCartRepository, user *repo_mock.MockUserRepository, promo *repo_mock.MockPromoRepository, voucher *repo_mock.MockVoucherRepository) {
+				cart.EXPECT().GetUserCart(gomock.Any(), nil, gomock.Any()).Return(models.Cart{}, nil)
+			},
+		},
+		{
+			name: "promo not found",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.OrderInput{
+					UserId:   1,
+					PromoIds: []uint{1, 2},
+				},
+			},
+			want:    dto.OrderQuote{},
+			wantErr: true,
+			mockRepo: func(cart *repo_mock.MockCartRepository, user *repo_mock.MockUserRepository, promo *repo_mock.MockPromoRepository, voucher *repo_mock.MockVoucherRepository) {
+				cart.EXPECT().GetUserCart(gomock.Any(), nil, gomock.Any()).Return(cartData, nil)
+				promo.EXPECT().GetPromoByUserCart(gomock.Any(), nil, gomock.Any()).Return([]models.Promo{}, nil)
+			},
+		},
+		{
+			name: "promo usage limit per user reached",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.OrderInput{
+					UserId:   1,
+					PromoIds: []uint{1, 2},
+				},
+			},
+			want:    dto.OrderQuote{},
+			wantErr: true,
+			mockRepo: func(cart *repo_mock.MockCartRepository, user *repo_mock.MockUserRepository, promo *repo_mock.MockPromoRepository, voucher *repo_mock.MockVoucherRepository) {
+				cart.EXPECT().GetUserCart(gomock.Any(), nil, gomock.Any()).Return(cartData, nil)
+				promo.EXPECT().GetPromoByUserCart(gomock.Any(), nil, gomock.Any()).Return([]models.Promo{promoBuyXGetY, promoDiscountPerUser}, nil)
+				user.EXPECT().Get(gomock.Any(), nil, cartData.UserID).Return(&cartOwner, nil)
+				promo.EXPECT().GetUserUsageCount(gomock.Any(), nil, uint(1), uint(2)).Return(1, nil)
+			},
+		},
+		{
+			name: "success without promo",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.OrderInput{
+					UserId:   1,
+					PromoIds: []uint{},
+				},
+			},
+			want: dto.OrderQuote{
+				Subtotal:  100000,
+				Promos:    []dto.OrderQuotePromo{},
+				FreeItems: []dto.OrderQuoteFreeItem{},
+				Total:     100000,
+			},
+			wantErr: false,
+			mockRepo: func(cart *repo_mock.MockCartRepository, user *repo_mock.MockUserRepository, promo *repo_mock.MockPromoRepository, voucher *repo_mock.MockVoucherRepository) {
+				cart.EXPECT().GetUserCart(gomock.Any(), nil, gomock.Any()).Return(cartData, nil)
+			},
+		},
+		{
+			name: "success",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.OrderInput{
+					UserId:   1,
+					PromoIds: []uint{1, 2},
+				},
+			},
+			want: dto.OrderQuote{
+				Subtotal: 100000,
+				Promos: []dto.OrderQuotePromo{
+					{
+						PromoId:        1,
+						Name:           "buy x get y",
+						Type:           constants.PROMOTYPEBUYXGETY,
+						FreeProductId:  &freeProductId,
+						FreeProductQty: 1,
+					},
+					{
+						PromoId:        2,
+						Name:           "discount",
+						Type:           constants.PROMOTYPEPERCENTAGE,
+						DiscountAmount: 1000,
+					},
+				},
+				FreeItems: []dto.OrderQuoteFreeItem{
+					{
+						ProductId: 2,
+						Quantity:  1,
+						PromoId:   1,
+					},
+				},
+				DiscountTotal: 1000,
+				Total:         99000,
+			},
+			wantErr: false,
+			mockRepo: func(cart *repo_mock.MockCartRepository, user *repo_mock.MockUserRepository, promo *repo_mock.MockPromoRepository, voucher *repo_mock.MockVoucherRepository) {
+				cart.EXPECT().GetUserCart(gomock.Any(), nil, gomock.Any()).Return(cartData, nil)
+
+				isAvailable := true
+				promo.EXPECT().GetPromoByUserCart(gomock.Any(), nil, repository.GetPromoByUserCartInput{
+					Cart:        cartData,
+					IsAvailable: &isAvailable,
+					PromoIds:    []uint{1, 2},
+				}).Return([]models.Promo{promoBuyXGetY, promoDiscount}, nil)
+				user.EXPECT().Get(gomock.Any(), nil, cartData.UserID).Return(&cartOwner, nil)
+			},
+		},
+		{
+			name: "promo code not found",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.OrderInput{
+					UserId:     1,
+					PromoIds:   []uint{1},
+					PromoCodes: []string{"HEMAT20", "UNKNOWN"},
+				},
+			},
+			want:    dto.OrderQuote{},
+			wantErr: true,
+			mockRepo: func(cart *repo_mock.MockCartRepository, user *repo_mock.MockUserRepository, promo *repo_mock.MockPromoRepository, voucher *repo_mock.MockVoucherRepository) {
+				cart.EXPECT().GetUserCart(gomock.Any(), nil, gomock.Any()).Return(cartData, nil)
+				voucher.EXPECT().GetVoucherByCode(gomock.Any(), nil, "HEMAT20").Return(models.PromoVoucher{}, gorm.ErrRecordNotFound)
+				voucher.EXPECT().GetVoucherByCode(gomock.Any(), nil, "UNKNOWN").Return(models.PromoVoucher{}, gorm.ErrRecordNotFound)
+				promo.EXPECT().GetPromoByUserCart(gomock.Any(), nil, gomock.Any()).Return([]models.Promo{promoBuyXGetY, promoDiscountCode}, nil)
+				user.EXPECT().Get(gomock.Any(), nil, cartData.UserID).Return(&cartOwner, nil)
+			},
+		},
+		{
+			name: "success with tiered discount",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.OrderInput{
+					UserId:   1,
+					PromoIds: []uint{3},
+				},
+			},
+			want: dto.OrderQuote{
+				Subtotal: 100000,
+				Promos: []dto.OrderQuotePromo{
+					{
+						PromoId:        3,
+						Name:           "tiered",
+						Type:           constants.PROMOTYPETIERED,
+						DiscountAmount: 10000,
+						TierId:         &tierId,
+					},
+				},
+				FreeItems:     []dto.OrderQuoteFreeItem{},
+				DiscountTotal: 10000,
+				Total:         90000,
+			},
+			wantErr: false,
+			mockRepo: func(cart *repo_mock.MockCartRepository, user *repo_mock.MockUserRepository, promo *repo_mock.MockPromoRepository, voucher *repo_mock.MockVoucherRepository) {
+				cart.EXPECT().GetUserCart(gomock.Any(), nil, gomock.Any()).Return(cartData, nil)
+
+				isAvailable := true
+				promo.EXPECT().GetPromoByUserCart(gomock.Any(), nil, repository.GetPromoByUserCartInput{
+					Cart:        cartData,
+					IsAvailable: &isAvailable,
+					PromoIds:    []uint{3},
+				}).Return([]models.Promo{promoTiered}, nil)
+				user.EXPECT().Get(gomock.Any(), nil, cartData.UserID).Return(&cartOwner, nil)
+			},
+		},
+		{
+			name: "success with the user's variant",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.OrderInput{
+					UserId:   1,
+					PromoIds: []uint{2},
+				},
+			},
+			want: dto.OrderQuote{
+				Subtotal: 100000,
+				Promos: []dto.OrderQuotePromo{
+					{
+						PromoId:        2,
+						Name:           "discount",
+						Type:           constants.PROMOTYPEPERCENTAGE,
+						DiscountAmount: 3000,
+						VariantId:      &variantId,
+					},
+				},
+				FreeItems:     []dto.OrderQuoteFreeItem{},
+				DiscountTotal: 3000,
+				Total:         97000,
+			},
+			wantErr: false,
+			mockRepo: func(cart *repo_mock.MockCartRepository, user *repo_mock.MockUserRepository, promo *repo_mock.MockPromoRepository, voucher *repo_mock.MockVoucherRepository) {
+				cart.EXPECT().GetUserCart(gomock.Any(), nil, gomock.Any()).Return(cartData, nil)
+
+				isAvailable := true
+				promo.EXPECT().GetPromoByUserCart(gomock.Any(), nil, repository.GetPromoByUserCartInput{
+					Cart:        cartData,
+					IsAvailable: &isAvailable,
+					PromoIds:    []uint{2},
+				}).Return([]models.Promo{promoDiscountVariant}, nil)
+				user.EXPECT().Get(gomock.Any(), nil, cartData.UserID).Return(&cartOwner, nil)
+			},
+		},
+		{
+			name: "success with promo code",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.OrderInput{
+					UserId:     1,
+					PromoCodes: []string{"HEMAT20"},
+				},
+			},
+			want: dto.OrderQuote{
+				Subtotal: 100000,
+				Promos: []dto.OrderQuotePromo{
+					{
+						PromoId:        2,
+						Name:           "discount",
+						Type:           constants.PROMOTYPEPERCENTAGE,
+						DiscountAmount: 1000,
+					},
+				},
+				FreeItems:     []dto.OrderQuoteFreeItem{},
+				DiscountTotal: 1000,
+				Total:         99000,
+			},
+			wantErr: false,
+			mockRepo: func(cart *repo_mock.MockCartRepository, user *repo_mock.MockUserRepository, promo *repo_mock.MockPromoRepository, voucher *repo_mock.MockVoucherRepository) {
+				cart.EXPECT().GetUserCart(gomock.Any(), nil, gomock.Any()).Return(cartData, nil)
+				voucher.EXPECT().GetVoucherByCode(gomock.Any(), nil, "HEMAT20").Return(models.PromoVoucher{}, gorm.ErrRecordNotFound)
+
+				isAvailable := true
+				promo.EXPECT().GetPromoByUserCart(gomock.Any(), nil, repository.GetPromoByUserCartInput{
+					Cart:        cartData,
+					IsAvailable: &isAvailable,
+					PromoCodes:  []string{"HEMAT20"},
+				}).Return([]models.Promo{promoDiscountCode}, nil)
+				user.EXPECT().Get(gomock.Any(), nil, cartData.UserID).Return(&cartOwner, nil)
+			},
+		},
+		{
+			name: "err get voucher by code",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.OrderInput{
+					UserId:     1,
+					PromoCodes: []string{"PARTNERABCD2345"},
+				},
+			},
+			want:    dto.OrderQuote{},
+			wantErr: true,
+			mockRepo: func(cart *repo_mock.MockCartRepository, user *repo_mock.MockUserRepository, promo *repo_mock.MockPromoRepository, voucher *repo_mock.MockVoucherRepository) {
+				cart.EXPECT().GetUserCart(gomock.Any(), nil, gomock.Any()).Return(cartData, nil)
+				voucher.EXPECT().GetVoucherByCode(gomock.Any(), nil, "PARTNERABCD2345").Return(models.PromoVoucher{}, errors.New("error"))
+			},
+		},
+		{
+			name: "voucher already redeemed",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.OrderInput{
+					UserId:     1,
+					PromoCodes: []string{"PARTNERABCD2345"},
+				},
+			},
+			want:    dto.OrderQuote{},
+			wantErr: true,
+			mockRepo: func(cart *repo_mock.MockCartRepository, user *repo_mock.MockUserRepository, promo *repo_mock.MockPromoRepository, voucher *repo_mock.MockVoucherRepository) {
+				cart.EXPECT().GetUserCart(gomock.Any(), nil, gomock.Any()).Return(cartData, nil)
+				redeemedAt := time.Now()
+				voucher.EXPECT().GetVoucherByCode(gomock.Any(), nil, "PARTNERABCD2345").Return(models.PromoVoucher{
+					ID:         1,
+					PromoID:    2,
+					Code:       "PARTNERABCD2345",
+					RedeemedAt: &redeemedAt,
+				}, nil)
+			},
+		},
+		{
+			name: "success with voucher",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.OrderInput{
+					UserId:     1,
+					PromoCodes: []string{"PARTNERABCD2345"},
+				},
+			},
+			want: dto.OrderQuote{
+				Subtotal: 100000,
+				Promos: []dto.OrderQuotePromo{
+					{
+						PromoId:        2,
+						Name:           "discount",
+						Type:           constants.PROMOTYPEPERCENTAGE,
+						DiscountAmount: 1000,
+					},
+				},
+				FreeItems:     []dto.OrderQuoteFreeItem{},
+				DiscountTotal: 1000,
+				Total:         99000,
+			},
+			wantErr: false,
+			mockRepo: func(cart *repo_mock.MockCartRepository, user *repo_mock.MockUserRepository, promo *repo_mock.MockPromoRepository, voucher *repo_mock.MockVoucherRepository) {
+				cart.EXPECT().GetUserCart(gomock.Any(), nil, gomock.Any()).Return(cartData, nil)
+				voucher.EXPECT().GetVoucherByCode(gomock.Any(), nil, "PARTNERABCD2345").Return(models.PromoVoucher{
+					ID:      1,
+					PromoID: 2,
+					Code:    "PARTNERABCD2345",
+				}, nil)
+
+				isAvailable := true
+				promo.EXPECT().GetPromoByUserCart(gomock.Any(), nil, repository.GetPromoByUserCartInput{
+					Cart:            cartData,
+					IsAvailable:     &isAvailable,
+					VoucherPromoIds: []uint{2},
+				}).Return([]models.Promo{promoDiscount}, nil)
+				user.EXPECT().Get(gomock.Any(), nil, cartData.UserID).Return(&cartOwner, nil)
+			},
+		},
+		{
+			name: "exclusive promo combined with other promo",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.OrderInput{
+					UserId:   1,
+					PromoIds: []uint{1, 2},
+				},
+			},
+			want:    dto.OrderQuote{},
+			wantErr: true,
+			mockRepo: func(cart *repo_mock.MockCartRepository, user *repo_mock.MockUserRepository, promo *repo_mock.MockPromoRepository, voucher *repo_mock.MockVoucherRepository) {
+				cart.EXPECT().GetUserCart(gomock.Any(), nil, gomock.Any()).Return(cartData, nil)
+				promo.EXPECT().GetPromoByUserCart(gomock.Any(), nil, gomock.Any()).Return([]models.Promo{promoBuyXGetY, promoDiscountExclusive}, nil)
+				user.EXPECT().Get(gomock.Any(), nil, cartData.UserID).Return(&cartOwner, nil)
+			},
+		},
+		{
+			name: "too many promos for one order",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.OrderInput{
+					UserId:   1,
+					PromoIds: []uint{1, 2},
+				},
+			},
+			maxPromosPerOrder: 1,
+			want:              dto.OrderQuote{},
+			wantErr:           true,
+			mockRepo: func(cart *repo_mock.MockCartRepository, user *repo_mock.MockUserRepository, promo *repo_mock.MockPromoRepository, voucher *repo_mock.MockVoucherRepository) {
+				cart.EXPECT().GetUserCart(gomock.Any(), nil, gomock.Any()).Return(cartData, nil)
+				promo.EXPECT().GetPromoByUserCart(gomock.Any(), nil, gomock.Any()).Return([]models.Promo{promoBuyXGetY, promoDiscount}, nil)
+				user.EXPECT().Get(gomock.Any(), nil, cartData.UserID).Return(&cartOwner, nil)
+			},
+		},
+		{
+			name: "success applies higher priority first",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.OrderInput{
+					UserId:   1,
+					PromoIds: []uint{1, 2},
+				},
+			},
+			maxPromosPerOrder: 2,
+			want: dto.OrderQuote{
+				Subtotal: 100000,
+				Promos: []dto.OrderQuotePromo{
+					{
+						PromoId:        2,
+						Name:           "discount",
+						Type:           constants.PROMOTYPEPERCENTAGE,
+						DiscountAmount: 1000,
+					},
+					{
+						PromoId:        1,
+						Name:           "buy x get y",
+						Type:           constants.PROMOTYPEBUYXGETY,
+						FreeProductId:  &freeProductId,
+						FreeProductQty: 1,
+					},
+				},
+				FreeItems: []dto.OrderQuoteFreeItem{
+					{
+						ProductId: 2,
+						Quantity:  1,
+						PromoId:   1,
+					},
+				},
+				DiscountTotal: 1000,
+				Total:         99000,
+			},
+			wantErr: false,
+			mockRepo: func(cart *repo_mock.MockCartRepository, user *repo_mock.MockUserRepository, promo *repo_mock.MockPromoRepository, voucher *repo_mock.MockVoucherRepository) {
+				cart.EXPECT().GetUserCart(gomock.Any(), nil, gomock.Any()).Return(cartData, nil)
+				promo.EXPECT().GetPromoByUserCart(gomock.Any(), nil, gomock.Any()).Return([]models.Promo{promoBuyXGetY, promoDiscountPriority}, nil)
+				user.EXPECT().Get(gomock.Any(), nil, cartData.UserID).Return(&cartOwner, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			transactionRepo := repo_mock.NewMockTransactionRepository(ctrl)
+			cartRepo := repo_mock.NewMockCartRepository(ctrl)
+			promoRepo := repo_mock.NewMockPromoRepository(ctrl)
+			orderRepo := repo_mock.NewMockOrderRepository(ctrl)
+			userRepo := repo_mock.NewMockUserRepository(ctrl)
+			voucherRepo := repo_mock.NewMockVoucherRepository(ctrl)
+
+			// a quote must never write, so only the read repositories get expectations
+			tt.mockRepo(cartRepo, userRepo, promoRepo, voucherRepo)
+
+			usecase := NewOrderUsecase(transactionRepo, orderRepo, userRepo, cartRepo, promoRepo, voucherRepo, tt.maxPromosPerOrder)
+
+			got, err := usecase.QuoteOrder(tt.args.ctx, tt.args.dto)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("orderUsecase.QuoteOrder() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("orderUsecase.QuoteOrder() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -5,20 +5,57 @@ import (
 	"hangry/constants"
 	"hangry/domain/dto"
 	"hangry/domain/models"
+	"hangry/eligibility"
 	"hangry/generated"
+	"hangry/pricing"
 	"hangry/repository"
 	"hangry/utils"
 	"net/http"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+// reviewedPromoFields are the fields of the pricing a reviewer approves.
+var reviewedPromoFields = []string{"min_order_amount", "discount_value", "max_discount_amount", "max_budget_amount"}
+
 //go:generate mockgen -source=./promo.go -destination=./mocks/mock_promo.go -package=mocks
 type PromoUsecase interface {
 	CreatePromo(ctx context.Context, dto dto.CreatePromoInput) (uint, error)
 	ExtendPromo(ctx context.Context, dto dto.ExtendPromoInput) error
+	UpdatePromo(ctx context.Context, dto dto.UpdatePromoInput) error
+	// SubmitPromo sends a draft or rejected promo for approval.
+	SubmitPromo(ctx context.Context, promoID uint) error
+	// ApprovePromo makes a promo waiting for approval redeemable.
+	ApprovePromo(ctx context.Context, dto dto.ReviewPromoInput) error
+	RejectPromo(ctx context.Context, dto dto.ReviewPromoInput) error
+	PausePromo(ctx context.Context, promoID uint) error
+	ResumePromo(ctx context.Context, promoID uint) error
+	// EndPromo stops an approved or paused promo before its end date.
+	EndPromo(ctx context.Context, promoID uint) error
+	// ArchivePromo retires the promo for good. The row is kept so the orders
+	// that used it still point to it.
+	ArchivePromo(ctx context.Context, promoID uint) error
+	GetPromoDetail(ctx context.Context, promoID uint) (models.Promo, error)
+	GetPromoCities(ctx context.Context, promoID uint) ([]models.PromoCity, error)
+	// AddPromoCities adds the cities the promo does not have yet and returns
+	// all of its cities.
+	AddPromoCities(ctx context.Context, dto dto.AddPromoCitiesInput) ([]models.PromoCity, error)
+	RemovePromoCity(ctx context.Context, dto dto.RemovePromoCityInput) error
+	// GetPromoHistory returns one page of the changes made to the promo,
+	// newest first, and how many there are.
+	GetPromoHistory(ctx context.Context, dto dto.GetPromoHistoryInput) ([]models.PromoAuditLog, int64, error)
+	GetPromoUsers(ctx context.Context, dto dto.GetPromoUsersInput) ([]models.PromoUser, int64, error)
+	// AddPromoUsers lists the users for a USER_LIST promo and returns how
+	// many were added, unknown users and users already listed are skipped.
+	AddPromoUsers(ctx context.Context, dto dto.PromoUsersInput) (int64, error)
+	// RemovePromoUsers unlists the users and returns how many were listed.
+	RemovePromoUsers(ctx context.Context, dto dto.PromoUsersInput) (int64, error)
+	ListPromos(ctx context.Context, dto dto.ListPromosInput) ([]models.Promo, int64, error)
 	GetPromo(ctx context.Context, dto dto.GetPromoInput) ([]models.Promo, int64, error)
+	RecommendPromos(ctx context.Context, dto dto.RecommendPromoInput) (dto.PromoRecommendation, error)
+	ExplainEligibility(ctx context.Context, dto dto.PromoEligibilityInput) (dto.PromoEligibility, error)
 }
 
 type promoUsecase struct {
@@ -26,6 +63,166 @@ type promoUsecase struct {
 	transactionRepository repository.TransactionRepository
 	cartRepository        repository.CartRepository
 	productRepository     repository.ProductRepository
+	userRepository        repository.UserRepository
+	// maxPromosPerOrder is the same limit the order flow applies, zero means no
+	// limit
+	maxPromosPerOrder int
+}
+
+// ExplainEligibility implements PromoUsecase.
+func (p *promoUsecase) ExplainEligibility(ctx context.Context, input dto.PromoEligibilityInput) (dto.PromoEligibility, error) {
+	promo, err := p.promoRepository.GetPromoByPromoID(ctx, nil, input.PromoId)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return dto.PromoEligibility{}, utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+	}
+
+	if promo.ID == 0 {
+		return dto.PromoEligibility{}, utils.NewCustomError("promo not found", nil, http.StatusNotFound)
+	}
+
+	user, err := p.userRepository.Get(ctx, nil, input.UserId)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return dto.PromoEligibility{}, utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+	}
+
+	if user == nil || user.ID == 0 {
+		return dto.PromoEligibility{}, utils.NewCustomError("user not found", nil, http.StatusNotFound)
+	}
+
+	if promo.Segmentation == constants.PROMOSEGMENTATIONCITY {
+		promo.PromoCities, err = p.promoRepository.GetCities(ctx, nil, promo.ID)
+		if err != nil {
+			return dto.PromoEligibility{}, utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+		}
+	}
+
+	if promo.Segmentation == constants.PROMOSEGMENTATIONUSERLIST {
+		promo.PromoUsers, err = p.promoRepository.FindUsers(ctx, nil, promo.ID, []uint{user.ID})
+		if err != nil {
+			return dto.PromoEligibility{}, utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+		}
+	}
+
+	if promo.Type == constants.PROMOTYPEPERCENTAGE {
+		promo.PromoProducts, err = p.promoRepository.GetProducts(ctx, nil, promo.ID)
+		if err != nil {
+			return dto.PromoEligibility{}, utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+		}
+	}
+
+	if promo.Type == constants.PROMOTYPETIERED {
+		promo.PromoTiers, err = p.promoRepository.GetTiers(ctx, nil, promo.ID)
+		if err != nil {
+			return dto.PromoEligibility{}, utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+		}
+	}
+
+	// the budget check needs the price of the free item
+	if promo.FreeProductID != nil && promo.MaxBudgetAmount != nil {
+		product, err := p.productRepository.Get(ctx, nil, *promo.FreeProductID)
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return dto.PromoEligibility{}, utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+		}
+		if product.ID != 0 {
+			promo.FreeProduct = &product
+		}
+	}
+
+	promo.PromoSchedules, err = p.promoRepository.GetSchedules(ctx, nil, promo.ID)
+	if err != nil {
+		return dto.PromoEligibility{}, utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+	}
+
+	// a user without a cart is checked against an empty one
+	cart, err := p.cartRepository.GetUserCart(ctx, nil, repository.GetUserCartInput{
+		UserId:    input.UserId,
+		Relations: []string{"CartItems", "CartItems.Product"},
+	})
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return dto.PromoEligibility{}, utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+	}
+
+	usageCount, err := p.promoRepository.GetUserUsageCount(ctx, nil, input.UserId, promo.ID)
+	if err != nil {
+		return dto.PromoEligibility{}, utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+	}
+
+	subject := eligibility.Subject{
+		Now:            time.Now(),
+		User:           *user,
+		Lines:          pricing.LinesFromCart(cart),
+		UserUsageCount: usageCount,
+	}
+	if eligibility.NeedsOrderHistory(promo) {
+		if err := loadOrderHistory(ctx, nil, p.userRepository, &subject); err != nil {
+			return dto.PromoEligibility{}, utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+		}
+	}
+
+	checks := eligibility.Explain(promo, subject)
+
+	result := dto.PromoEligibility{
+		PromoId:  promo.ID,
+		Eligible: eligibility.Eligible(checks),
+		Checks:   make([]dto.EligibilityCheck, 0, len(checks)),
+	}
+	for _, check := range checks {
+		result.Checks = append(result.Checks, dto.EligibilityCheck{
+			Name:     check.Name,
+			Passed:   check.Passed,
+			Actual:   check.Actual,
+			Required: check.Required,
+			Message:  check.Message,
+		})
+	}
+
+	return result, nil
+}
+
+// RecommendPromos implements PromoUsecase.
+func (p *promoUsecase) RecommendPromos(ctx context.Context, input dto.RecommendPromoInput) (dto.PromoRecommendation, error) {
+	cart, err := p.cartRepository.GetUserCart(ctx, nil, repository.GetUserCartInput{
+		UserId:    input.UserId,
+		Relations: []string{"CartItems", "CartItems.Product"},
+	})
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return dto.PromoRecommendation{}, utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+	}
+
+	if cart.ID == 0 || len(cart.CartItems) == 0 {
+		return dto.PromoRecommendation{}, utils.NewCustomError("cart not found", nil, http.StatusNotFound)
+	}
+
+	// the same promos the customer sees in the promo list
+	isAvailable := true
+	withinUserLimit := true
+	promos, err := getEligiblePromos(ctx, nil, p.promoRepository, p.userRepository, repository.GetPromoByUserCartInput{
+		Cart:            cart,
+		IsAvailable:     &isAvailable,
+		WithinUserLimit: &withinUserLimit,
+	})
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return dto.PromoRecommendation{}, utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+	}
+
+	// free items are valued at the free product loaded with the promo
+	recommendation := pricing.Recommend(pricing.Input{
+		Lines:   pricing.LinesFromCart(cart),
+		Profile: pricing.Profile{UserID: cart.UserID},
+		Promos:  promos,
+	}, p.maxPromosPerOrder)
+
+	promoIds := []uint{}
+	for _, promo := range recommendation.Breakdown.Promos {
+		promoIds = append(promoIds, promo.PromoID)
+	}
+
+	return dto.PromoRecommendation{
+		PromoIds:       promoIds,
+		Quote:          newOrderQuote(recommendation.Breakdown),
+		FreeItemsValue: recommendation.FreeItemsValue,
+		Saving:         recommendation.Saving,
+	}, nil
 }
 
 // GetPromo implements PromoUsecase.
@@ -33,7 +230,7 @@ func (p *promoUsecase) GetPromo(ctx context.Context, dto dto.GetPromoInput) ([]m
 	// get user cart
 	cart, err := p.cartRepository.GetUserCart(ctx, nil, repository.GetUserCartInput{
 		UserId:    uint(dto.UserId),
-		Relations: []string{"CartItems"},
+		Relations: []string{"CartItems", "CartItems.Product"},
 	})
 	if err != nil && err != gorm.ErrRecordNotFound {
 		return nil, 0, err
@@ -44,23 +241,39 @@ func (p *promoUsecase) GetPromo(ctx context.Context, dto dto.GetPromoInput) ([]m
 	}
 
 	isAvailable := true
-	promos, total, err := p.promoRepository.GetPromoByUserCart(ctx, nil, repository.GetPromoByUserCartInput{
-		Cart:        cart,
-		IsAvailable: &isAvailable,
-		Page:        &dto.Page,
-		PerPage:     &dto.PerPage,
+	withinUserLimit := true
+	promos, err := getEligiblePromos(ctx, nil, p.promoRepository, p.userRepository, repository.GetPromoByUserCartInput{
+		Cart:            cart,
+		IsAvailable:     &isAvailable,
+		WithinUserLimit: &withinUserLimit,
 	})
 	if err != nil && err != gorm.ErrRecordNotFound {
 		return nil, 0, err
 	}
 
-	return promos, total, nil
+	// the user sees the discount of their variant, the same one the order gets
+	for i := range promos {
+		promos[i] = pricing.ApplyVariant(promos[i], cart.UserID)
+	}
+
+	// eligibility is decided after the query, so the page is cut here
+	total := len(promos)
+	start := (dto.Page - 1) * dto.PerPage
+	if start > total {
+		start = total
+	}
+	end := start + dto.PerPage
+	if end > total {
+		end = total
+	}
+
+	return promos[start:end], int64(total), nil
 }
 
 // ExtendPromo implements PromoUsecase.
 func (p *promoUsecase) ExtendPromo(ctx context.Context, dto dto.ExtendPromoInput) error {
 	return p.transactionRepository.Execute(ctx, func(tx *gorm.DB) error {
-		promo, err := p.promoRepository.GetPromoByPromoID(ctx, tx, dto.ID)
+		promo, err := p.promoRepository.GetPromoForUpdate(ctx, tx, dto.ID)
 		if err != nil && err != gorm.ErrRecordNotFound {
 			return err
 		}
@@ -69,18 +282,459 @@ func (p *promoUsecase) ExtendPromo(ctx context.Context, dto dto.ExtendPromoInput
 			return utils.NewCustomError("promo not found", nil, http.StatusNotFound)
 		}
 
+		if promo.Status == constants.PROMOSTATUSARCHIVED {
+			return utils.NewCustomError("promo is archived", nil, http.StatusConflict)
+		}
+
+		if promo.Status == constants.PROMOSTATUSENDED {
+			return utils.NewCustomError("promo was ended", nil, http.StatusConflict)
+		}
+
 		// check if dto.EndDate is less than promo.EndDate
 		if dto.EndDate.Before(promo.EndDate) || dto.EndDate.Before(time.Now()) {
 			return utils.NewCustomError("new end date must be greater than current end date", nil, http.StatusBadRequest)
 		}
 
+		before := promo
 		promo.EndDate = dto.EndDate
 		if !dto.StartDate.IsZero() {
 			promo.StartDate = dto.StartDate
 		}
-		return p.promoRepository.Save(ctx, tx, &promo)
+		if err := p.promoRepository.Save(ctx, tx, &promo); err != nil {
+			return err
+		}
+
+		return p.audit(ctx, tx, promo.ID, constants.PROMOAUDITACTIONEXTEND, diffPromo(before, promo))
+	})
+
+}
+
+// UpdatePromo implements PromoUsecase.
+func (p *promoUsecase) UpdatePromo(ctx context.Context, dto dto.UpdatePromoInput) error {
+	return p.transactionRepository.Execute(ctx, func(tx *gorm.DB) error {
+		promo, err := p.promoRepository.GetPromoForUpdate(ctx, tx, dto.ID)
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+		}
+
+		if promo.ID == 0 {
+			return utils.NewCustomError("promo not found", nil, http.StatusNotFound)
+		}
+
+		if promo.Status == constants.PROMOSTATUSARCHIVED {
+			return utils.NewCustomError("promo is archived", nil, http.StatusConflict)
+		}
+
+		if promo.Status == constants.PROMOSTATUSENDED {
+			return utils.NewCustomError("promo was ended", nil, http.StatusConflict)
+		}
+
+		// the reviewer approves the promo they looked at
+		if promo.Status == constants.PROMOSTATUSPENDINGAPPROVAL {
+			return utils.NewCustomError("promo is waiting for approval", nil, http.StatusConflict)
+		}
+
+		// like on creation, a promo only takes the amounts its type uses
+		isDiscount := promo.Type == constants.PROMOTYPEPERCENTAGE || promo.Type == constants.PROMOTYPEFIXEDAMOUNT
+		invalid := map[string]string{}
+		if dto.MinOrderAmount != nil && !isDiscount {
+			invalid["minOrderAmount"] = "only for PERCENTAGE_DISCOUNT and FIXED_AMOUNT_DISCOUNT"
+		}
+		if dto.DiscountValue != nil && !isDiscount {
+			invalid["discountValue"] = "only for PERCENTAGE_DISCOUNT and FIXED_AMOUNT_DISCOUNT"
+		}
+		if dto.MaxDiscountAmount != nil && promo.Type != constants.PROMOTYPEPERCENTAGE {
+			invalid["maxDiscountAmount"] = "only for PERCENTAGE_DISCOUNT"
+		}
+		if len(invalid) > 0 {
+			return utils.NewCustomError("validation error", invalid, http.StatusBadRequest)
+		}
+
+		before := promo
+		dto.Apply(&promo)
+		changes := diffPromo(before, promo)
+
+		// the reviewer approved what the promo gives away, a change to it
+		// needs a new promo that goes through review again
+		if promo.Status == constants.PROMOSTATUSAPPROVED || promo.Status == constants.PROMOSTATUSPAUSED {
+			fields := make([]string, 0, len(reviewedPromoFields))
+			for _, field := range reviewedPromoFields {
+				if _, ok := changes[field]; ok {
+					fields = append(fields, field)
+				}
+			}
+			if len(fields) > 0 {
+				return utils.NewCustomError("pricing of an approved promo can not be changed", map[string]interface{}{"fields": fields}, http.StatusConflict)
+			}
+		}
+
+		// these depend on the stored promo, the request alone can not tell
+		if promo.Type == constants.PROMOTYPEPERCENTAGE && promo.DiscountValue > 100 {
+			return utils.NewCustomError("discount value of a percentage discount must be no greater than 100", nil, http.StatusBadRequest)
+		}
+		if promo.MaxUsageLimit != nil && *promo.MaxUsageLimit < promo.CurrentUsageCount {
+			return utils.NewCustomError("max usage limit is lower than the current usage count", map[string]interface{}{"currentUsageCount": promo.CurrentUsageCount}, http.StatusBadRequest)
+		}
+		if promo.MaxBudgetAmount != nil && *promo.MaxBudgetAmount < promo.SpentAmount {
+			return utils.NewCustomError("max budget amount is lower than the spent amount", map[string]interface{}{"spentAmount": promo.SpentAmount}, http.StatusBadRequest)
+		}
+
+		if err := p.promoRepository.Save(ctx, tx, &promo); err != nil {
+			return utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+		}
+
+		return p.audit(ctx, tx, promo.ID, constants.PROMOAUDITACTIONUPDATE, changes)
 	})
+}
 
+// SubmitPromo implements PromoUsecase.
+func (p *promoUsecase) SubmitPromo(ctx context.Context, promoID uint) error {
+	return p.changeStatus(ctx, promoID, constants.PROMOSTATUSPENDINGAPPROVAL, "submitted", nil, constants.PROMOSTATUSDRAFT, constants.PROMOSTATUSREJECTED)
+}
+
+// ApprovePromo implements PromoUsecase.
+func (p *promoUsecase) ApprovePromo(ctx context.Context, dto dto.ReviewPromoInput) error {
+	return p.changeStatus(utils.WithActor(ctx, dto.Actor), dto.PromoID, constants.PROMOSTATUSAPPROVED, "approved", func(promo *models.Promo) {
+		reviewedAt := time.Now()
+		promo.ReviewedBy = &dto.Actor
+		promo.ReviewedAt = &reviewedAt
+		promo.RejectionReason = nil
+	}, constants.PROMOSTATUSPENDINGAPPROVAL)
+}
+
+// RejectPromo implements PromoUsecase.
+func (p *promoUsecase) RejectPromo(ctx context.Context, dto dto.ReviewPromoInput) error {
+	return p.changeStatus(utils.WithActor(ctx, dto.Actor), dto.PromoID, constants.PROMOSTATUSREJECTED, "rejected", func(promo *models.Promo) {
+		reviewedAt := time.Now()
+		promo.ReviewedBy = &dto.Actor
+		promo.ReviewedAt = &reviewedAt
+		promo.RejectionReason = dto.Reason
+	}, constants.PROMOSTATUSPENDINGAPPROVAL)
+}
+
+// PausePromo implements PromoUsecase.
+func (p *promoUsecase) PausePromo(ctx context.Context, promoID uint) error {
+	return p.changeStatus(ctx, promoID, constants.PROMOSTATUSPAUSED, "paused", nil, constants.PROMOSTATUSAPPROVED)
+}
+
+// ResumePromo implements PromoUsecase.
+func (p *promoUsecase) ResumePromo(ctx context.Context, promoID uint) error {
+	return p.changeStatus(ctx, promoID, constants.PROMOSTATUSAPPROVED, "resumed", nil, constants.PROMOSTATUSPAUSED)
+}
+
+// EndPromo implements PromoUsecase.
+func (p *promoUsecase) EndPromo(ctx context.Context, promoID uint) error {
+	return p.changeStatus(ctx, promoID, constants.PROMOSTATUSENDED, "ended", nil, constants.PROMOSTATUSAPPROVED, constants.PROMOSTATUSPAUSED)
+}
+
+// ArchivePromo implements PromoUsecase.
+func (p *promoUsecase) ArchivePromo(ctx context.Context, promoID uint) error {
+	return p.changeStatus(ctx, promoID, constants.PROMOSTATUSARCHIVED, "archived", nil,
+		constants.PROMOSTATUSDRAFT, constants.PROMOSTATUSPENDINGAPPROVAL, constants.PROMOSTATUSAPPROVED,
+		constants.PROMOSTATUSREJECTED, constants.PROMOSTATUSPAUSED, constants.PROMOSTATUSENDED)
+}
+
+// changeStatus moves the promo to status when it is in one of the from
+// statuses. action names the change in the conflict error, apply, when not
+// nil, changes the promo along with its status.
+func (p *promoUsecase) changeStatus(ctx context.Context, promoID uint, status string, action string, apply func(promo *models.Promo), from ...string) error {
+	return p.transactionRepository.Execute(ctx, func(tx *gorm.DB) error {
+		promo, err := p.promoRepository.GetPromoForUpdate(ctx, tx, promoID)
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+		}
+
+		if promo.ID == 0 {
+			return utils.NewCustomError("promo not found", nil, http.StatusNotFound)
+		}
+
+		allowed := false
+		for _, s := range from {
+			if promo.Status == s {
+				allowed = true
+			}
+		}
+		if !allowed {
+			return utils.NewCustomError("promo can not be "+action, map[string]interface{}{"status": promo.Status}, http.StatusConflict)
+		}
+
+		before := promo
+		promo.Status = status
+		if apply != nil {
+			apply(&promo)
+		}
+		if err := p.promoRepository.Save(ctx, tx, &promo); err != nil {
+			return utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+		}
+
+		return p.audit(ctx, tx, promo.ID, constants.PROMOAUDITACTIONCHANGESTATUS, diffPromo(before, promo))
+	})
+}
+
+// GetPromoDetail implements PromoUsecase.
+func (p *promoUsecase) GetPromoDetail(ctx context.Context, promoID uint) (models.Promo, error) {
+	promo, err := p.promoRepository.GetPromoDetail(ctx, nil, promoID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return models.Promo{}, utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+	}
+
+	if promo.ID == 0 {
+		return models.Promo{}, utils.NewCustomError("promo not found", nil, http.StatusNotFound)
+	}
+
+	return promo, nil
+}
+
+// GetPromoCities implements PromoUsecase.
+func (p *promoUsecase) GetPromoCities(ctx context.Context, promoID uint) ([]models.PromoCity, error) {
+	promo, err := p.promoRepository.GetPromoByPromoID(ctx, nil, promoID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+	}
+
+	if promo.ID == 0 {
+		return nil, utils.NewCustomError("promo not found", nil, http.StatusNotFound)
+	}
+
+	cities, err := p.promoRepository.GetCities(ctx, nil, promoID)
+	if err != nil {
+		return nil, utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+	}
+
+	return cities, nil
+}
+
+// AddPromoCities implements PromoUsecase.
+func (p *promoUsecase) AddPromoCities(ctx context.Context, dto dto.AddPromoCitiesInput) ([]models.PromoCity, error) {
+	var cities []models.PromoCity
+
+	err := p.transactionRepository.Execute(ctx, func(tx *gorm.DB) error {
+		promo, err := p.getSegmentedPromo(ctx, tx, dto.PromoID, constants.PROMOSEGMENTATIONCITY, true)
+		if err != nil {
+			return err
+		}
+
+		if promo.Status == constants.PROMOSTATUSARCHIVED {
+			return utils.NewCustomError("promo is archived", nil, http.StatusConflict)
+		}
+
+		existing, err := p.promoRepository.GetCities(ctx, tx, promo.ID)
+		if err != nil {
+			return utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+		}
+
+		known := make(map[string]bool, len(existing))
+		for _, city := range existing {
+			known[strings.ToLower(city.City)] = true
+		}
+
+		added := make([]string, 0, len(dto.Cities))
+		for _, city := range utils.NormalizeCities(dto.Cities) {
+			if !known[strings.ToLower(city)] {
+				added = append(added, city)
+			}
+		}
+
+		if len(added) > 0 {
+			if err := p.promoRepository.SaveCities(ctx, tx, promo.ID, added); err != nil {
+				return utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+			}
+		}
+
+		cities, err = p.promoRepository.GetCities(ctx, tx, promo.ID)
+		if err != nil {
+			return utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+		}
+
+		if len(added) == 0 {
+			return nil
+		}
+
+		return p.audit(ctx, tx, promo.ID, constants.PROMOAUDITACTIONADDCITIES, map[string]auditChange{
+			"cities": {Before: promoCityNames(existing), After: promoCityNames(cities)},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cities, nil
+}
+
+// RemovePromoCity implements PromoUsecase.
+func (p *promoUsecase) RemovePromoCity(ctx context.Context, dto dto.RemovePromoCityInput) error {
+	return p.transactionRepository.Execute(ctx, func(tx *gorm.DB) error {
+		promo, err := p.getSegmentedPromo(ctx, tx, dto.PromoID, constants.PROMOSEGMENTATIONCITY, true)
+		if err != nil {
+			return err
+		}
+
+		if promo.Status == constants.PROMOSTATUSARCHIVED {
+			return utils.NewCustomError("promo is archived", nil, http.StatusConflict)
+		}
+
+		cities, err := p.promoRepository.GetCities(ctx, tx, promo.ID)
+		if err != nil {
+			return utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+		}
+
+		// a CITY promo without cities would not be available anywhere
+		if len(cities) == 1 && strings.EqualFold(cities[0].City, utils.NormalizeCity(dto.City)) {
+			return utils.NewCustomError("can not remove the last city of the promo", nil, http.StatusUnprocessableEntity)
+		}
+
+		removed, err := p.promoRepository.RemoveCity(ctx, tx, promo.ID, utils.NormalizeCity(dto.City))
+		if err != nil {
+			return utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+		}
+
+		if !removed {
+			return utils.NewCustomError("promo city not found", nil, http.StatusNotFound)
+		}
+
+		remaining := make([]models.PromoCity, 0, len(cities))
+		for _, city := range cities {
+			if !strings.EqualFold(city.City, utils.NormalizeCity(dto.City)) {
+				remaining = append(remaining, city)
+			}
+		}
+
+		return p.audit(ctx, tx, promo.ID, constants.PROMOAUDITACTIONREMOVECITY, map[string]auditChange{
+			"cities": {Before: promoCityNames(cities), After: promoCityNames(remaining)},
+		})
+	})
+}
+
+// GetPromoHistory implements PromoUsecase.
+func (p *promoUsecase) GetPromoHistory(ctx context.Context, dto dto.GetPromoHistoryInput) ([]models.PromoAuditLog, int64, error) {
+	promo, err := p.promoRepository.GetPromoByPromoID(ctx, nil, dto.PromoID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, 0, utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+	}
+
+	if promo.ID == 0 {
+		return nil, 0, utils.NewCustomError("promo not found", nil, http.StatusNotFound)
+	}
+
+	logs, total, err := p.promoRepository.GetAuditLogs(ctx, nil, dto.PromoID, dto.Page, dto.PerPage)
+	if err != nil {
+		return nil, 0, utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+	}
+
+	return logs, total, nil
+}
+
+// GetPromoUsers implements PromoUsecase.
+func (p *promoUsecase) GetPromoUsers(ctx context.Context, dto dto.GetPromoUsersInput) ([]models.PromoUser, int64, error) {
+	if _, err := p.getSegmentedPromo(ctx, nil, dto.PromoID, constants.PROMOSEGMENTATIONUSERLIST, false); err != nil {
+		return nil, 0, err
+	}
+
+	users, total, err := p.promoRepository.GetUsers(ctx, nil, dto.PromoID, dto.Page, dto.PerPage)
+	if err != nil {
+		return nil, 0, utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+	}
+
+	return users, total, nil
+}
+
+// AddPromoUsers implements PromoUsecase.
+func (p *promoUsecase) AddPromoUsers(ctx context.Context, dto dto.PromoUsersInput) (int64, error) {
+	return p.changePromoUsers(ctx, dto, constants.PROMOAUDITACTIONADDUSERS, p.promoRepository.SaveUsers)
+}
+
+// RemovePromoUsers implements PromoUsecase.
+func (p *promoUsecase) RemovePromoUsers(ctx context.Context, dto dto.PromoUsersInput) (int64, error) {
+	return p.changePromoUsers(ctx, dto, constants.PROMOAUDITACTIONREMOVEUSERS, p.promoRepository.RemoveUsers)
+}
+
+// changePromoUsers applies change to the users of a USER_LIST promo that is
+// not archived and records it as action. The list can be long, so the history
+// only holds which of the given users were listed before and after.
+func (p *promoUsecase) changePromoUsers(ctx context.Context, dto dto.PromoUsersInput, action string, change func(ctx context.Context, tx *gorm.DB, promoID uint, userIDs []uint) (int64, error)) (int64, error) {
+	var changed int64
+
+	err := p.transactionRepository.Execute(ctx, func(tx *gorm.DB) error {
+		promo, err := p.getSegmentedPromo(ctx, tx, dto.PromoID, constants.PROMOSEGMENTATIONUSERLIST, true)
+		if err != nil {
+			return err
+		}
+
+		if promo.Status == constants.PROMOSTATUSARCHIVED {
+			return utils.NewCustomError("promo is archived", nil, http.StatusConflict)
+		}
+
+		listed, err := p.promoRepository.FindUsers(ctx, tx, promo.ID, dto.UserIds)
+		if err != nil {
+			return utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+		}
+
+		changed, err = change(ctx, tx, promo.ID, dto.UserIds)
+		if err != nil {
+			return utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+		}
+
+		if changed == 0 {
+			return nil
+		}
+
+		users, err := p.promoRepository.FindUsers(ctx, tx, promo.ID, dto.UserIds)
+		if err != nil {
+			return utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+		}
+
+		return p.audit(ctx, tx, promo.ID, action, map[string]auditChange{
+			"user_ids": {Before: promoUserIDs(listed), After: promoUserIDs(users)},
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return changed, nil
+}
+
+// getSegmentedPromo returns the promo when it exists and has the segmentation.
+// forUpdate locks the promo row, so concurrent changes to its cities or users
+// are checked against each other's result.
+func (p *promoUsecase) getSegmentedPromo(ctx context.Context, tx *gorm.DB, promoID uint, segmentation string, forUpdate bool) (models.Promo, error) {
+	getPromo := p.promoRepository.GetPromoByPromoID
+	if forUpdate {
+		getPromo = p.promoRepository.GetPromoForUpdate
+	}
+
+	promo, err := getPromo(ctx, tx, promoID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return models.Promo{}, utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+	}
+
+	if promo.ID == 0 {
+		return models.Promo{}, utils.NewCustomError("promo not found", nil, http.StatusNotFound)
+	}
+
+	if promo.Segmentation != segmentation {
+		return models.Promo{}, utils.NewCustomError("promo segmentation is not "+segmentation, map[string]interface{}{"segmentation": promo.Segmentation}, http.StatusUnprocessableEntity)
+	}
+
+	return promo, nil
+}
+
+// ListPromos implements PromoUsecase.
+func (p *promoUsecase) ListPromos(ctx context.Context, dto dto.ListPromosInput) ([]models.Promo, int64, error) {
+	promos, total, err := p.promoRepository.ListPromos(ctx, nil, repository.ListPromosInput{
+		Status:       dto.Status,
+		Type:         dto.Type,
+		Segmentation: dto.Segmentation,
+		StartDate:    dto.StartDate,
+		EndDate:      dto.EndDate,
+		Page:         dto.Page,
+		PerPage:      dto.PerPage,
+	})
+	if err != nil {
+		return nil, 0, utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+	}
+
+	return promos, total, nil
 }
 
 // CreatePromo implements PromoUsecase.
@@ -109,6 +763,27 @@ func (p *promoUsecase) CreatePromo(ctx context.Context, dto dto.CreatePromoInput
 			}
 		}
 
+		// check target product ids
+		for _, productId := range dto.ProductIds {
+			product, err := p.productRepository.Get(ctx, tx, productId)
+			if err != nil {
+				return utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+			}
+			if product.ID == 0 {
+				return utils.NewCustomError("product not found", map[string]interface{}{"productId": productId}, http.StatusNotFound)
+			}
+		}
+
+		if dto.Code != nil {
+			existing, err := p.promoRepository.GetPromoByCode(ctx, tx, *dto.Code)
+			if err != nil && err != gorm.ErrRecordNotFound {
+				return utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+			}
+			if existing.ID != 0 {
+				return utils.NewCustomError("promo code already exists", nil, http.StatusConflict)
+			}
+		}
+
 		promo := dto.CreatePromoModel()
 
 		if err := p.promoRepository.Save(ctx, tx, &promo); err != nil {
@@ -121,6 +796,60 @@ func (p *promoUsecase) CreatePromo(ctx context.Context, dto dto.CreatePromoInput
 			}
 		}
 
+		if promo.Segmentation == constants.PROMOSEGMENTATIONUSERLIST && len(dto.UserIds) > 0 {
+			if _, err := p.promoRepository.SaveUsers(ctx, tx, promo.ID, dto.UserIds); err != nil {
+				return err
+			}
+		}
+
+		if len(dto.ProductIds) > 0 {
+			if err := p.promoRepository.SaveProducts(ctx, tx, promo.ID, dto.ProductIds); err != nil {
+				return err
+			}
+		}
+
+		if tiers := dto.CreatePromoTierModels(promo.ID); len(tiers) > 0 {
+			if err := p.promoRepository.SaveTiers(ctx, tx, tiers); err != nil {
+				return err
+			}
+		}
+
+		if variants := dto.CreatePromoVariantModels(promo.ID); len(variants) > 0 {
+			if err := p.promoRepository.SaveVariants(ctx, tx, variants); err != nil {
+				return err
+			}
+		}
+
+		if schedules := dto.CreatePromoScheduleModels(promo.ID); len(schedules) > 0 {
+			if err := p.promoRepository.SaveSchedules(ctx, tx, schedules); err != nil {
+				return err
+			}
+		}
+
+		// the relations are recorded as they were requested
+		changes := diffPromo(models.Promo{}, promo)
+		if promo.Segmentation == constants.PROMOSEGMENTATIONCITY {
+			changes["cities"] = auditChange{After: dto.Cities}
+		}
+		if len(dto.UserIds) > 0 {
+			changes["user_ids"] = auditChange{After: dto.UserIds}
+		}
+		if len(dto.ProductIds) > 0 {
+			changes["product_ids"] = auditChange{After: dto.ProductIds}
+		}
+		if len(dto.Tiers) > 0 {
+			changes["tiers"] = auditChange{After: dto.Tiers}
+		}
+		if len(dto.Variants) > 0 {
+			changes["variants"] = auditChange{After: dto.Variants}
+		}
+		if len(dto.Schedules) > 0 {
+			changes["schedules"] = auditChange{After: dto.Schedules}
+		}
+		if err := p.audit(ctx, tx, promo.ID, constants.PROMOAUDITACTIONCREATE, changes); err != nil {
+			return err
+		}
+
 		promoId = promo.ID
 
 		return nil
@@ -138,11 +867,15 @@ func NewPromoUsecase(
 	transactionRepository repository.TransactionRepository,
 	cartRepository repository.CartRepository,
 	productRepository repository.ProductRepository,
+	userRepository repository.UserRepository,
+	maxPromosPerOrder int,
 ) PromoUsecase {
 	return &promoUsecase{
 		promoRepository:       promoRepository,
 		transactionRepository: transactionRepository,
 		cartRepository:        cartRepository,
 		productRepository:     productRepository,
+		userRepository:        userRepository,
+		maxPromosPerOrder:     maxPromosPerOrder,
 	}
 }
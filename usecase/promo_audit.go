@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"hangry/domain/models"
+	"hangry/utils"
+	"net/http"
+	"reflect"
+
+	"gorm.io/gorm"
+)
+
+// auditChange is the value of a field before and after a change.
+type auditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// promoFields reads the promo the way the API shows it, keeping only its own
+// columns. The relations are the nested objects and lists, and the id and
+// timestamps are bookkeeping of the row.
+func promoFields(promo models.Promo) map[string]interface{} {
+	data, _ := json.Marshal(promo)
+
+	fields := map[string]interface{}{}
+	_ = json.Unmarshal(data, &fields)
+
+	for name, value := range fields {
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			delete(fields, name)
+		}
+	}
+	delete(fields, "id")
+	delete(fields, "created_at")
+	delete(fields, "updated_at")
+
+	return fields
+}
+
+// diffPromo lists the fields whose value differs between before and after.
+func diffPromo(before, after models.Promo) map[string]auditChange {
+	beforeFields, afterFields := promoFields(before), promoFields(after)
+
+	changes := map[string]auditChange{}
+	for name, value := range afterFields {
+		if !reflect.DeepEqual(beforeFields[name], value) {
+			changes[name] = auditChange{Before: beforeFields[name], After: value}
+		}
+	}
+
+	return changes
+}
+
+// audit appends the changes to the promo's history, crediting the actor of
+// ctx. Nothing is written when nothing changed.
+func (p *promoUsecase) audit(ctx context.Context, tx *gorm.DB, promoID uint, action string, changes map[string]auditChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+	}
+
+	log := models.PromoAuditLog{
+		PromoID: promoID,
+		Action:  action,
+		Actor:   utils.Actor(ctx),
+		Changes: data,
+	}
+	if err := p.promoRepository.SaveAuditLog(ctx, tx, &log); err != nil {
+		return utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+func promoCityNames(cities []models.PromoCity) []string {
+	names := make([]string, len(cities))
+	for i, city := range cities {
+		names[i] = city.City
+	}
+
+	return names
+}
+
+func promoUserIDs(users []models.PromoUser) []uint {
+	ids := make([]uint, len(users))
+	for i, user := range users {
+		ids[i] = user.UserID
+	}
+
+	return ids
+}
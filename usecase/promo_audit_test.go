@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"hangry/constants"
+	"hangry/domain/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_diffPromo(t *testing.T) {
+	endDate := time.Date(2024, 6, 30, 23, 59, 59, 0, time.UTC)
+	code := "HEMAT20"
+	stored := models.Promo{
+		ID:          1,
+		Name:        "Payday",
+		Type:        constants.PROMOTYPEPERCENTAGE,
+		EndDate:     endDate,
+		Status:      constants.PROMOSTATUSAPPROVED,
+		PromoCities: []models.PromoCity{{ID: 1, PromoID: 1, City: "Jakarta"}},
+	}
+
+	tests := []struct {
+		name   string
+		before models.Promo
+		after  func(promo models.Promo) models.Promo
+		want   map[string]auditChange
+	}{
+		{
+			name:   "nothing changed",
+			before: stored,
+			after: func(promo models.Promo) models.Promo {
+				promo.UpdatedAt = time.Now()
+				promo.PromoCities = nil
+				return promo
+			},
+			want: map[string]auditChange{},
+		},
+		{
+			name:   "extended",
+			before: stored,
+			after: func(promo models.Promo) models.Promo {
+				promo.EndDate = endDate.AddDate(0, 1, 0)
+				return promo
+			},
+			want: map[string]auditChange{
+				"end_date": {Before: "2024-06-30T23:59:59Z", After: "2024-07-30T23:59:59Z"},
+			},
+		},
+		{
+			name:   "code set and paused",
+			before: stored,
+			after: func(promo models.Promo) models.Promo {
+				promo.Code = &code
+				promo.Status = constants.PROMOSTATUSPAUSED
+				return promo
+			},
+			want: map[string]auditChange{
+				"code":   {Before: nil, After: "HEMAT20"},
+				"status": {Before: constants.PROMOSTATUSAPPROVED, After: constants.PROMOSTATUSPAUSED},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffPromo(tt.before, tt.after(tt.before)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffPromo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
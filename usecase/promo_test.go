@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"hangry/constants"
@@ -9,6 +10,8 @@ import (
 	"hangry/domain/models"
 	"hangry/repository"
 	repo_mock "hangry/repository/mocks"
+	"hangry/utils"
+	"net/http"
 	"reflect"
 	"testing"
 	"time"
@@ -33,6 +36,7 @@ func Test_promoUsecase_GetPromo(t *testing.T) {
 		promoRepoMock       func(*repo_mock.MockPromoRepository)
 		cartRepoMock        func(*repo_mock.MockCartRepository)
 		productRepoMock     func(*repo_mock.MockProductRepository)
+		userRepoMock        func(*repo_mock.MockUserRepository)
 	}{
 		// TODO: Add test cases.
 		{
@@ -55,11 +59,13 @@ func Test_promoUsecase_GetPromo(t *testing.T) {
 			cartRepoMock: func(r *repo_mock.MockCartRepository) {
 				r.EXPECT().GetUserCart(gomock.Any(), nil, repository.GetUserCartInput{
 					UserId:    uint(1),
-					Relations: []string{"CartItems"},
+					Relations: []string{"CartItems", "CartItems.Product"},
 				}).Return(models.Cart{}, errors.New("error"))
 			},
 			productRepoMock: func(r *repo_mock.MockProductRepository) {
 			},
+			userRepoMock: func(r *repo_mock.MockUserRepository) {
+			},
 		},
 		{
 			name: "cart not found",
@@ -81,11 +87,13 @@ func Test_promoUsecase_GetPromo(t *testing.T) {
 			cartRepoMock: func(r *repo_mock.MockCartRepository) {
 				r.EXPECT().GetUserCart(gomock.Any(), nil, repository.GetUserCartInput{
 					UserId:    uint(1),
-					Relations: []string{"CartItems"},
+					Relations: []string{"CartItems", "CartItems.Product"},
 				}).Return(models.Cart{}, nil)
 			},
 			productRepoMock: func(r *repo_mock.MockProductRepository) {
 			},
+			userRepoMock: func(r *repo_mock.MockUserRepository) {
+			},
 		},
 		{
 			name: "err get promo by user cart",
@@ -109,20 +117,18 @@ func Test_promoUsecase_GetPromo(t *testing.T) {
 					UserID: 1,
 				}
 				isAvailable := true
-				page := 1
-				perPage := 10
+				withinUserLimit := true
 
 				r.EXPECT().GetPromoByUserCart(gomock.Any(), nil, gomock.Eq(repository.GetPromoByUserCartInput{
-					Cart:        cart,
-					IsAvailable: &isAvailable,
-					Page:        &page,
-					PerPage:     &perPage,
-				})).Return([]models.Promo{}, int64(0), errors.New("error"))
+					Cart:            cart,
+					IsAvailable:     &isAvailable,
+					WithinUserLimit: &withinUserLimit,
+				})).Return([]models.Promo{}, errors.New("error"))
 			},
 			cartRepoMock: func(r *repo_mock.MockCartRepository) {
 				r.EXPECT().GetUserCart(gomock.Any(), nil, repository.GetUserCartInput{
 					UserId:    uint(1),
-					Relations: []string{"CartItems"},
+					Relations: []string{"CartItems", "CartItems.Product"},
 				}).Return(models.Cart{
 					ID:     1,
 					UserID: 1,
@@ -130,6 +136,8 @@ func Test_promoUsecase_GetPromo(t *testing.T) {
 			},
 			productRepoMock: func(r *repo_mock.MockProductRepository) {
 			},
+			userRepoMock: func(r *repo_mock.MockUserRepository) {
+			},
 		},
 		{
 			name: "success",
@@ -143,7 +151,9 @@ func Test_promoUsecase_GetPromo(t *testing.T) {
 			},
 			want: []models.Promo{
 				{
-					ID: 1,
+					ID:           1,
+					Segmentation: constants.PROMOSEGMENTATIONALL,
+					Type:         constants.PROMOTYPEPERCENTAGE,
 				},
 			},
 			want1:   1,
@@ -157,20 +167,21 @@ func Test_promoUsecase_GetPromo(t *testing.T) {
 					UserID: 1,
 				}
 				isAvailable := true
-				page := 1
-				perPage := 10
+				withinUserLimit := true
 
 				r.EXPECT().GetPromoByUserCart(gomock.Any(), nil, gomock.Eq(repository.GetPromoByUserCartInput{
-					Cart:        cart,
-					IsAvailable: &isAvailable,
-					Page:        &page,
-					PerPage:     &perPage,
-				})).Return([]models.Promo{{ID: 1}}, int64(1), nil)
+					Cart:            cart,
+					IsAvailable:     &isAvailable,
+					WithinUserLimit: &withinUserLimit,
+				})).Return([]models.Promo{
+					{ID: 1, Segmentation: constants.PROMOSEGMENTATIONALL, Type: constants.PROMOTYPEPERCENTAGE},
+					{ID: 2, Segmentation: constants.PROMOSEGMENTATIONLOYALUSER, Type: constants.PROMOTYPEPERCENTAGE},
+				}, nil)
 			},
 			cartRepoMock: func(r *repo_mock.MockCartRepository) {
 				r.EXPECT().GetUserCart(gomock.Any(), nil, repository.GetUserCartInput{
 					UserId:    uint(1),
-					Relations: []string{"CartItems"},
+					Relations: []string{"CartItems", "CartItems.Product"},
 				}).Return(models.Cart{
 					ID:     1,
 					UserID: 1,
@@ -178,6 +189,49 @@ func Test_promoUsecase_GetPromo(t *testing.T) {
 			},
 			productRepoMock: func(r *repo_mock.MockProductRepository) {
 			},
+			userRepoMock: func(r *repo_mock.MockUserRepository) {
+				r.EXPECT().Get(gomock.Any(), nil, uint(1)).Return(&models.User{ID: 1}, nil)
+			},
+		},
+		{
+			name: "success second page",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.GetPromoInput{
+					UserId:  1,
+					Page:    2,
+					PerPage: 1,
+				},
+			},
+			want: []models.Promo{
+				{
+					ID:           3,
+					Segmentation: constants.PROMOSEGMENTATIONLOYALUSER,
+					Type:         constants.PROMOTYPEPERCENTAGE,
+				},
+			},
+			want1:   2,
+			wantErr: false,
+			transactionRepoMock: func(r *repo_mock.MockTransactionRepository) {
+			},
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoByUserCart(gomock.Any(), nil, gomock.Any()).Return([]models.Promo{
+					{ID: 1, Segmentation: constants.PROMOSEGMENTATIONALL, Type: constants.PROMOTYPEPERCENTAGE},
+					{ID: 2, Segmentation: constants.PROMOSEGMENTATIONNEWUSER, Type: constants.PROMOTYPEPERCENTAGE},
+					{ID: 3, Segmentation: constants.PROMOSEGMENTATIONLOYALUSER, Type: constants.PROMOTYPEPERCENTAGE},
+				}, nil)
+			},
+			cartRepoMock: func(r *repo_mock.MockCartRepository) {
+				r.EXPECT().GetUserCart(gomock.Any(), nil, gomock.Any()).Return(models.Cart{
+					ID:     1,
+					UserID: 1,
+				}, nil)
+			},
+			productRepoMock: func(r *repo_mock.MockProductRepository) {
+			},
+			userRepoMock: func(r *repo_mock.MockUserRepository) {
+				r.EXPECT().Get(gomock.Any(), nil, uint(1)).Return(&models.User{ID: 1, IsLoyal: true}, nil)
+			},
 		},
 	}
 	for _, tt := range tests {
@@ -198,7 +252,10 @@ func Test_promoUsecase_GetPromo(t *testing.T) {
 			promoRepo := repo_mock.NewMockPromoRepository(ctrl)
 			tt.promoRepoMock(promoRepo)
 
-			usecase := NewPromoUsecase(promoRepo, transactionRepo, cartRepo, productRepo)
+			userRepo := repo_mock.NewMockUserRepository(ctrl)
+			tt.userRepoMock(userRepo)
+
+			usecase := NewPromoUsecase(promoRepo, transactionRepo, cartRepo, productRepo, userRepo, 0)
 
 			res, count, err := usecase.GetPromo(context.Background(), tt.args.dto)
 			if (err != nil) != tt.wantErr {
@@ -253,7 +310,7 @@ func Test_promoUsecase_ExtendPromo(t *testing.T) {
 					})
 			},
 			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
-				r.EXPECT().GetPromoByPromoID(gomock.Any(), nil, uint(1)).Return(models.Promo{}, errors.New("error"))
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(models.Promo{}, errors.New("error"))
 			},
 		},
 		{
@@ -274,7 +331,28 @@ func Test_promoUsecase_ExtendPromo(t *testing.T) {
 					})
 			},
 			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
-				r.EXPECT().GetPromoByPromoID(gomock.Any(), nil, uint(1)).Return(models.Promo{}, nil)
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(models.Promo{}, nil)
+			},
+		},
+		{
+			name: "promo is archived",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.ExtendPromoInput{
+					ID:        1,
+					StartDate: startDate,
+					EndDate:   endDate,
+				},
+			},
+			wantErr: true,
+			transactionRepoMock: func(r *repo_mock.MockTransactionRepository) {
+				r.EXPECT().Execute(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+						return fn(nil)
+					})
+			},
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(models.Promo{ID: 1, Status: constants.PROMOSTATUSARCHIVED}, nil)
 			},
 		},
 		{
@@ -295,7 +373,7 @@ func Test_promoUsecase_ExtendPromo(t *testing.T) {
 					})
 			},
 			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
-				r.EXPECT().GetPromoByPromoID(gomock.Any(), nil, uint(1)).Return(models.Promo{ID: 1, EndDate: time.Now()}, nil)
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(models.Promo{ID: 1, EndDate: time.Now()}, nil)
 			},
 		},
 		{
@@ -316,7 +394,7 @@ func Test_promoUsecase_ExtendPromo(t *testing.T) {
 					})
 			},
 			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
-				r.EXPECT().GetPromoByPromoID(gomock.Any(), nil, uint(1)).Return(
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(
 					models.Promo{
 						ID:      1,
 						EndDate: time.Now().Add(-time.Hour * 24 * 1)},
@@ -330,7 +408,7 @@ func Test_promoUsecase_ExtendPromo(t *testing.T) {
 			},
 		},
 		{
-			name: "err save promo",
+			name: "success",
 			args: args{
 				ctx: context.Background(),
 				dto: dto.ExtendPromoInput{
@@ -347,7 +425,7 @@ func Test_promoUsecase_ExtendPromo(t *testing.T) {
 					})
 			},
 			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
-				r.EXPECT().GetPromoByPromoID(gomock.Any(), nil, uint(1)).Return(
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(
 					models.Promo{
 						ID:      1,
 						EndDate: time.Now().Add(-time.Hour * 24 * 1)},
@@ -358,6 +436,20 @@ func Test_promoUsecase_ExtendPromo(t *testing.T) {
 					EndDate:   endDate,
 				})
 				r.EXPECT().Save(gomock.Any(), gomock.Any(), promo).Return(nil)
+				r.EXPECT().SaveAuditLog(gomock.Any(), nil, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tx *gorm.DB, log *models.PromoAuditLog) error {
+						changes := map[string]auditChange{}
+						if err := json.Unmarshal(log.Changes, &changes); err != nil {
+							t.Fatal(err)
+						}
+						if log.PromoID != 1 || log.Action != constants.PROMOAUDITACTIONEXTEND || log.Actor != nil || len(changes) != 2 {
+							t.Errorf("audit log = %+v, changes %v", log, changes)
+						}
+						if _, ok := changes["end_date"]; !ok {
+							t.Errorf("audit log changes = %v, want end_date", changes)
+						}
+						return nil
+					})
 			},
 		},
 	}
@@ -373,7 +465,7 @@ func Test_promoUsecase_ExtendPromo(t *testing.T) {
 			promoRepo := repo_mock.NewMockPromoRepository(ctrl)
 			tt.promoRepoMock(promoRepo)
 
-			usecase := NewPromoUsecase(promoRepo, transactionRepo, nil, nil)
+			usecase := NewPromoUsecase(promoRepo, transactionRepo, nil, nil, nil, 0)
 
 			err := usecase.ExtendPromo(context.Background(), tt.args.dto)
 			if (err != nil) != tt.wantErr {
@@ -385,170 +477,1162 @@ func Test_promoUsecase_ExtendPromo(t *testing.T) {
 	}
 }
 
-func Test_promoUsecase_CreatePromo(t *testing.T) {
-	type args struct {
-		ctx context.Context
-		dto dto.CreatePromoInput
+func Test_promoUsecase_UpdatePromo(t *testing.T) {
+	name := "Payday Sale"
+	discountValue := float64(150)
+	maxUsageLimit := 5
+	maxBudgetAmount := float64(1000)
+	newDiscountValue := float64(20)
+	raisedBudgetAmount := float64(10000)
+	storedPromo := models.Promo{
+		ID:                1,
+		Name:              "Summer Sale",
+		Type:              constants.PROMOTYPEPERCENTAGE,
+		DiscountValue:     10,
+		CurrentUsageCount: 10,
+		SpentAmount:       5000,
+		Status:            constants.PROMOSTATUSAPPROVED,
 	}
-	buyProductId := int(1)
-	freeProductId := int(2)
-	cities := []string{"Jakarta", "Bogor"}
+	draftPromo := storedPromo
+	draftPromo.Status = constants.PROMOSTATUSDRAFT
+	minOrderAmount := float64(50000)
+	maxDiscountAmount := float64(20000)
+
 	tests := []struct {
-		name                string
-		args                args
-		want                uint
-		wantErr             bool
-		transactionRepoMock func(*repo_mock.MockTransactionRepository)
-		promoRepoMock       func(*repo_mock.MockPromoRepository)
-		productRepoMock     func(*repo_mock.MockProductRepository)
+		name          string
+		dto           dto.UpdatePromoInput
+		wantErr       bool
+		wantStatus    int
+		promoRepoMock func(*repo_mock.MockPromoRepository)
 	}{
 		{
-			name: "err get product",
-			args: args{
-				ctx: context.Background(),
-				dto: dto.CreatePromoInput{
-					Type:         constants.PROMOTYPEBUYXGETY,
-					BuyProductId: &buyProductId,
-				},
+			name:       "promo not found",
+			dto:        dto.UpdatePromoInput{ID: 1, Name: &name},
+			wantErr:    true,
+			wantStatus: http.StatusNotFound,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(models.Promo{}, gorm.ErrRecordNotFound)
 			},
-			want:    0,
-			wantErr: true,
-			transactionRepoMock: func(r *repo_mock.MockTransactionRepository) {
-				r.EXPECT().Execute(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
-					return fn(nil)
-				})
+		},
+		{
+			name:       "promo is archived",
+			dto:        dto.UpdatePromoInput{ID: 1, Name: &name},
+			wantErr:    true,
+			wantStatus: http.StatusConflict,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				archived := storedPromo
+				archived.Status = constants.PROMOSTATUSARCHIVED
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(archived, nil)
 			},
+		},
+		{
+			name:       "promo is waiting for approval",
+			dto:        dto.UpdatePromoInput{ID: 1, Name: &name},
+			wantErr:    true,
+			wantStatus: http.StatusConflict,
 			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				pending := storedPromo
+				pending.Status = constants.PROMOSTATUSPENDINGAPPROVAL
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(pending, nil)
 			},
-			productRepoMock: func(r *repo_mock.MockProductRepository) {
-				r.EXPECT().Get(gomock.Any(), nil, uint(buyProductId)).Return(models.Product{}, errors.New("error"))
+		},
+		{
+			name:       "discount value of a buy x get y promo",
+			dto:        dto.UpdatePromoInput{ID: 1, DiscountValue: &newDiscountValue},
+			wantErr:    true,
+			wantStatus: http.StatusBadRequest,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				buyXGetY := draftPromo
+				buyXGetY.Type = constants.PROMOTYPEBUYXGETY
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(buyXGetY, nil)
 			},
 		},
 		{
-			name: "err buy product not found",
-			args: args{
-				ctx: context.Background(),
-				dto: dto.CreatePromoInput{
-					Type:         constants.PROMOTYPEBUYXGETY,
-					BuyProductId: &buyProductId,
-				},
+			name:       "min order amount of a tiered promo",
+			dto:        dto.UpdatePromoInput{ID: 1, MinOrderAmount: &minOrderAmount},
+			wantErr:    true,
+			wantStatus: http.StatusBadRequest,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				tiered := draftPromo
+				tiered.Type = constants.PROMOTYPETIERED
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(tiered, nil)
 			},
-			want:    0,
-			wantErr: true,
-			transactionRepoMock: func(r *repo_mock.MockTransactionRepository) {
-				r.EXPECT().Execute(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
-					return fn(nil)
-				})
+		},
+		{
+			name:       "max discount amount of a fixed amount promo",
+			dto:        dto.UpdatePromoInput{ID: 1, MaxDiscountAmount: &maxDiscountAmount},
+			wantErr:    true,
+			wantStatus: http.StatusBadRequest,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				fixedAmount := draftPromo
+				fixedAmount.Type = constants.PROMOTYPEFIXEDAMOUNT
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(fixedAmount, nil)
 			},
+		},
+		{
+			name:       "discount of an approved promo",
+			dto:        dto.UpdatePromoInput{ID: 1, DiscountValue: &newDiscountValue},
+			wantErr:    true,
+			wantStatus: http.StatusConflict,
 			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(storedPromo, nil)
 			},
-			productRepoMock: func(r *repo_mock.MockProductRepository) {
-				r.EXPECT().Get(gomock.Any(), nil, uint(buyProductId)).Return(models.Product{}, nil)
+		},
+		{
+			name:       "budget of a paused promo",
+			dto:        dto.UpdatePromoInput{ID: 1, MaxBudgetAmount: &raisedBudgetAmount},
+			wantErr:    true,
+			wantStatus: http.StatusConflict,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				paused := storedPromo
+				paused.Status = constants.PROMOSTATUSPAUSED
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(paused, nil)
 			},
 		},
 		{
-			name: "err get free product",
-			args: args{
-				ctx: context.Background(),
-				dto: dto.CreatePromoInput{
-					Type:          constants.PROMOTYPEBUYXGETY,
-					BuyProductId:  &buyProductId,
-					FreeProductId: &freeProductId,
-				},
+			name:    "discount of a draft promo",
+			dto:     dto.UpdatePromoInput{ID: 1, DiscountValue: &newDiscountValue},
+			wantErr: false,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(draftPromo, nil)
+				updated := draftPromo
+				updated.DiscountValue = newDiscountValue
+				r.EXPECT().Save(gomock.Any(), nil, &updated).Return(nil)
+				r.EXPECT().SaveAuditLog(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 			},
-			want:    0,
-			wantErr: true,
-			transactionRepoMock: func(r *repo_mock.MockTransactionRepository) {
-				r.EXPECT().Execute(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
-					return fn(nil)
-				})
+		},
+		{
+			name:       "percentage discount value over 100",
+			dto:        dto.UpdatePromoInput{ID: 1, DiscountValue: &discountValue},
+			wantErr:    true,
+			wantStatus: http.StatusBadRequest,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(draftPromo, nil)
 			},
+		},
+		{
+			name:       "max usage limit lower than the current usage count",
+			dto:        dto.UpdatePromoInput{ID: 1, MaxUsageLimit: &maxUsageLimit},
+			wantErr:    true,
+			wantStatus: http.StatusBadRequest,
 			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(storedPromo, nil)
 			},
-			productRepoMock: func(r *repo_mock.MockProductRepository) {
-				r.EXPECT().Get(gomock.Any(), nil, uint(buyProductId)).Return(models.Product{
-					ID: uint(buyProductId),
-				}, nil)
-
-				r.EXPECT().Get(gomock.Any(), nil, uint(freeProductId)).Return(models.Product{}, errors.New("error"))
+		},
+		{
+			name:       "max budget amount lower than the spent amount",
+			dto:        dto.UpdatePromoInput{ID: 1, MaxBudgetAmount: &maxBudgetAmount},
+			wantErr:    true,
+			wantStatus: http.StatusBadRequest,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(draftPromo, nil)
 			},
 		},
 		{
-			name: "err free product not found",
-			args: args{
-				ctx: context.Background(),
-				dto: dto.CreatePromoInput{
-					Type:          constants.PROMOTYPEBUYXGETY,
-					BuyProductId:  &buyProductId,
-					FreeProductId: &freeProductId,
-				},
+			name:       "err save promo",
+			dto:        dto.UpdatePromoInput{ID: 1, Name: &name},
+			wantErr:    true,
+			wantStatus: http.StatusInternalServerError,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(storedPromo, nil)
+				r.EXPECT().Save(gomock.Any(), nil, gomock.Any()).Return(errors.New("error"))
 			},
-			want:    0,
-			wantErr: true,
-			transactionRepoMock: func(r *repo_mock.MockTransactionRepository) {
-				r.EXPECT().Execute(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+		},
+		{
+			name:    "success",
+			dto:     dto.UpdatePromoInput{ID: 1, Name: &name},
+			wantErr: false,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().SaveAuditLog(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(storedPromo, nil)
+				updated := storedPromo
+				updated.Name = name
+				r.EXPECT().Save(gomock.Any(), nil, &updated).Return(nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			transactionRepo := repo_mock.NewMockTransactionRepository(ctrl)
+			transactionRepo.EXPECT().Execute(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
 					return fn(nil)
 				})
+
+			promoRepo := repo_mock.NewMockPromoRepository(ctrl)
+			tt.promoRepoMock(promoRepo)
+
+			usecase := NewPromoUsecase(promoRepo, transactionRepo, nil, nil, nil, 0)
+
+			err := usecase.UpdatePromo(context.Background(), tt.dto)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("promoUsecase.UpdatePromo() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if customErr, ok := err.(*utils.CustomError); ok && customErr.StatusCode != tt.wantStatus {
+				t.Errorf("promoUsecase.UpdatePromo() status = %v, want %v", customErr.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_promoUsecase_ChangeStatus(t *testing.T) {
+	promoWithStatus := func(status string) models.Promo {
+		return models.Promo{ID: 1, Status: status}
+	}
+
+	tests := []struct {
+		name          string
+		change        func(PromoUsecase) error
+		wantErr       bool
+		wantStatus    int
+		promoRepoMock func(*repo_mock.MockPromoRepository)
+	}{
+		{
+			name: "submit draft promo",
+			change: func(u PromoUsecase) error {
+				return u.SubmitPromo(context.Background(), 1)
 			},
+			wantErr: false,
 			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().SaveAuditLog(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(promoWithStatus(constants.PROMOSTATUSDRAFT), nil)
+				pending := promoWithStatus(constants.PROMOSTATUSPENDINGAPPROVAL)
+				r.EXPECT().Save(gomock.Any(), nil, &pending).Return(nil)
 			},
-			productRepoMock: func(r *repo_mock.MockProductRepository) {
-				r.EXPECT().Get(gomock.Any(), nil, uint(buyProductId)).Return(models.Product{
-					ID: uint(buyProductId),
-				}, nil)
-
-				r.EXPECT().Get(gomock.Any(), nil, uint(freeProductId)).Return(models.Product{}, nil)
+		},
+		{
+			name: "submit approved promo",
+			change: func(u PromoUsecase) error {
+				return u.SubmitPromo(context.Background(), 1)
+			},
+			wantErr:    true,
+			wantStatus: http.StatusConflict,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(promoWithStatus(constants.PROMOSTATUSAPPROVED), nil)
 			},
 		},
 		{
-			name: "err save promo",
-			args: args{
-				ctx: context.Background(),
-				dto: dto.CreatePromoInput{
-					Type:          constants.PROMOTYPEBUYXGETY,
-					BuyProductId:  &buyProductId,
-					FreeProductId: &freeProductId,
-				},
+			name: "approve pending promo",
+			change: func(u PromoUsecase) error {
+				return u.ApprovePromo(context.Background(), dto.ReviewPromoInput{PromoID: 1, Actor: "jane@hangry.id"})
 			},
-			want:    0,
-			wantErr: true,
-			transactionRepoMock: func(r *repo_mock.MockTransactionRepository) {
-				r.EXPECT().Execute(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
-					return fn(nil)
-				})
+			wantErr: false,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				rejected := promoWithStatus(constants.PROMOSTATUSPENDINGAPPROVAL)
+				reason := "discount is too high"
+				rejected.RejectionReason = &reason
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(rejected, nil)
+				r.EXPECT().Save(gomock.Any(), nil, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tx *gorm.DB, promo *models.Promo) error {
+						if promo.Status != constants.PROMOSTATUSAPPROVED || promo.ReviewedBy == nil || *promo.ReviewedBy != "jane@hangry.id" ||
+							promo.ReviewedAt == nil || promo.RejectionReason != nil {
+							t.Errorf("approved promo = %+v", promo)
+						}
+						return nil
+					})
+				r.EXPECT().SaveAuditLog(gomock.Any(), nil, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tx *gorm.DB, log *models.PromoAuditLog) error {
+						if log.Action != constants.PROMOAUDITACTIONCHANGESTATUS || log.Actor == nil || *log.Actor != "jane@hangry.id" {
+							t.Errorf("audit log = %+v", log)
+						}
+						return nil
+					})
 			},
+		},
+		{
+			name: "approve draft promo",
+			change: func(u PromoUsecase) error {
+				return u.ApprovePromo(context.Background(), dto.ReviewPromoInput{PromoID: 1, Actor: "jane@hangry.id"})
+			},
+			wantErr:    true,
+			wantStatus: http.StatusConflict,
 			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
-				buyProductId := uint(buyProductId)
-				freeProductId := uint(freeProductId)
-				promo := gomock.Eq(&models.Promo{
-					Type:              constants.PROMOTYPEBUYXGETY,
-					BuyProductID:      &buyProductId,
-					FreeProductID:     &freeProductId,
-					CurrentUsageCount: 0,
-				})
-				r.EXPECT().Save(gomock.Any(), gomock.Any(), promo).Return(errors.New("error"))
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(promoWithStatus(constants.PROMOSTATUSDRAFT), nil)
 			},
-			productRepoMock: func(r *repo_mock.MockProductRepository) {
-				r.EXPECT().Get(gomock.Any(), nil, uint(buyProductId)).Return(models.Product{
-					ID: uint(buyProductId),
-				}, nil)
-
-				r.EXPECT().Get(gomock.Any(), nil, uint(freeProductId)).Return(models.Product{
-					ID: uint(freeProductId),
-				}, nil)
+		},
+		{
+			name: "reject pending promo",
+			change: func(u PromoUsecase) error {
+				reason := "discount is too high"
+				return u.RejectPromo(context.Background(), dto.ReviewPromoInput{PromoID: 1, Actor: "jane@hangry.id", Reason: &reason})
+			},
+			wantErr: false,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(promoWithStatus(constants.PROMOSTATUSPENDINGAPPROVAL), nil)
+				r.EXPECT().Save(gomock.Any(), nil, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tx *gorm.DB, promo *models.Promo) error {
+						if promo.Status != constants.PROMOSTATUSREJECTED || promo.ReviewedBy == nil || *promo.ReviewedBy != "jane@hangry.id" ||
+							promo.ReviewedAt == nil || promo.RejectionReason == nil || *promo.RejectionReason != "discount is too high" {
+							t.Errorf("rejected promo = %+v", promo)
+						}
+						return nil
+					})
+				r.EXPECT().SaveAuditLog(gomock.Any(), nil, gomock.Any()).Return(nil)
 			},
 		},
 		{
-			name: "err save promo cities",
-			args: args{
-				ctx: context.Background(),
-				dto: dto.CreatePromoInput{
-					Type:          constants.PROMOTYPEBUYXGETY,
-					BuyProductId:  &buyProductId,
-					FreeProductId: &freeProductId,
-					Segmentation:  constants.PROMOSEGMENTATIONCITY,
-					Cities:        cities,
-				},
+			name: "submit rejected promo",
+			change: func(u PromoUsecase) error {
+				return u.SubmitPromo(context.Background(), 1)
+			},
+			wantErr: false,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().SaveAuditLog(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(promoWithStatus(constants.PROMOSTATUSREJECTED), nil)
+				pending := promoWithStatus(constants.PROMOSTATUSPENDINGAPPROVAL)
+				r.EXPECT().Save(gomock.Any(), nil, &pending).Return(nil)
+			},
+		},
+		{
+			name: "pause promo not found",
+			change: func(u PromoUsecase) error {
+				return u.PausePromo(context.Background(), 1)
+			},
+			wantErr:    true,
+			wantStatus: http.StatusNotFound,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(models.Promo{}, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name: "pause approved promo",
+			change: func(u PromoUsecase) error {
+				return u.PausePromo(context.Background(), 1)
+			},
+			wantErr: false,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().SaveAuditLog(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(promoWithStatus(constants.PROMOSTATUSAPPROVED), nil)
+				paused := promoWithStatus(constants.PROMOSTATUSPAUSED)
+				r.EXPECT().Save(gomock.Any(), nil, &paused).Return(nil)
+			},
+		},
+		{
+			name: "pause paused promo",
+			change: func(u PromoUsecase) error {
+				return u.PausePromo(context.Background(), 1)
+			},
+			wantErr:    true,
+			wantStatus: http.StatusConflict,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(promoWithStatus(constants.PROMOSTATUSPAUSED), nil)
+			},
+		},
+		{
+			name: "resume paused promo",
+			change: func(u PromoUsecase) error {
+				return u.ResumePromo(context.Background(), 1)
+			},
+			wantErr: false,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().SaveAuditLog(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(promoWithStatus(constants.PROMOSTATUSPAUSED), nil)
+				approved := promoWithStatus(constants.PROMOSTATUSAPPROVED)
+				r.EXPECT().Save(gomock.Any(), nil, &approved).Return(nil)
+			},
+		},
+		{
+			name: "resume archived promo",
+			change: func(u PromoUsecase) error {
+				return u.ResumePromo(context.Background(), 1)
+			},
+			wantErr:    true,
+			wantStatus: http.StatusConflict,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(promoWithStatus(constants.PROMOSTATUSARCHIVED), nil)
+			},
+		},
+		{
+			name: "end paused promo",
+			change: func(u PromoUsecase) error {
+				return u.EndPromo(context.Background(), 1)
+			},
+			wantErr: false,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().SaveAuditLog(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(promoWithStatus(constants.PROMOSTATUSPAUSED), nil)
+				ended := promoWithStatus(constants.PROMOSTATUSENDED)
+				r.EXPECT().Save(gomock.Any(), nil, &ended).Return(nil)
+			},
+		},
+		{
+			name: "end draft promo",
+			change: func(u PromoUsecase) error {
+				return u.EndPromo(context.Background(), 1)
+			},
+			wantErr:    true,
+			wantStatus: http.StatusConflict,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(promoWithStatus(constants.PROMOSTATUSDRAFT), nil)
+			},
+		},
+		{
+			name: "resume ended promo",
+			change: func(u PromoUsecase) error {
+				return u.ResumePromo(context.Background(), 1)
+			},
+			wantErr:    true,
+			wantStatus: http.StatusConflict,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(promoWithStatus(constants.PROMOSTATUSENDED), nil)
+			},
+		},
+		{
+			name: "archive paused promo",
+			change: func(u PromoUsecase) error {
+				return u.ArchivePromo(context.Background(), 1)
+			},
+			wantErr: false,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().SaveAuditLog(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(promoWithStatus(constants.PROMOSTATUSPAUSED), nil)
+				archived := promoWithStatus(constants.PROMOSTATUSARCHIVED)
+				r.EXPECT().Save(gomock.Any(), nil, &archived).Return(nil)
+			},
+		},
+		{
+			name: "archive archived promo",
+			change: func(u PromoUsecase) error {
+				return u.ArchivePromo(context.Background(), 1)
+			},
+			wantErr:    true,
+			wantStatus: http.StatusConflict,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(promoWithStatus(constants.PROMOSTATUSARCHIVED), nil)
+			},
+		},
+		{
+			name: "err save audit log",
+			change: func(u PromoUsecase) error {
+				return u.PausePromo(context.Background(), 1)
+			},
+			wantErr:    true,
+			wantStatus: http.StatusInternalServerError,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(promoWithStatus(constants.PROMOSTATUSAPPROVED), nil)
+				r.EXPECT().Save(gomock.Any(), nil, gomock.Any()).Return(nil)
+				r.EXPECT().SaveAuditLog(gomock.Any(), nil, gomock.Any()).Return(errors.New("error"))
+			},
+		},
+		{
+			name: "err save promo",
+			change: func(u PromoUsecase) error {
+				return u.ArchivePromo(context.Background(), 1)
+			},
+			wantErr:    true,
+			wantStatus: http.StatusInternalServerError,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(promoWithStatus(constants.PROMOSTATUSAPPROVED), nil)
+				r.EXPECT().Save(gomock.Any(), nil, gomock.Any()).Return(errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			transactionRepo := repo_mock.NewMockTransactionRepository(ctrl)
+			transactionRepo.EXPECT().Execute(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+					return fn(nil)
+				})
+
+			promoRepo := repo_mock.NewMockPromoRepository(ctrl)
+			tt.promoRepoMock(promoRepo)
+
+			usecase := NewPromoUsecase(promoRepo, transactionRepo, nil, nil, nil, 0)
+
+			err := tt.change(usecase)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("promoUsecase status change error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if customErr, ok := err.(*utils.CustomError); ok && customErr.StatusCode != tt.wantStatus {
+				t.Errorf("promoUsecase status change status = %v, want %v", customErr.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_promoUsecase_PromoCities(t *testing.T) {
+	cityPromo := models.Promo{ID: 1, Segmentation: constants.PROMOSEGMENTATIONCITY, Status: constants.PROMOSTATUSAPPROVED}
+	jakarta := models.PromoCity{ID: 1, PromoID: 1, City: "Jakarta"}
+	bandung := models.PromoCity{ID: 2, PromoID: 1, City: "Bandung"}
+
+	tests := []struct {
+		name          string
+		change        func(PromoUsecase) error
+		wantErr       bool
+		wantStatus    int
+		promoRepoMock func(*repo_mock.MockPromoRepository)
+	}{
+		{
+			name: "add new cities only",
+			change: func(u PromoUsecase) error {
+				_, err := u.AddPromoCities(context.Background(), dto.AddPromoCitiesInput{PromoID: 1, Cities: []string{"jakarta", " Bandung "}})
+				return err
+			},
+			wantErr: false,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().SaveAuditLog(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(cityPromo, nil)
+				r.EXPECT().GetCities(gomock.Any(), nil, uint(1)).Return([]models.PromoCity{jakarta}, nil)
+				r.EXPECT().SaveCities(gomock.Any(), nil, uint(1), []string{"Bandung"}).Return(nil)
+				r.EXPECT().GetCities(gomock.Any(), nil, uint(1)).Return([]models.PromoCity{jakarta, bandung}, nil)
+			},
+		},
+		{
+			name: "add cities the promo already has",
+			change: func(u PromoUsecase) error {
+				_, err := u.AddPromoCities(context.Background(), dto.AddPromoCitiesInput{PromoID: 1, Cities: []string{"JAKARTA"}})
+				return err
+			},
+			wantErr: false,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(cityPromo, nil)
+				r.EXPECT().GetCities(gomock.Any(), nil, uint(1)).Return([]models.PromoCity{jakarta}, nil).Times(2)
+			},
+		},
+		{
+			name: "add cities to promo not found",
+			change: func(u PromoUsecase) error {
+				_, err := u.AddPromoCities(context.Background(), dto.AddPromoCitiesInput{PromoID: 1, Cities: []string{"Bandung"}})
+				return err
+			},
+			wantErr:    true,
+			wantStatus: http.StatusNotFound,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(models.Promo{}, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name: "add cities to non city promo",
+			change: func(u PromoUsecase) error {
+				_, err := u.AddPromoCities(context.Background(), dto.AddPromoCitiesInput{PromoID: 1, Cities: []string{"Bandung"}})
+				return err
+			},
+			wantErr:    true,
+			wantStatus: http.StatusUnprocessableEntity,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(models.Promo{ID: 1, Segmentation: constants.PROMOSEGMENTATIONNEWUSER}, nil)
+			},
+		},
+		{
+			name: "add cities to archived promo",
+			change: func(u PromoUsecase) error {
+				_, err := u.AddPromoCities(context.Background(), dto.AddPromoCitiesInput{PromoID: 1, Cities: []string{"Bandung"}})
+				return err
+			},
+			wantErr:    true,
+			wantStatus: http.StatusConflict,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(models.Promo{ID: 1, Segmentation: constants.PROMOSEGMENTATIONCITY, Status: constants.PROMOSTATUSARCHIVED}, nil)
+			},
+		},
+		{
+			name: "err save cities",
+			change: func(u PromoUsecase) error {
+				_, err := u.AddPromoCities(context.Background(), dto.AddPromoCitiesInput{PromoID: 1, Cities: []string{"Bandung"}})
+				return err
+			},
+			wantErr:    true,
+			wantStatus: http.StatusInternalServerError,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(cityPromo, nil)
+				r.EXPECT().GetCities(gomock.Any(), nil, uint(1)).Return([]models.PromoCity{jakarta}, nil)
+				r.EXPECT().SaveCities(gomock.Any(), nil, uint(1), []string{"Bandung"}).Return(errors.New("error"))
+			},
+		},
+		{
+			name: "remove city",
+			change: func(u PromoUsecase) error {
+				return u.RemovePromoCity(context.Background(), dto.RemovePromoCityInput{PromoID: 1, City: "bandung"})
+			},
+			wantErr: false,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().SaveAuditLog(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(cityPromo, nil)
+				r.EXPECT().GetCities(gomock.Any(), nil, uint(1)).Return([]models.PromoCity{jakarta, bandung}, nil)
+				r.EXPECT().RemoveCity(gomock.Any(), nil, uint(1), "bandung").Return(true, nil)
+			},
+		},
+		{
+			name: "remove last city",
+			change: func(u PromoUsecase) error {
+				return u.RemovePromoCity(context.Background(), dto.RemovePromoCityInput{PromoID: 1, City: "jakarta"})
+			},
+			wantErr:    true,
+			wantStatus: http.StatusUnprocessableEntity,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(cityPromo, nil)
+				r.EXPECT().GetCities(gomock.Any(), nil, uint(1)).Return([]models.PromoCity{jakarta}, nil)
+			},
+		},
+		{
+			name: "remove city not found",
+			change: func(u PromoUsecase) error {
+				return u.RemovePromoCity(context.Background(), dto.RemovePromoCityInput{PromoID: 1, City: "Surabaya"})
+			},
+			wantErr:    true,
+			wantStatus: http.StatusNotFound,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(cityPromo, nil)
+				r.EXPECT().GetCities(gomock.Any(), nil, uint(1)).Return([]models.PromoCity{jakarta, bandung}, nil)
+				r.EXPECT().RemoveCity(gomock.Any(), nil, uint(1), "Surabaya").Return(false, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			transactionRepo := repo_mock.NewMockTransactionRepository(ctrl)
+			transactionRepo.EXPECT().Execute(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+					return fn(nil)
+				})
+
+			promoRepo := repo_mock.NewMockPromoRepository(ctrl)
+			tt.promoRepoMock(promoRepo)
+
+			usecase := NewPromoUsecase(promoRepo, transactionRepo, nil, nil, nil, 0)
+
+			err := tt.change(usecase)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("promoUsecase city change error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if customErr, ok := err.(*utils.CustomError); ok && customErr.StatusCode != tt.wantStatus {
+				t.Errorf("promoUsecase city change status = %v, want %v", customErr.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_promoUsecase_GetPromoHistory(t *testing.T) {
+	tests := []struct {
+		name          string
+		wantLogs      []models.PromoAuditLog
+		wantTotal     int64
+		wantErr       bool
+		wantStatus    int
+		promoRepoMock func(*repo_mock.MockPromoRepository)
+	}{
+		{
+			name:       "promo not found",
+			wantErr:    true,
+			wantStatus: http.StatusNotFound,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoByPromoID(gomock.Any(), nil, uint(1)).Return(models.Promo{}, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name:       "err get audit logs",
+			wantErr:    true,
+			wantStatus: http.StatusInternalServerError,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoByPromoID(gomock.Any(), nil, uint(1)).Return(models.Promo{ID: 1}, nil)
+				r.EXPECT().GetAuditLogs(gomock.Any(), nil, uint(1), 2, 10).Return(nil, int64(0), errors.New("error"))
+			},
+		},
+		{
+			name:      "success",
+			wantLogs:  []models.PromoAuditLog{{ID: 12, PromoID: 1, Action: constants.PROMOAUDITACTIONEXTEND}},
+			wantTotal: 12,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoByPromoID(gomock.Any(), nil, uint(1)).Return(models.Promo{ID: 1}, nil)
+				r.EXPECT().GetAuditLogs(gomock.Any(), nil, uint(1), 2, 10).
+					Return([]models.PromoAuditLog{{ID: 12, PromoID: 1, Action: constants.PROMOAUDITACTIONEXTEND}}, int64(12), nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			promoRepo := repo_mock.NewMockPromoRepository(ctrl)
+			tt.promoRepoMock(promoRepo)
+
+			usecase := NewPromoUsecase(promoRepo, nil, nil, nil, nil, 0)
+
+			logs, total, err := usecase.GetPromoHistory(context.Background(), dto.GetPromoHistoryInput{PromoID: 1, Page: 2, PerPage: 10})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("promoUsecase.GetPromoHistory() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				if err.(*utils.CustomError).StatusCode != tt.wantStatus {
+					t.Errorf("promoUsecase.GetPromoHistory() status = %v, want %v", err.(*utils.CustomError).StatusCode, tt.wantStatus)
+				}
+				return
+			}
+			if !reflect.DeepEqual(logs, tt.wantLogs) || total != tt.wantTotal {
+				t.Errorf("promoUsecase.GetPromoHistory() = %v, %v, want %v, %v", logs, total, tt.wantLogs, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func Test_promoUsecase_PromoUsers(t *testing.T) {
+	userListPromo := models.Promo{ID: 1, Segmentation: constants.PROMOSEGMENTATIONUSERLIST, Status: constants.PROMOSTATUSAPPROVED}
+
+	tests := []struct {
+		name          string
+		change        func(PromoUsecase) error
+		wantErr       bool
+		wantStatus    int
+		promoRepoMock func(*repo_mock.MockPromoRepository)
+	}{
+		{
+			name: "get users",
+			change: func(u PromoUsecase) error {
+				_, _, err := u.GetPromoUsers(context.Background(), dto.GetPromoUsersInput{PromoID: 1, Page: 1, PerPage: 10})
+				return err
+			},
+			wantErr: false,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoByPromoID(gomock.Any(), nil, uint(1)).Return(userListPromo, nil)
+				r.EXPECT().GetUsers(gomock.Any(), nil, uint(1), 1, 10).Return([]models.PromoUser{{ID: 1, PromoID: 1, UserID: 2}}, int64(1), nil)
+			},
+		},
+		{
+			name: "get users of non user list promo",
+			change: func(u PromoUsecase) error {
+				_, _, err := u.GetPromoUsers(context.Background(), dto.GetPromoUsersInput{PromoID: 1, Page: 1, PerPage: 10})
+				return err
+			},
+			wantErr:    true,
+			wantStatus: http.StatusUnprocessableEntity,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoByPromoID(gomock.Any(), nil, uint(1)).Return(models.Promo{ID: 1, Segmentation: constants.PROMOSEGMENTATIONCITY}, nil)
+			},
+		},
+		{
+			name: "add users",
+			change: func(u PromoUsecase) error {
+				_, err := u.AddPromoUsers(context.Background(), dto.PromoUsersInput{PromoID: 1, UserIds: []uint{2, 3}})
+				return err
+			},
+			wantErr: false,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(userListPromo, nil)
+				gomock.InOrder(
+					r.EXPECT().FindUsers(gomock.Any(), nil, uint(1), []uint{2, 3}).Return([]models.PromoUser{}, nil),
+					r.EXPECT().SaveUsers(gomock.Any(), nil, uint(1), []uint{2, 3}).Return(int64(1), nil),
+					r.EXPECT().FindUsers(gomock.Any(), nil, uint(1), []uint{2, 3}).Return([]models.PromoUser{{ID: 1, PromoID: 1, UserID: 2}}, nil),
+				)
+				r.EXPECT().SaveAuditLog(gomock.Any(), nil, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tx *gorm.DB, log *models.PromoAuditLog) error {
+						if log.Action != constants.PROMOAUDITACTIONADDUSERS || string(log.Changes) != `{"user_ids":{"before":[],"after":[2]}}` {
+							t.Errorf("audit log = %+v, changes %s", log, log.Changes)
+						}
+						return nil
+					})
+			},
+		},
+		{
+			name: "add users already listed",
+			change: func(u PromoUsecase) error {
+				_, err := u.AddPromoUsers(context.Background(), dto.PromoUsersInput{PromoID: 1, UserIds: []uint{2}})
+				return err
+			},
+			wantErr: false,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(userListPromo, nil)
+				r.EXPECT().FindUsers(gomock.Any(), nil, uint(1), []uint{2}).Return([]models.PromoUser{{ID: 1, PromoID: 1, UserID: 2}}, nil)
+				r.EXPECT().SaveUsers(gomock.Any(), nil, uint(1), []uint{2}).Return(int64(0), nil)
+			},
+		},
+		{
+			name: "add users to promo not found",
+			change: func(u PromoUsecase) error {
+				_, err := u.AddPromoUsers(context.Background(), dto.PromoUsersInput{PromoID: 1, UserIds: []uint{2}})
+				return err
+			},
+			wantErr:    true,
+			wantStatus: http.StatusNotFound,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(models.Promo{}, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name: "add users to archived promo",
+			change: func(u PromoUsecase) error {
+				_, err := u.AddPromoUsers(context.Background(), dto.PromoUsersInput{PromoID: 1, UserIds: []uint{2}})
+				return err
+			},
+			wantErr:    true,
+			wantStatus: http.StatusConflict,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(models.Promo{ID: 1, Segmentation: constants.PROMOSEGMENTATIONUSERLIST, Status: constants.PROMOSTATUSARCHIVED}, nil)
+			},
+		},
+		{
+			name: "remove users",
+			change: func(u PromoUsecase) error {
+				_, err := u.RemovePromoUsers(context.Background(), dto.PromoUsersInput{PromoID: 1, UserIds: []uint{2}})
+				return err
+			},
+			wantErr: false,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(userListPromo, nil)
+				gomock.InOrder(
+					r.EXPECT().FindUsers(gomock.Any(), nil, uint(1), []uint{2}).Return([]models.PromoUser{{ID: 1, PromoID: 1, UserID: 2}}, nil),
+					r.EXPECT().RemoveUsers(gomock.Any(), nil, uint(1), []uint{2}).Return(int64(1), nil),
+					r.EXPECT().FindUsers(gomock.Any(), nil, uint(1), []uint{2}).Return([]models.PromoUser{}, nil),
+				)
+				r.EXPECT().SaveAuditLog(gomock.Any(), nil, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tx *gorm.DB, log *models.PromoAuditLog) error {
+						if log.Action != constants.PROMOAUDITACTIONREMOVEUSERS || string(log.Changes) != `{"user_ids":{"before":[2],"after":[]}}` {
+							t.Errorf("audit log = %+v, changes %s", log, log.Changes)
+						}
+						return nil
+					})
+			},
+		},
+		{
+			name: "err remove users",
+			change: func(u PromoUsecase) error {
+				_, err := u.RemovePromoUsers(context.Background(), dto.PromoUsersInput{PromoID: 1, UserIds: []uint{2}})
+				return err
+			},
+			wantErr:    true,
+			wantStatus: http.StatusInternalServerError,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoForUpdate(gomock.Any(), nil, uint(1)).Return(userListPromo, nil)
+				r.EXPECT().FindUsers(gomock.Any(), nil, uint(1), []uint{2}).Return([]models.PromoUser{{ID: 1, PromoID: 1, UserID: 2}}, nil)
+				r.EXPECT().RemoveUsers(gomock.Any(), nil, uint(1), []uint{2}).Return(int64(0), errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			transactionRepo := repo_mock.NewMockTransactionRepository(ctrl)
+			transactionRepo.EXPECT().Execute(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+					return fn(nil)
+				}).AnyTimes()
+
+			promoRepo := repo_mock.NewMockPromoRepository(ctrl)
+			tt.promoRepoMock(promoRepo)
+
+			usecase := NewPromoUsecase(promoRepo, transactionRepo, nil, nil, nil, 0)
+
+			err := tt.change(usecase)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("promoUsecase user change error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if customErr, ok := err.(*utils.CustomError); ok && customErr.StatusCode != tt.wantStatus {
+				t.Errorf("promoUsecase user change status = %v, want %v", customErr.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_promoUsecase_GetPromoDetail(t *testing.T) {
+	detail := models.Promo{
+		ID:          1,
+		Status:      constants.PROMOSTATUSAPPROVED,
+		PromoCities: []models.PromoCity{{ID: 1, PromoID: 1, City: "Jakarta"}},
+	}
+
+	tests := []struct {
+		name          string
+		want          models.Promo
+		wantErr       bool
+		promoRepoMock func(*repo_mock.MockPromoRepository)
+	}{
+		{
+			name:    "err get promo detail",
+			want:    models.Promo{},
+			wantErr: true,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoDetail(gomock.Any(), nil, uint(1)).Return(models.Promo{}, errors.New("error"))
+			},
+		},
+		{
+			name:    "promo not found",
+			want:    models.Promo{},
+			wantErr: true,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoDetail(gomock.Any(), nil, uint(1)).Return(models.Promo{}, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name:    "success",
+			want:    detail,
+			wantErr: false,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoDetail(gomock.Any(), nil, uint(1)).Return(detail, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			promoRepo := repo_mock.NewMockPromoRepository(ctrl)
+			tt.promoRepoMock(promoRepo)
+
+			usecase := NewPromoUsecase(promoRepo, nil, nil, nil, nil, 0)
+
+			got, err := usecase.GetPromoDetail(context.Background(), 1)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("promoUsecase.GetPromoDetail() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("promoUsecase.GetPromoDetail() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_promoUsecase_ListPromos(t *testing.T) {
+	input := dto.ListPromosInput{
+		Status:  constants.PROMOSTATUSPAUSED,
+		Type:    constants.PROMOTYPEPERCENTAGE,
+		Page:    1,
+		PerPage: 10,
+	}
+	repoInput := repository.ListPromosInput{
+		Status:  constants.PROMOSTATUSPAUSED,
+		Type:    constants.PROMOTYPEPERCENTAGE,
+		Page:    1,
+		PerPage: 10,
+	}
+	promos := []models.Promo{{ID: 1, Status: constants.PROMOSTATUSPAUSED}}
+
+	tests := []struct {
+		name          string
+		want          []models.Promo
+		wantTotal     int64
+		wantErr       bool
+		promoRepoMock func(*repo_mock.MockPromoRepository)
+	}{
+		{
+			name:    "err list promos",
+			want:    nil,
+			wantErr: true,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().ListPromos(gomock.Any(), nil, repoInput).Return(nil, int64(0), errors.New("error"))
+			},
+		},
+		{
+			name:      "success",
+			want:      promos,
+			wantTotal: 1,
+			wantErr:   false,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().ListPromos(gomock.Any(), nil, repoInput).Return(promos, int64(1), nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			promoRepo := repo_mock.NewMockPromoRepository(ctrl)
+			tt.promoRepoMock(promoRepo)
+
+			usecase := NewPromoUsecase(promoRepo, nil, nil, nil, nil, 0)
+
+			got, total, err := usecase.ListPromos(context.Background(), input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("promoUsecase.ListPromos() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("promoUsecase.ListPromos() = %v, want %v", got, tt.want)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("promoUsecase.ListPromos() total = %v, want %v", total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func Test_promoUsecase_CreatePromo(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		dto dto.CreatePromoInput
+	}
+	buyProductId := int(1)
+	freeProductId := int(2)
+	cities := []string{"Jakarta", "Bogor"}
+	code := "HEMAT20"
+	rolloutPercentage := 50
+	tests := []struct {
+		name                string
+		args                args
+		want                uint
+		wantErr             bool
+		transactionRepoMock func(*repo_mock.MockTransactionRepository)
+		promoRepoMock       func(*repo_mock.MockPromoRepository)
+		productRepoMock     func(*repo_mock.MockProductRepository)
+	}{
+		{
+			name: "err get product",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.CreatePromoInput{
+					Type:         constants.PROMOTYPEBUYXGETY,
+					BuyProductId: &buyProductId,
+				},
+			},
+			want:    0,
+			wantErr: true,
+			transactionRepoMock: func(r *repo_mock.MockTransactionRepository) {
+				r.EXPECT().Execute(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+					return fn(nil)
+				})
+			},
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+			},
+			productRepoMock: func(r *repo_mock.MockProductRepository) {
+				r.EXPECT().Get(gomock.Any(), nil, uint(buyProductId)).Return(models.Product{}, errors.New("error"))
+			},
+		},
+		{
+			name: "err buy product not found",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.CreatePromoInput{
+					Type:         constants.PROMOTYPEBUYXGETY,
+					BuyProductId: &buyProductId,
+				},
+			},
+			want:    0,
+			wantErr: true,
+			transactionRepoMock: func(r *repo_mock.MockTransactionRepository) {
+				r.EXPECT().Execute(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+					return fn(nil)
+				})
+			},
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+			},
+			productRepoMock: func(r *repo_mock.MockProductRepository) {
+				r.EXPECT().Get(gomock.Any(), nil, uint(buyProductId)).Return(models.Product{}, nil)
+			},
+		},
+		{
+			name: "err get free product",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.CreatePromoInput{
+					Type:          constants.PROMOTYPEBUYXGETY,
+					BuyProductId:  &buyProductId,
+					FreeProductId: &freeProductId,
+				},
+			},
+			want:    0,
+			wantErr: true,
+			transactionRepoMock: func(r *repo_mock.MockTransactionRepository) {
+				r.EXPECT().Execute(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+					return fn(nil)
+				})
+			},
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+			},
+			productRepoMock: func(r *repo_mock.MockProductRepository) {
+				r.EXPECT().Get(gomock.Any(), nil, uint(buyProductId)).Return(models.Product{
+					ID: uint(buyProductId),
+				}, nil)
+
+				r.EXPECT().Get(gomock.Any(), nil, uint(freeProductId)).Return(models.Product{}, errors.New("error"))
+			},
+		},
+		{
+			name: "err free product not found",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.CreatePromoInput{
+					Type:          constants.PROMOTYPEBUYXGETY,
+					BuyProductId:  &buyProductId,
+					FreeProductId: &freeProductId,
+				},
+			},
+			want:    0,
+			wantErr: true,
+			transactionRepoMock: func(r *repo_mock.MockTransactionRepository) {
+				r.EXPECT().Execute(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+					return fn(nil)
+				})
+			},
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+			},
+			productRepoMock: func(r *repo_mock.MockProductRepository) {
+				r.EXPECT().Get(gomock.Any(), nil, uint(buyProductId)).Return(models.Product{
+					ID: uint(buyProductId),
+				}, nil)
+
+				r.EXPECT().Get(gomock.Any(), nil, uint(freeProductId)).Return(models.Product{}, nil)
+			},
+		},
+		{
+			name: "err save promo",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.CreatePromoInput{
+					Type:          constants.PROMOTYPEBUYXGETY,
+					BuyProductId:  &buyProductId,
+					FreeProductId: &freeProductId,
+				},
+			},
+			want:    0,
+			wantErr: true,
+			transactionRepoMock: func(r *repo_mock.MockTransactionRepository) {
+				r.EXPECT().Execute(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+					return fn(nil)
+				})
+			},
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				buyProductId := uint(buyProductId)
+				freeProductId := uint(freeProductId)
+				promo := gomock.Eq(&models.Promo{
+					Type:              constants.PROMOTYPEBUYXGETY,
+					BuyProductID:      &buyProductId,
+					FreeProductID:     &freeProductId,
+					CurrentUsageCount: 0,
+					Timezone:          constants.PROMODEFAULTTIMEZONE,
+					Status:            constants.PROMOSTATUSDRAFT,
+				})
+				r.EXPECT().Save(gomock.Any(), gomock.Any(), promo).Return(errors.New("error"))
+			},
+			productRepoMock: func(r *repo_mock.MockProductRepository) {
+				r.EXPECT().Get(gomock.Any(), nil, uint(buyProductId)).Return(models.Product{
+					ID: uint(buyProductId),
+				}, nil)
+
+				r.EXPECT().Get(gomock.Any(), nil, uint(freeProductId)).Return(models.Product{
+					ID: uint(freeProductId),
+				}, nil)
+			},
+		},
+		{
+			name: "err save promo cities",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.CreatePromoInput{
+					Type:          constants.PROMOTYPEBUYXGETY,
+					BuyProductId:  &buyProductId,
+					FreeProductId: &freeProductId,
+					Segmentation:  constants.PROMOSEGMENTATIONCITY,
+					Cities:        cities,
+				},
 			},
 			want:    0,
 			wantErr: true,
@@ -565,32 +1649,379 @@ func Test_promoUsecase_CreatePromo(t *testing.T) {
 					BuyProductID:      &buyProductId,
 					FreeProductID:     &freeProductId,
 					CurrentUsageCount: 0,
+					Timezone:          constants.PROMODEFAULTTIMEZONE,
+					Status:            constants.PROMOSTATUSDRAFT,
 					Segmentation:      constants.PROMOSEGMENTATIONCITY,
 				}
 				promoMock := gomock.Eq(&promo)
 				r.EXPECT().Save(gomock.Any(), gomock.Any(), promoMock).Return(nil)
 				r.EXPECT().SaveCities(gomock.Any(), gomock.Any(), promo.ID, cities).Return(errors.New("error"))
 			},
-			productRepoMock: func(r *repo_mock.MockProductRepository) {
-				r.EXPECT().Get(gomock.Any(), nil, uint(buyProductId)).Return(models.Product{
-					ID: uint(buyProductId),
-				}, nil)
-
-				r.EXPECT().Get(gomock.Any(), nil, uint(freeProductId)).Return(models.Product{
-					ID: uint(freeProductId),
-				}, nil)
+			productRepoMock: func(r *repo_mock.MockProductRepository) {
+				r.EXPECT().Get(gomock.Any(), nil, uint(buyProductId)).Return(models.Product{
+					ID: uint(buyProductId),
+				}, nil)
+
+				r.EXPECT().Get(gomock.Any(), nil, uint(freeProductId)).Return(models.Product{
+					ID: uint(freeProductId),
+				}, nil)
+			},
+		},
+		{
+			name: "err get promo by code",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.CreatePromoInput{
+					Type: constants.PROMOTYPEPERCENTAGE,
+					Code: &code,
+				},
+			},
+			want:    0,
+			wantErr: true,
+			transactionRepoMock: func(r *repo_mock.MockTransactionRepository) {
+				r.EXPECT().Execute(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+					return fn(nil)
+				})
+			},
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoByCode(gomock.Any(), nil, code).Return(models.Promo{}, errors.New("error"))
+			},
+			productRepoMock: func(r *repo_mock.MockProductRepository) {},
+		},
+		{
+			name: "promo code already exists",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.CreatePromoInput{
+					Type: constants.PROMOTYPEPERCENTAGE,
+					Code: &code,
+				},
+			},
+			want:    0,
+			wantErr: true,
+			transactionRepoMock: func(r *repo_mock.MockTransactionRepository) {
+				r.EXPECT().Execute(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+					return fn(nil)
+				})
+			},
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoByCode(gomock.Any(), nil, code).Return(models.Promo{ID: 1, Code: &code}, nil)
+			},
+			productRepoMock: func(r *repo_mock.MockProductRepository) {},
+		},
+		{
+			name: "success code only",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.CreatePromoInput{
+					Type:     constants.PROMOTYPEPERCENTAGE,
+					Code:     &code,
+					CodeOnly: true,
+				},
+			},
+			want:    1,
+			wantErr: false,
+			transactionRepoMock: func(r *repo_mock.MockTransactionRepository) {
+				r.EXPECT().Execute(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+					return fn(nil)
+				})
+			},
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().SaveAuditLog(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				r.EXPECT().GetPromoByCode(gomock.Any(), nil, code).Return(models.Promo{}, gorm.ErrRecordNotFound)
+				promo := gomock.Eq(&models.Promo{
+					Type:       constants.PROMOTYPEPERCENTAGE,
+					Code:       &code,
+					IsCodeOnly: true,
+					Timezone:   constants.PROMODEFAULTTIMEZONE,
+					Status:     constants.PROMOSTATUSDRAFT,
+				})
+				r.EXPECT().Save(gomock.Any(), gomock.Any(), promo).DoAndReturn(func(ctx context.Context, tx any, p *models.Promo) error {
+					p.ID = 1
+					return nil
+				})
+			},
+			productRepoMock: func(r *repo_mock.MockProductRepository) {},
+		},
+		{
+			name: "success",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.CreatePromoInput{
+					Type:          constants.PROMOTYPEBUYXGETY,
+					BuyProductId:  &buyProductId,
+					FreeProductId: &freeProductId,
+					Segmentation:  constants.PROMOSEGMENTATIONCITY,
+					Cities:        cities,
+				},
+			},
+			want:    1,
+			wantErr: false,
+			transactionRepoMock: func(r *repo_mock.MockTransactionRepository) {
+				r.EXPECT().Execute(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+					return fn(nil)
+				})
+			},
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().SaveAuditLog(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				buyProductId := uint(buyProductId)
+				freeProductId := uint(freeProductId)
+				promo := models.Promo{
+					Type:              constants.PROMOTYPEBUYXGETY,
+					BuyProductID:      &buyProductId,
+					FreeProductID:     &freeProductId,
+					CurrentUsageCount: 0,
+					Timezone:          constants.PROMODEFAULTTIMEZONE,
+					Status:            constants.PROMOSTATUSDRAFT,
+					Segmentation:      constants.PROMOSEGMENTATIONCITY,
+				}
+				promoMock := gomock.AssignableToTypeOf(&promo)
+				r.EXPECT().Save(gomock.Any(), gomock.Any(), promoMock).DoAndReturn(func(ctx context.Context, tx any, p *models.Promo) error {
+					p.ID = 1
+					return nil
+				})
+				promo.ID = 1
+				r.EXPECT().SaveCities(gomock.Any(), gomock.Any(), promo.ID, cities).Return(nil)
+			},
+			productRepoMock: func(r *repo_mock.MockProductRepository) {
+				r.EXPECT().Get(gomock.Any(), nil, uint(buyProductId)).Return(models.Product{
+					ID: uint(buyProductId),
+				}, nil)
+
+				r.EXPECT().Get(gomock.Any(), nil, uint(freeProductId)).Return(models.Product{
+					ID: uint(freeProductId),
+				}, nil)
+			},
+		},
+		{
+			name: "err save promo schedules",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.CreatePromoInput{
+					Type:      constants.PROMOTYPEPERCENTAGE,
+					Timezone:  "Asia/Makassar",
+					Schedules: []dto.PromoScheduleInput{{Days: []int{1, 2}, StartTime: "14:00", EndTime: "17:00"}},
+				},
+			},
+			want:    0,
+			wantErr: true,
+			transactionRepoMock: func(r *repo_mock.MockTransactionRepository) {
+				r.EXPECT().Execute(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+					return fn(nil)
+				})
+			},
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				promo := gomock.Eq(&models.Promo{
+					Type:     constants.PROMOTYPEPERCENTAGE,
+					Timezone: "Asia/Makassar",
+					Status:   constants.PROMOSTATUSDRAFT,
+				})
+				r.EXPECT().Save(gomock.Any(), gomock.Any(), promo).DoAndReturn(func(ctx context.Context, tx any, p *models.Promo) error {
+					p.ID = 1
+					return nil
+				})
+				r.EXPECT().SaveSchedules(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("error"))
+			},
+			productRepoMock: func(r *repo_mock.MockProductRepository) {},
+		},
+		{
+			name: "success with schedules",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.CreatePromoInput{
+					Type: constants.PROMOTYPEPERCENTAGE,
+					Schedules: []dto.PromoScheduleInput{
+						{Days: []int{1, 2}, StartTime: "14:00", EndTime: "17:00"},
+						{Days: []int{5}, StartTime: "22:00", EndTime: "02:00"},
+					},
+				},
+			},
+			want:    1,
+			wantErr: false,
+			transactionRepoMock: func(r *repo_mock.MockTransactionRepository) {
+				r.EXPECT().Execute(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+					return fn(nil)
+				})
+			},
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().SaveAuditLog(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				promo := gomock.Eq(&models.Promo{
+					Type:     constants.PROMOTYPEPERCENTAGE,
+					Timezone: constants.PROMODEFAULTTIMEZONE,
+					Status:   constants.PROMOSTATUSDRAFT,
+				})
+				r.EXPECT().Save(gomock.Any(), gomock.Any(), promo).DoAndReturn(func(ctx context.Context, tx any, p *models.Promo) error {
+					p.ID = 1
+					return nil
+				})
+				r.EXPECT().SaveSchedules(gomock.Any(), gomock.Any(), []models.PromoSchedule{
+					{PromoID: 1, DayOfWeek: 1, StartTime: "14:00", EndTime: "17:00"},
+					{PromoID: 1, DayOfWeek: 2, StartTime: "14:00", EndTime: "17:00"},
+					{PromoID: 1, DayOfWeek: 5, StartTime: "22:00", EndTime: "02:00"},
+				}).Return(nil)
+			},
+			productRepoMock: func(r *repo_mock.MockProductRepository) {},
+		},
+		{
+			name: "target product not found",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.CreatePromoInput{
+					Type:       constants.PROMOTYPEPERCENTAGE,
+					ProductIds: []uint{4, 7},
+				},
+			},
+			want:    0,
+			wantErr: true,
+			transactionRepoMock: func(r *repo_mock.MockTransactionRepository) {
+				r.EXPECT().Execute(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+					return fn(nil)
+				})
+			},
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {},
+			productRepoMock: func(r *repo_mock.MockProductRepository) {
+				r.EXPECT().Get(gomock.Any(), nil, uint(4)).Return(models.Product{ID: 4}, nil)
+				r.EXPECT().Get(gomock.Any(), nil, uint(7)).Return(models.Product{}, nil)
+			},
+		},
+		{
+			name: "success with target products",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.CreatePromoInput{
+					Type:       constants.PROMOTYPEPERCENTAGE,
+					ProductIds: []uint{4, 7},
+				},
+			},
+			want:    1,
+			wantErr: false,
+			transactionRepoMock: func(r *repo_mock.MockTransactionRepository) {
+				r.EXPECT().Execute(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+					return fn(nil)
+				})
+			},
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().SaveAuditLog(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tx *gorm.DB, log *models.PromoAuditLog) error {
+						changes := map[string]json.RawMessage{}
+						if err := json.Unmarshal(log.Changes, &changes); err != nil {
+							t.Fatal(err)
+						}
+						if string(changes["product_ids"]) != `{"before":null,"after":[4,7]}` {
+							t.Errorf("audit log product_ids = %s", changes["product_ids"])
+						}
+						return nil
+					})
+				promo := gomock.Eq(&models.Promo{
+					Type:     constants.PROMOTYPEPERCENTAGE,
+					Timezone: constants.PROMODEFAULTTIMEZONE,
+					Status:   constants.PROMOSTATUSDRAFT,
+				})
+				r.EXPECT().Save(gomock.Any(), gomock.Any(), promo).DoAndReturn(func(ctx context.Context, tx any, p *models.Promo) error {
+					p.ID = 1
+					return nil
+				})
+				r.EXPECT().SaveProducts(gomock.Any(), gomock.Any(), uint(1), []uint{4, 7}).Return(nil)
+			},
+			productRepoMock: func(r *repo_mock.MockProductRepository) {
+				r.EXPECT().Get(gomock.Any(), nil, uint(4)).Return(models.Product{ID: 4}, nil)
+				r.EXPECT().Get(gomock.Any(), nil, uint(7)).Return(models.Product{ID: 7}, nil)
+			},
+		},
+		{
+			name: "success with tiers",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.CreatePromoInput{
+					Type: constants.PROMOTYPETIERED,
+					Tiers: []dto.PromoTierInput{
+						{MinAmount: 50000, DiscountType: constants.PROMOTIERPERCENTAGE, DiscountValue: 5},
+						{MinAmount: 100000, DiscountType: constants.PROMOTIERFIXED, DiscountValue: 10000},
+					},
+				},
+			},
+			want:    1,
+			wantErr: false,
+			transactionRepoMock: func(r *repo_mock.MockTransactionRepository) {
+				r.EXPECT().Execute(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+					return fn(nil)
+				})
+			},
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().SaveAuditLog(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tx *gorm.DB, log *models.PromoAuditLog) error {
+						changes := map[string]json.RawMessage{}
+						if err := json.Unmarshal(log.Changes, &changes); err != nil {
+							t.Fatal(err)
+						}
+						if string(changes["tiers"]) != `{"before":null,"after":[{"minAmount":50000,"discountType":"PERCENTAGE","discountValue":5},{"minAmount":100000,"discountType":"FIXED","discountValue":10000}]}` {
+							t.Errorf("audit log tiers = %s", changes["tiers"])
+						}
+						return nil
+					})
+				promo := gomock.Eq(&models.Promo{
+					Type:     constants.PROMOTYPETIERED,
+					Timezone: constants.PROMODEFAULTTIMEZONE,
+					Status:   constants.PROMOSTATUSDRAFT,
+				})
+				r.EXPECT().Save(gomock.Any(), gomock.Any(), promo).DoAndReturn(func(ctx context.Context, tx any, p *models.Promo) error {
+					p.ID = 1
+					return nil
+				})
+				r.EXPECT().SaveTiers(gomock.Any(), gomock.Any(), []models.PromoTier{
+					{PromoID: 1, MinAmount: 50000, DiscountType: constants.PROMOTIERPERCENTAGE, DiscountValue: 5},
+					{PromoID: 1, MinAmount: 100000, DiscountType: constants.PROMOTIERFIXED, DiscountValue: 10000},
+				}).Return(nil)
+			},
+			productRepoMock: func(r *repo_mock.MockProductRepository) {},
+		},
+		{
+			name: "err save tiers",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.CreatePromoInput{
+					Type: constants.PROMOTYPETIERED,
+					Tiers: []dto.PromoTierInput{
+						{MinAmount: 50000, DiscountType: constants.PROMOTIERPERCENTAGE, DiscountValue: 5},
+						{MinAmount: 100000, DiscountType: constants.PROMOTIERFIXED, DiscountValue: 10000},
+					},
+				},
+			},
+			want:    0,
+			wantErr: true,
+			transactionRepoMock: func(r *repo_mock.MockTransactionRepository) {
+				r.EXPECT().Execute(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+					return fn(nil)
+				})
+			},
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				promo := gomock.Eq(&models.Promo{
+					Type:     constants.PROMOTYPETIERED,
+					Timezone: constants.PROMODEFAULTTIMEZONE,
+					Status:   constants.PROMOSTATUSDRAFT,
+				})
+				r.EXPECT().Save(gomock.Any(), gomock.Any(), promo).DoAndReturn(func(ctx context.Context, tx any, p *models.Promo) error {
+					p.ID = 1
+					return nil
+				})
+				r.EXPECT().SaveTiers(gomock.Any(), gomock.Any(), []models.PromoTier{
+					{PromoID: 1, MinAmount: 50000, DiscountType: constants.PROMOTIERPERCENTAGE, DiscountValue: 5},
+					{PromoID: 1, MinAmount: 100000, DiscountType: constants.PROMOTIERFIXED, DiscountValue: 10000},
+				}).Return(errors.New("error"))
 			},
+			productRepoMock: func(r *repo_mock.MockProductRepository) {},
 		},
 		{
-			name: "success",
+			name: "success with rollout and variants",
 			args: args{
 				ctx: context.Background(),
 				dto: dto.CreatePromoInput{
-					Type:          constants.PROMOTYPEBUYXGETY,
-					BuyProductId:  &buyProductId,
-					FreeProductId: &freeProductId,
-					Segmentation:  constants.PROMOSEGMENTATIONCITY,
-					Cities:        cities,
+					Type:              constants.PROMOTYPEPERCENTAGE,
+					RolloutPercentage: &rolloutPercentage,
+					Variants: []dto.PromoVariantInput{
+						{Name: "A", Weight: 1, DiscountValue: 15},
+						{Name: "B", Weight: 1, DiscountValue: 20},
+					},
 				},
 			},
 			want:    1,
@@ -601,32 +2032,23 @@ func Test_promoUsecase_CreatePromo(t *testing.T) {
 				})
 			},
 			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
-				buyProductId := uint(buyProductId)
-				freeProductId := uint(freeProductId)
-				promo := models.Promo{
-					Type:              constants.PROMOTYPEBUYXGETY,
-					BuyProductID:      &buyProductId,
-					FreeProductID:     &freeProductId,
-					CurrentUsageCount: 0,
-					Segmentation:      constants.PROMOSEGMENTATIONCITY,
-				}
-				promoMock := gomock.AssignableToTypeOf(&promo)
-				r.EXPECT().Save(gomock.Any(), gomock.Any(), promoMock).DoAndReturn(func(ctx context.Context, tx any, p *models.Promo) error {
+				r.EXPECT().SaveAuditLog(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				promo := gomock.Eq(&models.Promo{
+					Type:              constants.PROMOTYPEPERCENTAGE,
+					RolloutPercentage: &rolloutPercentage,
+					Timezone:          constants.PROMODEFAULTTIMEZONE,
+					Status:            constants.PROMOSTATUSDRAFT,
+				})
+				r.EXPECT().Save(gomock.Any(), gomock.Any(), promo).DoAndReturn(func(ctx context.Context, tx any, p *models.Promo) error {
 					p.ID = 1
 					return nil
 				})
-				promo.ID = 1
-				r.EXPECT().SaveCities(gomock.Any(), gomock.Any(), promo.ID, cities).Return(nil)
-			},
-			productRepoMock: func(r *repo_mock.MockProductRepository) {
-				r.EXPECT().Get(gomock.Any(), nil, uint(buyProductId)).Return(models.Product{
-					ID: uint(buyProductId),
-				}, nil)
-
-				r.EXPECT().Get(gomock.Any(), nil, uint(freeProductId)).Return(models.Product{
-					ID: uint(freeProductId),
-				}, nil)
+				r.EXPECT().SaveVariants(gomock.Any(), gomock.Any(), []models.PromoVariant{
+					{PromoID: 1, Name: "A", Weight: 1, DiscountValue: 15},
+					{PromoID: 1, Name: "B", Weight: 1, DiscountValue: 20},
+				}).Return(nil)
 			},
+			productRepoMock: func(r *repo_mock.MockProductRepository) {},
 		},
 	}
 	for _, tt := range tests {
@@ -644,7 +2066,7 @@ func Test_promoUsecase_CreatePromo(t *testing.T) {
 			promoRepo := repo_mock.NewMockPromoRepository(ctrl)
 			tt.promoRepoMock(promoRepo)
 
-			usecase := NewPromoUsecase(promoRepo, transactionRepo, nil, productRepo)
+			usecase := NewPromoUsecase(promoRepo, transactionRepo, nil, productRepo, nil, 0)
 
 			res, err := usecase.CreatePromo(context.Background(), tt.args.dto)
 			if (err != nil) != tt.wantErr {
@@ -659,3 +2081,456 @@ func Test_promoUsecase_CreatePromo(t *testing.T) {
 		})
 	}
 }
+
+func Test_promoUsecase_RecommendPromos(t *testing.T) {
+	buyProductId := uint(1)
+	freeProductId := uint(2)
+	cart := models.Cart{
+		ID:     1,
+		UserID: 1,
+		CartItems: []models.CartItem{
+			{
+				ProductID: 1,
+				Quantity:  2,
+				Product:   &models.Product{ID: 1, Price: 10000},
+			},
+		},
+	}
+	percentagePromo := models.Promo{
+		ID:            1,
+		Name:          "10% off",
+		Segmentation:  constants.PROMOSEGMENTATIONALL,
+		Type:          constants.PROMOTYPEPERCENTAGE,
+		DiscountValue: 10,
+	}
+	bxgyPromo := models.Promo{
+		ID:             2,
+		Name:           "buy 2 get 1",
+		Segmentation:   constants.PROMOSEGMENTATIONALL,
+		Type:           constants.PROMOTYPEBUYXGETY,
+		BuyProductID:   &buyProductId,
+		BuyProductQty:  2,
+		FreeProductID:  &freeProductId,
+		FreeProduct:    &models.Product{ID: 2, Price: 5000},
+		FreeProductQty: 1,
+		IsExclusive:    true,
+	}
+	isAvailable := true
+	withinUserLimit := true
+	promoInput := repository.GetPromoByUserCartInput{
+		Cart:            cart,
+		IsAvailable:     &isAvailable,
+		WithinUserLimit: &withinUserLimit,
+	}
+	cartInput := repository.GetUserCartInput{
+		UserId:    uint(1),
+		Relations: []string{"CartItems", "CartItems.Product"},
+	}
+
+	tests := []struct {
+		name          string
+		dto           dto.RecommendPromoInput
+		want          dto.PromoRecommendation
+		wantErr       bool
+		promoRepoMock func(*repo_mock.MockPromoRepository)
+		cartRepoMock  func(*repo_mock.MockCartRepository)
+		userRepoMock  func(*repo_mock.MockUserRepository)
+	}{
+		{
+			name:    "err get user cart",
+			dto:     dto.RecommendPromoInput{UserId: 1},
+			wantErr: true,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+			},
+			cartRepoMock: func(r *repo_mock.MockCartRepository) {
+				r.EXPECT().GetUserCart(gomock.Any(), nil, cartInput).Return(models.Cart{}, errors.New("error"))
+			},
+			userRepoMock: func(r *repo_mock.MockUserRepository) {
+			},
+		},
+		{
+			name:    "cart not found",
+			dto:     dto.RecommendPromoInput{UserId: 1},
+			wantErr: true,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+			},
+			cartRepoMock: func(r *repo_mock.MockCartRepository) {
+				r.EXPECT().GetUserCart(gomock.Any(), nil, cartInput).Return(models.Cart{}, gorm.ErrRecordNotFound)
+			},
+			userRepoMock: func(r *repo_mock.MockUserRepository) {
+			},
+		},
+		{
+			name:    "err get promo by user cart",
+			dto:     dto.RecommendPromoInput{UserId: 1},
+			wantErr: true,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoByUserCart(gomock.Any(), nil, promoInput).Return(nil, errors.New("error"))
+			},
+			cartRepoMock: func(r *repo_mock.MockCartRepository) {
+				r.EXPECT().GetUserCart(gomock.Any(), nil, cartInput).Return(cart, nil)
+			},
+			userRepoMock: func(r *repo_mock.MockUserRepository) {
+			},
+		},
+		{
+			name: "success no promo",
+			dto:  dto.RecommendPromoInput{UserId: 1},
+			want: dto.PromoRecommendation{
+				PromoIds: []uint{},
+				Quote: dto.OrderQuote{
+					Subtotal:  20000,
+					Promos:    []dto.OrderQuotePromo{},
+					FreeItems: []dto.OrderQuoteFreeItem{},
+					Total:     20000,
+				},
+			},
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoByUserCart(gomock.Any(), nil, promoInput).Return([]models.Promo{}, nil)
+			},
+			cartRepoMock: func(r *repo_mock.MockCartRepository) {
+				r.EXPECT().GetUserCart(gomock.Any(), nil, cartInput).Return(cart, nil)
+			},
+			userRepoMock: func(r *repo_mock.MockUserRepository) {
+			},
+		},
+		{
+			name: "success free item worth more than discount",
+			dto:  dto.RecommendPromoInput{UserId: 1},
+			want: dto.PromoRecommendation{
+				PromoIds: []uint{2},
+				Quote: dto.OrderQuote{
+					Subtotal: 20000,
+					Promos: []dto.OrderQuotePromo{
+						{
+							PromoId:        2,
+							Name:           "buy 2 get 1",
+							Type:           constants.PROMOTYPEBUYXGETY,
+							FreeProductId:  &freeProductId,
+							FreeProductQty: 1,
+						},
+					},
+					FreeItems: []dto.OrderQuoteFreeItem{
+						{
+							ProductId: 2,
+							Quantity:  1,
+							PromoId:   2,
+						},
+					},
+					Total: 20000,
+				},
+				FreeItemsValue: 5000,
+				Saving:         5000,
+			},
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoByUserCart(gomock.Any(), nil, promoInput).Return([]models.Promo{percentagePromo, bxgyPromo}, nil)
+			},
+			cartRepoMock: func(r *repo_mock.MockCartRepository) {
+				r.EXPECT().GetUserCart(gomock.Any(), nil, cartInput).Return(cart, nil)
+			},
+			userRepoMock: func(r *repo_mock.MockUserRepository) {
+				r.EXPECT().Get(gomock.Any(), nil, uint(1)).Return(&models.User{ID: 1}, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			transactionRepo := repo_mock.NewMockTransactionRepository(ctrl)
+
+			cartRepo := repo_mock.NewMockCartRepository(ctrl)
+			tt.cartRepoMock(cartRepo)
+
+			promoRepo := repo_mock.NewMockPromoRepository(ctrl)
+			tt.promoRepoMock(promoRepo)
+
+			userRepo := repo_mock.NewMockUserRepository(ctrl)
+			tt.userRepoMock(userRepo)
+
+			usecase := NewPromoUsecase(promoRepo, transactionRepo, cartRepo, nil, userRepo, 0)
+
+			got, err := usecase.RecommendPromos(context.Background(), tt.dto)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("promoUsecase.RecommendPromos() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("promoUsecase.RecommendPromos() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_promoUsecase_ExplainEligibility(t *testing.T) {
+	cityPromo := models.Promo{
+		ID:             1,
+		Type:           constants.PROMOTYPEPERCENTAGE,
+		Segmentation:   constants.PROMOSEGMENTATIONCITY,
+		MinOrderAmount: 20000,
+		StartDate:      time.Now().AddDate(0, 0, -1),
+		EndDate:        time.Now().AddDate(0, 0, 1),
+		Status:         constants.PROMOSTATUSAPPROVED,
+	}
+	// a whole day, but not today in the promo's timezone
+	jakarta, _ := time.LoadLocation(constants.PROMODEFAULTTIMEZONE)
+	closedSchedules := []models.PromoSchedule{
+		{PromoID: 1, DayOfWeek: (int(time.Now().In(jakarta).Weekday()) + 3) % 7, StartTime: "00:00", EndTime: "00:00"},
+	}
+	orderNumber := 3
+	nthOrderPromo := models.Promo{
+		ID:           1,
+		Type:         constants.PROMOTYPEFIXEDAMOUNT,
+		Segmentation: constants.PROMOSEGMENTATIONNTHORDER,
+		OrderNumber:  &orderNumber,
+		StartDate:    time.Now().AddDate(0, 0, -1),
+		EndDate:      time.Now().AddDate(0, 0, 1),
+		Status:       constants.PROMOSTATUSAPPROVED,
+	}
+	lastOrderAt := time.Now().AddDate(0, 0, -3)
+	cartInput := repository.GetUserCartInput{
+		UserId:    uint(1),
+		Relations: []string{"CartItems", "CartItems.Product"},
+	}
+
+	tests := []struct {
+		name          string
+		dto           dto.PromoEligibilityInput
+		wantEligible  bool
+		wantFailed    []string
+		wantErr       bool
+		promoRepoMock func(*repo_mock.MockPromoRepository)
+		userRepoMock  func(*repo_mock.MockUserRepository)
+		cartRepoMock  func(*repo_mock.MockCartRepository)
+	}{
+		{
+			name:    "promo not found",
+			dto:     dto.PromoEligibilityInput{PromoId: 1, UserId: 1},
+			wantErr: true,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoByPromoID(gomock.Any(), nil, uint(1)).Return(models.Promo{}, gorm.ErrRecordNotFound)
+			},
+			userRepoMock: func(r *repo_mock.MockUserRepository) {
+			},
+			cartRepoMock: func(r *repo_mock.MockCartRepository) {
+			},
+		},
+		{
+			name:    "user not found",
+			dto:     dto.PromoEligibilityInput{PromoId: 1, UserId: 1},
+			wantErr: true,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoByPromoID(gomock.Any(), nil, uint(1)).Return(cityPromo, nil)
+			},
+			userRepoMock: func(r *repo_mock.MockUserRepository) {
+				r.EXPECT().Get(gomock.Any(), nil, uint(1)).Return(nil, gorm.ErrRecordNotFound)
+			},
+			cartRepoMock: func(r *repo_mock.MockCartRepository) {
+			},
+		},
+		{
+			name:    "err get cities",
+			dto:     dto.PromoEligibilityInput{PromoId: 1, UserId: 1},
+			wantErr: true,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoByPromoID(gomock.Any(), nil, uint(1)).Return(cityPromo, nil)
+				r.EXPECT().GetCities(gomock.Any(), nil, uint(1)).Return(nil, errors.New("error"))
+			},
+			userRepoMock: func(r *repo_mock.MockUserRepository) {
+				r.EXPECT().Get(gomock.Any(), nil, uint(1)).Return(&models.User{ID: 1, City: "Jakarta"}, nil)
+			},
+			cartRepoMock: func(r *repo_mock.MockCartRepository) {
+			},
+		},
+		{
+			name:    "err get products",
+			dto:     dto.PromoEligibilityInput{PromoId: 1, UserId: 1},
+			wantErr: true,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoByPromoID(gomock.Any(), nil, uint(1)).Return(cityPromo, nil)
+				r.EXPECT().GetCities(gomock.Any(), nil, uint(1)).Return([]models.PromoCity{{City: "Jakarta"}}, nil)
+				r.EXPECT().GetProducts(gomock.Any(), nil, uint(1)).Return(nil, errors.New("error"))
+			},
+			userRepoMock: func(r *repo_mock.MockUserRepository) {
+				r.EXPECT().Get(gomock.Any(), nil, uint(1)).Return(&models.User{ID: 1, City: "Jakarta"}, nil)
+			},
+			cartRepoMock: func(r *repo_mock.MockCartRepository) {
+			},
+		},
+		{
+			name:    "err get schedules",
+			dto:     dto.PromoEligibilityInput{PromoId: 1, UserId: 1},
+			wantErr: true,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoByPromoID(gomock.Any(), nil, uint(1)).Return(cityPromo, nil)
+				r.EXPECT().GetCities(gomock.Any(), nil, uint(1)).Return([]models.PromoCity{{City: "Jakarta"}}, nil)
+				r.EXPECT().GetProducts(gomock.Any(), nil, uint(1)).Return(nil, nil)
+				r.EXPECT().GetSchedules(gomock.Any(), nil, uint(1)).Return(nil, errors.New("error"))
+			},
+			userRepoMock: func(r *repo_mock.MockUserRepository) {
+				r.EXPECT().Get(gomock.Any(), nil, uint(1)).Return(&models.User{ID: 1, City: "Jakarta"}, nil)
+			},
+			cartRepoMock: func(r *repo_mock.MockCartRepository) {
+			},
+		},
+		{
+			name:    "err get user usage count",
+			dto:     dto.PromoEligibilityInput{PromoId: 1, UserId: 1},
+			wantErr: true,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoByPromoID(gomock.Any(), nil, uint(1)).Return(cityPromo, nil)
+				r.EXPECT().GetCities(gomock.Any(), nil, uint(1)).Return([]models.PromoCity{{City: "Jakarta"}}, nil)
+				r.EXPECT().GetProducts(gomock.Any(), nil, uint(1)).Return(nil, nil)
+				r.EXPECT().GetSchedules(gomock.Any(), nil, uint(1)).Return(nil, nil)
+				r.EXPECT().GetUserUsageCount(gomock.Any(), nil, uint(1), uint(1)).Return(0, errors.New("error"))
+			},
+			userRepoMock: func(r *repo_mock.MockUserRepository) {
+				r.EXPECT().Get(gomock.Any(), nil, uint(1)).Return(&models.User{ID: 1, City: "Jakarta"}, nil)
+			},
+			cartRepoMock: func(r *repo_mock.MockCartRepository) {
+				r.EXPECT().GetUserCart(gomock.Any(), nil, cartInput).Return(models.Cart{}, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name:         "success not eligible",
+			dto:          dto.PromoEligibilityInput{PromoId: 1, UserId: 1},
+			wantEligible: false,
+			wantFailed:   []string{"SCHEDULE", "MIN_ORDER_AMOUNT", "CITY"},
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoByPromoID(gomock.Any(), nil, uint(1)).Return(cityPromo, nil)
+				r.EXPECT().GetCities(gomock.Any(), nil, uint(1)).Return([]models.PromoCity{{City: "Jakarta"}}, nil)
+				r.EXPECT().GetProducts(gomock.Any(), nil, uint(1)).Return(nil, nil)
+				r.EXPECT().GetSchedules(gomock.Any(), nil, uint(1)).Return(closedSchedules, nil)
+				r.EXPECT().GetUserUsageCount(gomock.Any(), nil, uint(1), uint(1)).Return(0, nil)
+			},
+			userRepoMock: func(r *repo_mock.MockUserRepository) {
+				r.EXPECT().Get(gomock.Any(), nil, uint(1)).Return(&models.User{ID: 1, City: "Bandung"}, nil)
+			},
+			cartRepoMock: func(r *repo_mock.MockCartRepository) {
+				r.EXPECT().GetUserCart(gomock.Any(), nil, cartInput).Return(models.Cart{}, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name:         "success eligible",
+			dto:          dto.PromoEligibilityInput{PromoId: 1, UserId: 1},
+			wantEligible: true,
+			wantFailed:   []string{},
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoByPromoID(gomock.Any(), nil, uint(1)).Return(cityPromo, nil)
+				r.EXPECT().GetCities(gomock.Any(), nil, uint(1)).Return([]models.PromoCity{{City: "Jakarta"}}, nil)
+				r.EXPECT().GetProducts(gomock.Any(), nil, uint(1)).Return([]models.PromoProduct{{PromoID: 1, ProductID: 1}}, nil)
+				r.EXPECT().GetSchedules(gomock.Any(), nil, uint(1)).Return(nil, nil)
+				r.EXPECT().GetUserUsageCount(gomock.Any(), nil, uint(1), uint(1)).Return(0, nil)
+			},
+			userRepoMock: func(r *repo_mock.MockUserRepository) {
+				r.EXPECT().Get(gomock.Any(), nil, uint(1)).Return(&models.User{ID: 1, City: "jakarta"}, nil)
+			},
+			cartRepoMock: func(r *repo_mock.MockCartRepository) {
+				r.EXPECT().GetUserCart(gomock.Any(), nil, cartInput).Return(models.Cart{
+					ID:     1,
+					UserID: 1,
+					CartItems: []models.CartItem{
+						{ProductID: 1, Quantity: 2, Product: &models.Product{ID: 1, Price: 10000}},
+					},
+				}, nil)
+			},
+		},
+		{
+			name:    "err get order history",
+			dto:     dto.PromoEligibilityInput{PromoId: 1, UserId: 1},
+			wantErr: true,
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoByPromoID(gomock.Any(), nil, uint(1)).Return(nthOrderPromo, nil)
+				r.EXPECT().GetSchedules(gomock.Any(), nil, uint(1)).Return(nil, nil)
+				r.EXPECT().GetUserUsageCount(gomock.Any(), nil, uint(1), uint(1)).Return(0, nil)
+			},
+			userRepoMock: func(r *repo_mock.MockUserRepository) {
+				r.EXPECT().Get(gomock.Any(), nil, uint(1)).Return(&models.User{ID: 1}, nil)
+				r.EXPECT().GetOrderHistory(gomock.Any(), nil, uint(1)).Return(repository.UserOrderHistory{}, errors.New("error"))
+			},
+			cartRepoMock: func(r *repo_mock.MockCartRepository) {
+				r.EXPECT().GetUserCart(gomock.Any(), nil, cartInput).Return(models.Cart{}, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name:         "success nth order not reached",
+			dto:          dto.PromoEligibilityInput{PromoId: 1, UserId: 1},
+			wantEligible: false,
+			wantFailed:   []string{"NTH_ORDER"},
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoByPromoID(gomock.Any(), nil, uint(1)).Return(nthOrderPromo, nil)
+				r.EXPECT().GetSchedules(gomock.Any(), nil, uint(1)).Return(nil, nil)
+				r.EXPECT().GetUserUsageCount(gomock.Any(), nil, uint(1), uint(1)).Return(0, nil)
+			},
+			userRepoMock: func(r *repo_mock.MockUserRepository) {
+				r.EXPECT().Get(gomock.Any(), nil, uint(1)).Return(&models.User{ID: 1}, nil)
+				r.EXPECT().GetOrderHistory(gomock.Any(), nil, uint(1)).Return(repository.UserOrderHistory{OrderCount: 1, LastOrderAt: &lastOrderAt}, nil)
+			},
+			cartRepoMock: func(r *repo_mock.MockCartRepository) {
+				r.EXPECT().GetUserCart(gomock.Any(), nil, cartInput).Return(models.Cart{}, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name:         "success nth order",
+			dto:          dto.PromoEligibilityInput{PromoId: 1, UserId: 1},
+			wantEligible: true,
+			wantFailed:   []string{},
+			promoRepoMock: func(r *repo_mock.MockPromoRepository) {
+				r.EXPECT().GetPromoByPromoID(gomock.Any(), nil, uint(1)).Return(nthOrderPromo, nil)
+				r.EXPECT().GetSchedules(gomock.Any(), nil, uint(1)).Return(nil, nil)
+				r.EXPECT().GetUserUsageCount(gomock.Any(), nil, uint(1), uint(1)).Return(0, nil)
+			},
+			userRepoMock: func(r *repo_mock.MockUserRepository) {
+				r.EXPECT().Get(gomock.Any(), nil, uint(1)).Return(&models.User{ID: 1}, nil)
+				r.EXPECT().GetOrderHistory(gomock.Any(), nil, uint(1)).Return(repository.UserOrderHistory{OrderCount: 2, LastOrderAt: &lastOrderAt}, nil)
+			},
+			cartRepoMock: func(r *repo_mock.MockCartRepository) {
+				r.EXPECT().GetUserCart(gomock.Any(), nil, cartInput).Return(models.Cart{}, gorm.ErrRecordNotFound)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			cartRepo := repo_mock.NewMockCartRepository(ctrl)
+			tt.cartRepoMock(cartRepo)
+
+			userRepo := repo_mock.NewMockUserRepository(ctrl)
+			tt.userRepoMock(userRepo)
+
+			promoRepo := repo_mock.NewMockPromoRepository(ctrl)
+			tt.promoRepoMock(promoRepo)
+
+			usecase := NewPromoUsecase(promoRepo, nil, cartRepo, nil, userRepo, 0)
+
+			got, err := usecase.ExplainEligibility(context.Background(), tt.dto)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("promoUsecase.ExplainEligibility() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got.Eligible != tt.wantEligible {
+				t.Errorf("promoUsecase.ExplainEligibility() eligible = %v, want %v", got.Eligible, tt.wantEligible)
+			}
+
+			failed := []string{}
+			for _, check := range got.Checks {
+				if !check.Passed {
+					failed = append(failed, check.Name)
+				}
+			}
+			if !reflect.DeepEqual(failed, tt.wantFailed) {
+				t.Errorf("promoUsecase.ExplainEligibility() failed checks = %v, want %v", failed, tt.wantFailed)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"hangry/domain/dto"
+	"hangry/domain/models"
+	"hangry/repository"
+	"hangry/utils"
+	"math/big"
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+// voucherAlphabet leaves out characters that are easy to misread when typed,
+// like O and 0 or I and 1.
+const voucherAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+const (
+	voucherCodeLength = 8
+	// maxVoucherAttempts bounds the retries when generated codes collide with
+	// existing ones.
+	maxVoucherAttempts = 5
+)
+
+//go:generate mockgen -source=./voucher.go -destination=./mocks/mock_voucher.go -package=mocks
+type VoucherUsecase interface {
+	GenerateVouchers(ctx context.Context, dto dto.GenerateVoucherInput) (int, error)
+	ExportVouchers(ctx context.Context, promoID uint) ([]models.PromoVoucher, error)
+}
+
+type voucherUsecase struct {
+	transactionRepository repository.TransactionRepository
+	promoRepository       repository.PromoRepository
+	voucherRepository     repository.VoucherRepository
+	// generateCode is swapped in tests to produce predictable codes
+	generateCode func(prefix string) (string, error)
+}
+
+func generateVoucherCode(prefix string) (string, error) {
+	code := make([]byte, voucherCodeLength)
+	max := big.NewInt(int64(len(voucherAlphabet)))
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = voucherAlphabet[n.Int64()]
+	}
+
+	return prefix + string(code), nil
+}
+
+// GenerateVouchers implements VoucherUsecase.
+func (v *voucherUsecase) GenerateVouchers(ctx context.Context, input dto.GenerateVoucherInput) (int, error) {
+	created := 0
+
+	err := v.transactionRepository.Execute(ctx, func(tx *gorm.DB) error {
+		promo, err := v.promoRepository.GetPromoByPromoID(ctx, tx, input.PromoId)
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+		}
+
+		if promo.ID == 0 {
+			return utils.NewCustomError("promo not found", nil, http.StatusNotFound)
+		}
+
+		for attempt := 0; attempt < maxVoucherAttempts && created < input.Count; attempt++ {
+			vouchers := make([]models.PromoVoucher, 0, input.Count-created)
+			for i := created; i < input.Count; i++ {
+				code, err := v.generateCode(input.Prefix)
+				if err != nil {
+					return utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+				}
+
+				vouchers = append(vouchers, models.PromoVoucher{
+					PromoID: promo.ID,
+					Code:    code,
+				})
+			}
+
+			inserted, err := v.voucherRepository.CreateVouchers(ctx, tx, vouchers)
+			if err != nil {
+				return utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+			}
+
+			created += int(inserted)
+		}
+
+		if created < input.Count {
+			return utils.NewCustomError("failed to generate unique voucher codes, try a longer prefix", nil, http.StatusInternalServerError)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return created, nil
+}
+
+// ExportVouchers implements VoucherUsecase.
+func (v *voucherUsecase) ExportVouchers(ctx context.Context, promoID uint) ([]models.PromoVoucher, error) {
+	promo, err := v.promoRepository.GetPromoByPromoID(ctx, nil, promoID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+	}
+
+	if promo.ID == 0 {
+		return nil, utils.NewCustomError("promo not found", nil, http.StatusNotFound)
+	}
+
+	vouchers, err := v.voucherRepository.GetVouchersByPromoID(ctx, nil, promoID)
+	if err != nil {
+		return nil, utils.NewCustomError(err.Error(), nil, http.StatusInternalServerError)
+	}
+
+	return vouchers, nil
+}
+
+func NewVoucherUsecase(
+	transactionRepository repository.TransactionRepository,
+	promoRepository repository.PromoRepository,
+	voucherRepository repository.VoucherRepository,
+) VoucherUsecase {
+	return &voucherUsecase{
+		transactionRepository: transactionRepository,
+		promoRepository:       promoRepository,
+		voucherRepository:     voucherRepository,
+		generateCode:          generateVoucherCode,
+	}
+}
@@ -0,0 +1,248 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hangry/domain/dto"
+	"hangry/domain/models"
+	repo_mock "hangry/repository/mocks"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func Test_voucherUsecase_GenerateVouchers(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		dto dto.GenerateVoucherInput
+	}
+	tests := []struct {
+		name     string
+		args     args
+		want     int
+		wantErr  bool
+		mockRepo func(
+			promo *repo_mock.MockPromoRepository,
+			voucher *repo_mock.MockVoucherRepository,
+		)
+	}{
+		{
+			name: "err get promo",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.GenerateVoucherInput{
+					PromoId: 1,
+					Count:   2,
+					Prefix:  "P",
+				},
+			},
+			want:    0,
+			wantErr: true,
+			mockRepo: func(promo *repo_mock.MockPromoRepository, voucher *repo_mock.MockVoucherRepository) {
+				promo.EXPECT().GetPromoByPromoID(gomock.Any(), nil, uint(1)).Return(models.Promo{}, errors.New("error"))
+			},
+		},
+		{
+			name: "promo not found",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.GenerateVoucherInput{
+					PromoId: 1,
+					Count:   2,
+					Prefix:  "P",
+				},
+			},
+			want:    0,
+			wantErr: true,
+			mockRepo: func(promo *repo_mock.MockPromoRepository, voucher *repo_mock.MockVoucherRepository) {
+				promo.EXPECT().GetPromoByPromoID(gomock.Any(), nil, uint(1)).Return(models.Promo{}, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name: "err create vouchers",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.GenerateVoucherInput{
+					PromoId: 1,
+					Count:   2,
+					Prefix:  "P",
+				},
+			},
+			want:    0,
+			wantErr: true,
+			mockRepo: func(promo *repo_mock.MockPromoRepository, voucher *repo_mock.MockVoucherRepository) {
+				promo.EXPECT().GetPromoByPromoID(gomock.Any(), nil, uint(1)).Return(models.Promo{ID: 1}, nil)
+				voucher.EXPECT().CreateVouchers(gomock.Any(), nil, gomock.Any()).Return(int64(0), errors.New("error"))
+			},
+		},
+		{
+			name: "codes keep colliding",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.GenerateVoucherInput{
+					PromoId: 1,
+					Count:   2,
+					Prefix:  "P",
+				},
+			},
+			want:    0,
+			wantErr: true,
+			mockRepo: func(promo *repo_mock.MockPromoRepository, voucher *repo_mock.MockVoucherRepository) {
+				promo.EXPECT().GetPromoByPromoID(gomock.Any(), nil, uint(1)).Return(models.Promo{ID: 1}, nil)
+				voucher.EXPECT().CreateVouchers(gomock.Any(), nil, gomock.Any()).Return(int64(0), nil).Times(maxVoucherAttempts)
+			},
+		},
+		{
+			name: "success after collision",
+			args: args{
+				ctx: context.Background(),
+				dto: dto.GenerateVoucherInput{
+					PromoId: 1,
+					Count:   2,
+					Prefix:  "P",
+				},
+			},
+			want:    2,
+			wantErr: false,
+			mockRepo: func(promo *repo_mock.MockPromoRepository, voucher *repo_mock.MockVoucherRepository) {
+				promo.EXPECT().GetPromoByPromoID(gomock.Any(), nil, uint(1)).Return(models.Promo{ID: 1}, nil)
+				gomock.InOrder(
+					voucher.EXPECT().CreateVouchers(gomock.Any(), nil, []models.PromoVoucher{
+						{PromoID: 1, Code: "P1"},
+						{PromoID: 1, Code: "P2"},
+					}).Return(int64(1), nil),
+					// only the missing voucher is generated again
+					voucher.EXPECT().CreateVouchers(gomock.Any(), nil, []models.PromoVoucher{
+						{PromoID: 1, Code: "P3"},
+					}).Return(int64(1), nil),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			transactionRepo := repo_mock.NewMockTransactionRepository(ctrl)
+			transactionRepo.EXPECT().Execute(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, fn func(tx *gorm.DB) error) error {
+					return fn(nil)
+				})
+			promoRepo := repo_mock.NewMockPromoRepository(ctrl)
+			voucherRepo := repo_mock.NewMockVoucherRepository(ctrl)
+
+			tt.mockRepo(promoRepo, voucherRepo)
+
+			usecase := NewVoucherUsecase(transactionRepo, promoRepo, voucherRepo).(*voucherUsecase)
+			sequence := 0
+			usecase.generateCode = func(prefix string) (string, error) {
+				sequence++
+				return fmt.Sprintf("%s%d", prefix, sequence), nil
+			}
+
+			got, err := usecase.GenerateVouchers(tt.args.ctx, tt.args.dto)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("voucherUsecase.GenerateVouchers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("voucherUsecase.GenerateVouchers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_voucherUsecase_ExportVouchers(t *testing.T) {
+	vouchers := []models.PromoVoucher{
+		{ID: 1, PromoID: 1, Code: "P1"},
+		{ID: 2, PromoID: 1, Code: "P2"},
+	}
+
+	tests := []struct {
+		name     string
+		promoID  uint
+		want     []models.PromoVoucher
+		wantErr  bool
+		mockRepo func(
+			promo *repo_mock.MockPromoRepository,
+			voucher *repo_mock.MockVoucherRepository,
+		)
+	}{
+		{
+			name:    "promo not found",
+			promoID: 1,
+			want:    nil,
+			wantErr: true,
+			mockRepo: func(promo *repo_mock.MockPromoRepository, voucher *repo_mock.MockVoucherRepository) {
+				promo.EXPECT().GetPromoByPromoID(gomock.Any(), nil, uint(1)).Return(models.Promo{}, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name:    "err get vouchers",
+			promoID: 1,
+			want:    nil,
+			wantErr: true,
+			mockRepo: func(promo *repo_mock.MockPromoRepository, voucher *repo_mock.MockVoucherRepository) {
+				promo.EXPECT().GetPromoByPromoID(gomock.Any(), nil, uint(1)).Return(models.Promo{ID: 1}, nil)
+				voucher.EXPECT().GetVouchersByPromoID(gomock.Any(), nil, uint(1)).Return(nil, errors.New("error"))
+			},
+		},
+		{
+			name:    "success",
+			promoID: 1,
+			want:    vouchers,
+			wantErr: false,
+			mockRepo: func(promo *repo_mock.MockPromoRepository, voucher *repo_mock.MockVoucherRepository) {
+				promo.EXPECT().GetPromoByPromoID(gomock.Any(), nil, uint(1)).Return(models.Promo{ID: 1}, nil)
+				voucher.EXPECT().GetVouchersByPromoID(gomock.Any(), nil, uint(1)).Return(vouchers, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			promoRepo := repo_mock.NewMockPromoRepository(ctrl)
+			voucherRepo := repo_mock.NewMockVoucherRepository(ctrl)
+
+			tt.mockRepo(promoRepo, voucherRepo)
+
+			usecase := NewVoucherUsecase(nil, promoRepo, voucherRepo)
+
+			got, err := usecase.ExportVouchers(context.Background(), tt.promoID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("voucherUsecase.ExportVouchers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("voucherUsecase.ExportVouchers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_generateVoucherCode(t *testing.T) {
+	code, err := generateVoucherCode("PARTNER")
+	if err != nil {
+		t.Fatalf("generateVoucherCode() error = %v", err)
+	}
+
+	random := strings.TrimPrefix(code, "PARTNER")
+	if random == code || len(random) != voucherCodeLength {
+		t.Fatalf("generateVoucherCode() = %v, want prefix and %d random characters", code, voucherCodeLength)
+	}
+
+	for _, c := range random {
+		if !strings.ContainsRune(voucherAlphabet, c) {
+			t.Errorf("generateVoucherCode() = %v, %q is not in the voucher alphabet", code, c)
+		}
+	}
+}
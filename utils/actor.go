@@ -0,0 +1,20 @@
+package utils
+
+import "context"
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx telling who makes the changes done with it.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// Actor returns who makes the changes done with ctx, nil when nobody said.
+func Actor(ctx context.Context) *string {
+	actor, ok := ctx.Value(actorKey{}).(string)
+	if !ok || actor == "" {
+		return nil
+	}
+
+	return &actor
+}
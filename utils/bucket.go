@@ -0,0 +1,16 @@
+package utils
+
+import "hash/fnv"
+
+// Bucket places the key in one of n buckets, numbered from 0. The same key
+// always lands in the same bucket, across requests and restarts.
+func Bucket(key string, n int) int {
+	if n <= 0 {
+		return 0
+	}
+
+	hash := fnv.New32a()
+	hash.Write([]byte(key))
+
+	return int(hash.Sum32() % uint32(n))
+}
@@ -0,0 +1,27 @@
+package utils
+
+import "strings"
+
+// NormalizeCity trims the city name and collapses the spaces inside it, so
+// cities typed by admins and users compare equal ignoring case.
+func NormalizeCity(city string) string {
+	return strings.Join(strings.Fields(city), " ")
+}
+
+// NormalizeCities normalizes the cities and drops the empty ones and the ones
+// repeated ignoring case, keeping the first spelling.
+func NormalizeCities(cities []string) []string {
+	normalized := make([]string, 0, len(cities))
+	seen := map[string]bool{}
+	for _, city := range cities {
+		city = NormalizeCity(city)
+		key := strings.ToLower(city)
+		if city == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, city)
+	}
+
+	return normalized
+}